@@ -0,0 +1,369 @@
+package bamboohr
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Cached resources, used as keys of the TTLs given to NewCache
+const (
+	CacheDirectory       = "directory"
+	CacheEmployee        = "employee"
+	CacheTimeOffTypes    = "time_off_types"
+	CacheTimeOffPolicies = "time_off_policies"
+	CacheBalances        = "balances"
+	CacheRequests        = "requests"
+	CacheWhosOut         = "whos_out"
+)
+
+// DefaultCacheTTLs are the cache lifetimes of each resource. Company metadata rarely changes,
+// while balances and requests change whenever time off is booked or approved.
+var DefaultCacheTTLs = map[string]time.Duration{
+	CacheDirectory:       15 * time.Minute,
+	CacheEmployee:        15 * time.Minute,
+	CacheTimeOffTypes:    24 * time.Hour,
+	CacheTimeOffPolicies: 24 * time.Hour,
+	CacheBalances:        5 * time.Minute,
+	CacheRequests:        time.Minute,
+	CacheWhosOut:         5 * time.Minute,
+}
+
+// cacheResources maps API paths to the cached resource they return. Paths are matched in order.
+var cacheResources = []struct {
+	pattern  *regexp.Regexp
+	resource string
+}{
+	{regexp.MustCompile(`/employees/directory$`), CacheDirectory},
+	{regexp.MustCompile(`/employees/\d+/time_off/calculator$`), CacheBalances},
+	{regexp.MustCompile(`/employees/\d+/time_off/policies$`), CacheTimeOffPolicies},
+	{regexp.MustCompile(`/employees/\d+$`), CacheEmployee},
+	{regexp.MustCompile(`/meta/time_off/types$`), CacheTimeOffTypes},
+	{regexp.MustCompile(`/meta/time_off/policies$`), CacheTimeOffPolicies},
+	{regexp.MustCompile(`/time_off/requests$`), CacheRequests},
+	{regexp.MustCompile(`/time_off/whos_out$`), CacheWhosOut},
+}
+
+// cacheSaveDelay is how long changes are batched before the cache file is rewritten
+const cacheSaveDelay = 2 * time.Second
+
+// timeOffResources are the resources invalidated by a time-off write
+var timeOffResources = []string{CacheBalances, CacheRequests, CacheWhosOut}
+
+// Cache caches GET responses from the BambooHR API, keyed by endpoint and caller. Stale entries
+// are revalidated with If-None-Match and If-Modified-Since when BambooHR returned an ETag or
+// Last-Modified header. Successful writes invalidate the caller's affected resources.
+// Entries that can no longer be served or revalidated are pruned as new ones are stored.
+type Cache struct {
+	ttls map[string]time.Duration
+	path string
+	now  func() time.Time
+
+	mu        sync.Mutex
+	entries   map[string]cacheEntry
+	saveTimer *time.Timer
+
+	// saveMu serializes writes of the cache file, which happen without holding mu
+	saveMu sync.Mutex
+}
+
+// cacheEntry is a cached response body and its validators
+type cacheEntry struct {
+	Caller       string    `json:"caller"`
+	Resource     string    `json:"resource"`
+	Body         []byte    `json:"body"`
+	ETag         string    `json:"etag,omitempty"`
+	LastModified string    `json:"lastModified,omitempty"`
+	Expires      time.Time `json:"expires"`
+}
+
+// NewCache creates an in-memory cache with the given TTLs per resource. Resources without a
+// positive TTL are not cached.
+func NewCache(ttls map[string]time.Duration) *Cache {
+	return &Cache{
+		ttls:    ttls,
+		now:     time.Now,
+		entries: make(map[string]cacheEntry),
+	}
+}
+
+// OpenCache creates a cache that is persisted to the file at path, loading any entries saved
+// by a previous run. Changes are written to the file in the background a few seconds after they
+// are made; call Flush before exiting to write the latest ones.
+func OpenCache(path string, ttls map[string]time.Duration) (*Cache, error) {
+	cache := NewCache(ttls)
+	cache.path = path
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return cache, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading cache file: %w", err)
+	}
+
+	if err := json.Unmarshal(data, &cache.entries); err != nil {
+		return nil, fmt.Errorf("decoding cache file: %w", err)
+	}
+	cache.prune()
+
+	return cache, nil
+}
+
+// ParseCacheTTLs parses per-resource TTL overrides in the form "directory=1h,balances=0"
+// and applies them to a copy of the defaults
+func ParseCacheTTLs(value string, defaults map[string]time.Duration) (map[string]time.Duration, error) {
+	ttls := make(map[string]time.Duration, len(defaults))
+	for resource, ttl := range defaults {
+		ttls[resource] = ttl
+	}
+
+	for _, entry := range strings.Split(value, ",") {
+		if strings.TrimSpace(entry) == "" {
+			continue
+		}
+
+		resource, duration, ok := strings.Cut(entry, "=")
+		resource = strings.TrimSpace(resource)
+		if !ok {
+			return nil, fmt.Errorf("invalid cache TTL %q: expected resource=duration", strings.TrimSpace(entry))
+		}
+		if _, known := defaults[resource]; !known {
+			return nil, fmt.Errorf("unknown cache resource %q", resource)
+		}
+
+		ttl, err := time.ParseDuration(strings.TrimSpace(duration))
+		if err != nil {
+			return nil, fmt.Errorf("invalid cache TTL for %s: %w", resource, err)
+		}
+		ttls[resource] = ttl
+	}
+
+	return ttls, nil
+}
+
+// Clear removes every cached response
+func (c *Cache) Clear() error {
+	c.mu.Lock()
+	c.entries = make(map[string]cacheEntry)
+	c.mu.Unlock()
+
+	return c.Flush()
+}
+
+// Flush writes the entries to the cache file, if any, without waiting for pending changes to be
+// saved in the background
+func (c *Cache) Flush() error {
+	if c.path == "" {
+		return nil
+	}
+
+	c.saveMu.Lock()
+	defer c.saveMu.Unlock()
+
+	c.mu.Lock()
+	if c.saveTimer != nil {
+		c.saveTimer.Stop()
+		c.saveTimer = nil
+	}
+	data, err := json.Marshal(c.entries)
+	c.mu.Unlock()
+
+	if err != nil {
+		return fmt.Errorf("encoding cache file: %w", err)
+	}
+	return writeCacheFile(c.path, data)
+}
+
+// cacheResource returns the cached resource served by the URL path, if any
+func cacheResource(path string) string {
+	for _, r := range cacheResources {
+		if r.pattern.MatchString(path) {
+			return r.resource
+		}
+	}
+	return ""
+}
+
+// cacheCaller identifies the caller by a hash of its credentials, so that responses are never
+// shared between API keys and the key itself is not persisted
+func cacheCaller(apiKey string) string {
+	sum := sha256.Sum256([]byte(apiKey))
+	return hex.EncodeToString(sum[:8])
+}
+
+// do performs the request through the cache
+func (c *Cache) do(client *http.Client, req *http.Request, caller string) (*http.Response, error) {
+	if req.Method != http.MethodGet {
+		resp, err := client.Do(req)
+		if err == nil && resp.StatusCode >= 200 && resp.StatusCode < 300 {
+			c.invalidate(caller, req.URL.Path)
+		}
+		return resp, err
+	}
+
+	resource := cacheResource(req.URL.Path)
+	ttl := c.ttls[resource]
+	if resource == "" || ttl <= 0 {
+		return client.Do(req)
+	}
+
+	key := caller + " " + req.URL.String()
+
+	c.mu.Lock()
+	entry, cached := c.entries[key]
+	c.mu.Unlock()
+
+	if cached && c.now().Before(entry.Expires) {
+		return cachedResponse(req, entry.Body), nil
+	}
+
+	if cached {
+		if entry.ETag != "" {
+			req.Header.Set("If-None-Match", entry.ETag)
+		}
+		if entry.LastModified != "" {
+			req.Header.Set("If-Modified-Since", entry.LastModified)
+		}
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case cached && resp.StatusCode == http.StatusNotModified:
+		resp.Body.Close()
+		entry.Expires = c.now().Add(ttl)
+		c.store(key, entry)
+		return cachedResponse(req, entry.Body), nil
+
+	case resp.StatusCode == http.StatusOK:
+		body, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("reading response body: %w", err)
+		}
+
+		c.store(key, cacheEntry{
+			Caller:       caller,
+			Resource:     resource,
+			Body:         body,
+			ETag:         resp.Header.Get("ETag"),
+			LastModified: resp.Header.Get("Last-Modified"),
+			Expires:      c.now().Add(ttl),
+		})
+
+		resp.Body = io.NopCloser(bytes.NewReader(body))
+		return resp, nil
+	}
+
+	return resp, nil
+}
+
+// cachedResponse returns a successful response with the cached body
+func cachedResponse(req *http.Request, body []byte) *http.Response {
+	return &http.Response{
+		Status:        "200 OK",
+		StatusCode:    http.StatusOK,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        http.Header{"Content-Type": []string{"application/json"}},
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}
+}
+
+// store saves the entry, pruning those that can no longer be used
+func (c *Cache) store(key string, entry cacheEntry) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.prune()
+	c.entries[key] = entry
+	c.scheduleSave()
+}
+
+// prune removes the entries that can no longer be served or revalidated: those of resources
+// that are no longer cached, those expired without a validator, and those expired for longer
+// than their TTL. The caller must hold c.mu.
+func (c *Cache) prune() {
+	now := c.now()
+	for key, entry := range c.entries {
+		ttl := c.ttls[entry.Resource]
+		expired := !now.Before(entry.Expires)
+		revalidatable := entry.ETag != "" || entry.LastModified != ""
+		if ttl <= 0 || (expired && (!revalidatable || now.After(entry.Expires.Add(ttl)))) {
+			delete(c.entries, key)
+		}
+	}
+}
+
+// scheduleSave writes the entries to the cache file after cacheSaveDelay, batching the changes
+// made until then. Persistence errors are ignored, the in-memory cache still applies. The caller
+// must hold c.mu.
+func (c *Cache) scheduleSave() {
+	if c.path == "" || c.saveTimer != nil {
+		return
+	}
+	c.saveTimer = time.AfterFunc(cacheSaveDelay, func() { _ = c.Flush() })
+}
+
+// invalidate removes the caller's cached resources affected by a write to the path.
+// Time-off writes invalidate balances, requests and who's out; other writes invalidate everything.
+func (c *Cache) invalidate(caller, path string) {
+	resources := map[string]bool{}
+	if strings.Contains(path, "/time_off/") {
+		for _, resource := range timeOffResources {
+			resources[resource] = true
+		}
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for key, entry := range c.entries {
+		if entry.Caller == caller && (len(resources) == 0 || resources[entry.Resource]) {
+			delete(c.entries, key)
+		}
+	}
+	c.scheduleSave()
+}
+
+// writeCacheFile replaces the cache file at path with data
+func writeCacheFile(path string, data []byte) error {
+	// Write to a temporary file first so that a crash never leaves a truncated cache
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*")
+	if err != nil {
+		return fmt.Errorf("writing cache file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("writing cache file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("writing cache file: %w", err)
+	}
+
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("writing cache file: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,320 @@
+package bamboohr
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// newCachedTestClient creates a client backed by the handler with a cache whose clock can be advanced
+func newCachedTestClient(t *testing.T, handler http.HandlerFunc) (*Client, *time.Time) {
+	t.Helper()
+
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	now := time.Date(2025, 12, 1, 9, 0, 0, 0, time.UTC)
+	cache := NewCache(DefaultCacheTTLs)
+	cache.now = func() time.Time { return now }
+
+	client := NewClient("testcompany", "testkey")
+	client.BaseURL = server.URL
+	client.V1BaseURL = server.URL
+	client.Cache = cache
+
+	return client, &now
+}
+
+func TestCache_DirectoryTTL(t *testing.T) {
+	requests := 0
+	client, now := newCachedTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Write([]byte(`{"fields": [], "employees": [{"id": "4", "displayName": "Charlotte Abbott"}]}`))
+	})
+
+	for i := 0; i < 3; i++ {
+		directory, err := client.GetEmployeeDirectory()
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if len(directory.Employees) != 1 {
+			t.Errorf("Expected 1 employee, got %d", len(directory.Employees))
+		}
+	}
+
+	if requests != 1 {
+		t.Errorf("Expected 1 request within the TTL, got %d", requests)
+	}
+
+	*now = now.Add(DefaultCacheTTLs[CacheDirectory] + time.Second)
+	if _, err := client.GetEmployeeDirectory(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if requests != 2 {
+		t.Errorf("Expected the directory to be fetched again after the TTL, got %d requests", requests)
+	}
+}
+
+func TestCache_Revalidation(t *testing.T) {
+	requests, notModified := 0, 0
+	client, now := newCachedTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.Header.Get("If-None-Match") == `"v1"` && r.Header.Get("If-Modified-Since") == "Mon, 01 Dec 2025 08:00:00 GMT" {
+			notModified++
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+		w.Header().Set("Last-Modified", "Mon, 01 Dec 2025 08:00:00 GMT")
+		w.Write([]byte(`{"timeOffTypes": [{"id": "78", "name": "Vacation", "units": "days"}], "defaultHours": []}`))
+	})
+
+	if _, err := client.GetTimeOffTypes(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	*now = now.Add(DefaultCacheTTLs[CacheTimeOffTypes] + time.Second)
+	types, err := client.GetTimeOffTypes()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if requests != 2 || notModified != 1 {
+		t.Errorf("Expected a conditional request answered with 304, got %d requests and %d not modified", requests, notModified)
+	}
+
+	if len(types.TimeOffTypes) != 1 || types.TimeOffTypes[0].Name != "Vacation" {
+		t.Errorf("Expected the cached types after revalidation, got %+v", types.TimeOffTypes)
+	}
+
+	// The revalidated entry is fresh for another TTL
+	if _, err := client.GetTimeOffTypes(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if requests != 2 {
+		t.Errorf("Expected the revalidated entry to be served from the cache, got %d requests", requests)
+	}
+}
+
+func TestCache_InvalidatedByWrite(t *testing.T) {
+	balanceRequests := 0
+	client, _ := newCachedTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodPut:
+			w.Write([]byte(`{"id": "1", "employeeId": "157"}`))
+		default:
+			if r.URL.Path == "/employees/157/time_off/calculator" {
+				balanceRequests++
+				w.Write([]byte(`[{"timeOffType": "78", "name": "Vacation", "balance": "10"}]`))
+				return
+			}
+			w.Write([]byte(`{"fields": [], "employees": []}`))
+		}
+	})
+
+	client.GetTimeOffBalance(157)
+	client.GetEmployeeDirectory()
+	client.GetTimeOffBalance(157)
+	if balanceRequests != 1 {
+		t.Fatalf("Expected the balance to be cached, got %d requests", balanceRequests)
+	}
+
+	if _, err := client.CreateTimeOffRequest(157, TimeOffRequestCreate{Status: "requested", Start: "2025-12-22", End: "2025-12-22", TimeOffTypeID: 78, Amount: 1}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	client.GetTimeOffBalance(157)
+	if balanceRequests != 2 {
+		t.Errorf("Expected the balance to be fetched again after a write, got %d requests", balanceRequests)
+	}
+
+	client.Cache.mu.Lock()
+	entries := len(client.Cache.entries)
+	client.Cache.mu.Unlock()
+	if entries != 2 {
+		t.Errorf("Expected the directory to stay cached alongside the new balance, got %d entries", entries)
+	}
+}
+
+func TestCache_KeyedByCaller(t *testing.T) {
+	requests := 0
+	client, _ := newCachedTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Write([]byte(`{"fields": [], "employees": []}`))
+	})
+
+	other := NewClient("testcompany", "otherkey")
+	other.BaseURL = client.BaseURL
+	other.Cache = client.Cache
+
+	client.GetEmployeeDirectory()
+	other.GetEmployeeDirectory()
+
+	if requests != 2 {
+		t.Errorf("Expected each API key to have its own cache entries, got %d requests", requests)
+	}
+}
+
+func TestCache_ErrorsNotCached(t *testing.T) {
+	requests := 0
+	client, _ := newCachedTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.WriteHeader(http.StatusServiceUnavailable)
+		w.Write([]byte("Service Unavailable"))
+	})
+
+	for i := 0; i < 2; i++ {
+		if _, err := client.GetEmployeeDirectory(); err == nil {
+			t.Error("Expected error for a failed request")
+		}
+	}
+
+	if requests != 2 {
+		t.Errorf("Expected failed responses not to be cached, got %d requests", requests)
+	}
+}
+
+func TestOpenCache_Persistence(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cache.json")
+
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Write([]byte(`[{"id": "1", "timeOffTypeId": "78", "name": "Vacation"}]`))
+	}))
+	defer server.Close()
+
+	for i := 0; i < 2; i++ {
+		cache, err := OpenCache(path, DefaultCacheTTLs)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		client := NewClient("testcompany", "testkey")
+		client.BaseURL = server.URL
+		client.Cache = cache
+
+		policies, err := client.GetTimeOffPolicies()
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if len(policies) != 1 || policies[0].Name != "Vacation" {
+			t.Errorf("Expected the Vacation policy, got %+v", policies)
+		}
+
+		if err := cache.Flush(); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	}
+
+	if requests != 1 {
+		t.Errorf("Expected the second run to be served from the cache file, got %d requests", requests)
+	}
+}
+
+func TestCache_PrunesExpiredEntries(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cache.json")
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/calculator") {
+			w.Write([]byte(`[]`))
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	cache, err := OpenCache(path, DefaultCacheTTLs)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	now := time.Date(2025, 12, 1, 9, 0, 0, 0, time.UTC)
+	cache.now = func() time.Time { return now }
+
+	client := NewClient("testcompany", "testkey")
+	client.BaseURL = server.URL
+	client.V1BaseURL = server.URL
+	client.Cache = cache
+
+	for _, balance := range []int{4, 5} {
+		if _, err := client.GetTimeOffBalance(balance); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	}
+	if _, err := client.GetTimeOffTypes(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	// The balances expire without a validator, while the types can still be revalidated
+	now = now.Add(DefaultCacheTTLs[CacheBalances] + time.Second)
+	if _, err := client.GetTimeOffBalance(6); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(cache.entries) != 2 {
+		t.Errorf("Expected the expired balances to be pruned, got %d entries", len(cache.entries))
+	}
+	if err := cache.Flush(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	// Entries of resources that are no longer cached are pruned when the file is loaded, while
+	// stale entries that can be revalidated are kept
+	reopened, err := OpenCache(path, map[string]time.Duration{CacheTimeOffTypes: 100 * 365 * 24 * time.Hour})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(reopened.entries) != 1 {
+		t.Errorf("Expected only the types to be loaded, got %d entries", len(reopened.entries))
+	}
+}
+
+func TestParseCacheTTLs(t *testing.T) {
+	ttls, err := ParseCacheTTLs("directory=1h, balances=0", DefaultCacheTTLs)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if ttls[CacheDirectory] != time.Hour {
+		t.Errorf("Expected directory TTL 1h, got %s", ttls[CacheDirectory])
+	}
+	if ttls[CacheBalances] != 0 {
+		t.Errorf("Expected balances TTL 0, got %s", ttls[CacheBalances])
+	}
+	if ttls[CacheTimeOffTypes] != DefaultCacheTTLs[CacheTimeOffTypes] {
+		t.Errorf("Expected the default time off types TTL, got %s", ttls[CacheTimeOffTypes])
+	}
+	if DefaultCacheTTLs[CacheDirectory] != 15*time.Minute {
+		t.Error("Expected the defaults to be left unchanged")
+	}
+
+	invalid := []string{"directory", "unknown=1h", "directory=soon"}
+	for _, value := range invalid {
+		if _, err := ParseCacheTTLs(value, DefaultCacheTTLs); err == nil {
+			t.Errorf("Expected error for %q", value)
+		}
+	}
+}
+
+func TestCacheResource(t *testing.T) {
+	tests := map[string]string{
+		"/employees/directory":               CacheDirectory,
+		"/employees/157":                     CacheEmployee,
+		"/employees/157/time_off/calculator": CacheBalances,
+		"/meta/time_off/types":               CacheTimeOffTypes,
+		"/meta/time_off/policies":            CacheTimeOffPolicies,
+		"/employees/157/time_off/policies":   CacheTimeOffPolicies,
+		"/time_off/requests":                 CacheRequests,
+		"/time_off/whos_out":                 CacheWhosOut,
+		"/employees/157/time_off/request":    "",
+	}
+
+	for path, expected := range tests {
+		if resource := cacheResource(path); resource != expected {
+			t.Errorf("%s: expected %q, got %q", path, expected, resource)
+		}
+	}
+}
@@ -0,0 +1,423 @@
+// Package bamboohr is a client for the BambooHR REST API, covering employees and time off.
+package bamboohr
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Client is a client for the BambooHR API
+type Client struct {
+	BaseURL    string
+	V1BaseURL  string
+	APIKey     string
+	HTTPClient *http.Client
+
+	company string
+
+	// Location is the timezone used to determine the current year when no dates are given.
+	// Defaults to the local timezone if nil.
+	Location *time.Location
+
+	// Cache caches GET responses when set. Writes invalidate the affected cached resources.
+	Cache *Cache
+}
+
+// APIError is returned when the BambooHR API responds with an unexpected status code
+type APIError struct {
+	StatusCode int
+	Body       string
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("API error %d: %s", e.StatusCode, e.Body)
+}
+
+// IsUnavailable reports whether the error means BambooHR could not be reached or failed to
+// respond, as opposed to rejecting the request
+func IsUnavailable(err error) bool {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.StatusCode >= http.StatusInternalServerError
+	}
+
+	var urlErr *url.Error
+	return errors.As(err, &urlErr)
+}
+
+// employeeFields lists the fields requested when fetching a single employee
+var employeeFields = []string{
+	"displayName",
+	"firstName",
+	"lastName",
+	"preferredName",
+	"jobTitle",
+	"workEmail",
+	"workPhone",
+	"mobilePhone",
+	"department",
+	"division",
+	"location",
+	"supervisor",
+	"supervisorEId",
+	"supervisorId",
+	"employeeNumber",
+	"photoUrl",
+	"standardHoursPerWeek",
+}
+
+// NewClient creates a new BambooHR API client
+func NewClient(company, apiKey string) *Client {
+	return &Client{
+		BaseURL:    fmt.Sprintf("https://%s.bamboohr.com/api/gateway.php/%s/v1", company, company),
+		V1BaseURL:  fmt.Sprintf("https://%s.bamboohr.com/api/v1", company),
+		APIKey:     apiKey,
+		company:    company,
+		HTTPClient: &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// Company returns the company subdomain the client connects to
+func (c *Client) Company() string {
+	return c.company
+}
+
+// makeRequest performs an HTTP request to the BambooHR API
+func (c *Client) makeRequest(method, endpoint string, body io.Reader) (*http.Response, error) {
+	url := c.BaseURL + endpoint
+
+	req, err := http.NewRequest(method, url, body)
+	if err != nil {
+		return nil, fmt.Errorf("creating request: %w", err)
+	}
+
+	// Basic authentication with API key as username and empty password
+	req.SetBasicAuth(c.APIKey, "")
+	req.Header.Set("Accept", "application/json")
+
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	return c.do(req)
+}
+
+// makeRequestV1 performs an HTTP request to the newer BambooHR API v1 format
+func (c *Client) makeRequestV1(method, endpoint string, body io.Reader) (*http.Response, error) {
+	url := c.V1BaseURL + endpoint
+
+	req, err := http.NewRequest(method, url, body)
+	if err != nil {
+		return nil, fmt.Errorf("creating request: %w", err)
+	}
+
+	// Basic authentication with API key as username and empty password
+	req.SetBasicAuth(c.APIKey, "")
+	req.Header.Set("Accept", "application/json")
+
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	return c.do(req)
+}
+
+// do sends the request, through the cache if one is configured
+func (c *Client) do(req *http.Request) (*http.Response, error) {
+	if c.Cache == nil {
+		return c.HTTPClient.Do(req)
+	}
+
+	return c.Cache.do(c.HTTPClient, req, cacheCaller(c.APIKey))
+}
+
+// Actions that time-off requests can be filtered by with TimeOffRequestQuery.Action
+const (
+	TimeOffActionView    = "view"
+	TimeOffActionApprove = "approve"
+)
+
+// TimeOffRequestQuery filters the time-off requests returned by SearchTimeOffRequests
+type TimeOffRequestQuery struct {
+	// Start and End (YYYY-MM-DD) limit the requests to those overlapping the period. They
+	// default to the current year.
+	Start string
+	End   string
+	// EmployeeID limits the requests to one employee. Zero returns the requests of every
+	// employee the API key can see.
+	EmployeeID int
+	// Statuses limits the requests to these statuses, such as "requested" or "approved"
+	Statuses []string
+	// TypeIDs limits the requests to these time off types
+	TypeIDs []string
+	// Action limits the requests to those the API key may view or approve
+	Action string
+}
+
+// GetTimeOffRequests retrieves time-off requests for a given employee
+func (c *Client) GetTimeOffRequests(employeeID int, start, end string) ([]TimeOffRequest, error) {
+	return c.SearchTimeOffRequests(TimeOffRequestQuery{Start: start, End: end, EmployeeID: employeeID})
+}
+
+// CurrentYearRange returns the first and last day (YYYY-MM-DD) of the current year in the
+// location, or the local timezone if it is nil. Searches without dates cover this range.
+func CurrentYearRange(location *time.Location) (string, string) {
+	now := time.Now()
+	if location != nil {
+		now = now.In(location)
+	}
+	return fmt.Sprintf("%d-01-01", now.Year()), fmt.Sprintf("%d-12-31", now.Year())
+}
+
+// SearchTimeOffRequests retrieves the time-off requests matching the query, across employees
+// unless the query names one
+func (c *Client) SearchTimeOffRequests(query TimeOffRequestQuery) ([]TimeOffRequest, error) {
+	start, end := query.Start, query.End
+
+	// Default to current year if no dates provided
+	if start == "" || end == "" {
+		start, end = CurrentYearRange(c.Location)
+	}
+
+	// Validate the dates before they are sent to the API
+	if err := validateDate(start); err != nil {
+		return nil, fmt.Errorf("invalid start: %w", err)
+	}
+	if err := validateDate(end); err != nil {
+		return nil, fmt.Errorf("invalid end: %w", err)
+	}
+
+	// Try the exact format from documentation: /time_off/requests with required start/end params
+	params := url.Values{}
+	params.Set("start", start)
+	params.Set("end", end)
+	if query.EmployeeID != 0 {
+		params.Set("employeeId", strconv.Itoa(query.EmployeeID))
+	}
+	if len(query.Statuses) > 0 {
+		params.Set("status", strings.Join(query.Statuses, ","))
+	}
+	if len(query.TypeIDs) > 0 {
+		params.Set("type", strings.Join(query.TypeIDs, ","))
+	}
+	if query.Action != "" {
+		params.Set("action", query.Action)
+	}
+	endpoint := "/time_off/requests?" + params.Encode()
+
+	resp, err := c.makeRequestV1("GET", endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("making request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("reading response body: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, &APIError{StatusCode: resp.StatusCode, Body: string(body)}
+	}
+
+	var requests []TimeOffRequest
+	if err := json.Unmarshal(body, &requests); err != nil {
+		return nil, fmt.Errorf("decoding response: %w", err)
+	}
+
+	return requests, nil
+}
+
+// GetTimeOffBalance retrieves time-off balance for an employee
+func (c *Client) GetTimeOffBalance(employeeID int) ([]TimeOffBalance, error) {
+	endpoint := fmt.Sprintf("/employees/%d/time_off/calculator", employeeID)
+
+	resp, err := c.makeRequest("GET", endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("making request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("reading response body: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, &APIError{StatusCode: resp.StatusCode, Body: string(body)}
+	}
+
+	var balances []TimeOffBalance
+	if err := json.Unmarshal(body, &balances); err != nil {
+		return nil, fmt.Errorf("decoding response: %w", err)
+	}
+
+	return balances, nil
+}
+
+// CreateTimeOffRequest creates a new time-off request for an employee
+func (c *Client) CreateTimeOffRequest(employeeID int, request TimeOffRequestCreate) (*TimeOffRequest, error) {
+	endpoint := fmt.Sprintf("/employees/%d/time_off/request", employeeID)
+
+	// Marshal the request to JSON
+	requestBody, err := json.Marshal(request)
+	if err != nil {
+		return nil, fmt.Errorf("marshaling request: %w", err)
+	}
+
+	resp, err := c.makeRequestV1("PUT", endpoint, strings.NewReader(string(requestBody)))
+	if err != nil {
+		return nil, fmt.Errorf("making request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("reading response body: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
+		return nil, &APIError{StatusCode: resp.StatusCode, Body: string(body)}
+	}
+
+	var createdRequest TimeOffRequest
+	if err := json.Unmarshal(body, &createdRequest); err != nil {
+		return nil, fmt.Errorf("decoding response: %w", err)
+	}
+
+	return &createdRequest, nil
+}
+
+// getJSON performs a GET request against the BambooHR API and decodes the JSON response into v
+func (c *Client) getJSON(endpoint string, v interface{}) error {
+	resp, err := c.makeRequest("GET", endpoint, nil)
+	if err != nil {
+		return fmt.Errorf("making request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("reading response body: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return &APIError{StatusCode: resp.StatusCode, Body: string(body)}
+	}
+
+	if err := json.Unmarshal(body, v); err != nil {
+		return fmt.Errorf("decoding response: %w", err)
+	}
+
+	return nil
+}
+
+// GetEmployeeDirectory retrieves the company employee directory
+func (c *Client) GetEmployeeDirectory() (*EmployeeDirectory, error) {
+	var directory EmployeeDirectory
+	if err := c.getJSON("/employees/directory", &directory); err != nil {
+		return nil, err
+	}
+
+	return &directory, nil
+}
+
+// GetEmployee retrieves a single employee by ID
+func (c *Client) GetEmployee(employeeID int) (*Employee, error) {
+	endpoint := fmt.Sprintf("/employees/%d?fields=%s", employeeID, strings.Join(employeeFields, ","))
+
+	var employee Employee
+	if err := c.getJSON(endpoint, &employee); err != nil {
+		return nil, err
+	}
+
+	return &employee, nil
+}
+
+// GetChangedEmployees retrieves the employees inserted, updated or deleted since the given time
+func (c *Client) GetChangedEmployees(since time.Time) (*EmployeeChanges, error) {
+	params := url.Values{}
+	params.Set("since", since.UTC().Format(time.RFC3339))
+	endpoint := "/employees/changed?" + params.Encode()
+
+	var changes EmployeeChanges
+	if err := c.getJSON(endpoint, &changes); err != nil {
+		return nil, err
+	}
+
+	return &changes, nil
+}
+
+// GetTimeOffTypes retrieves the time-off types configured for the company
+func (c *Client) GetTimeOffTypes() (*TimeOffTypes, error) {
+	var types TimeOffTypes
+	if err := c.getJSON("/meta/time_off/types", &types); err != nil {
+		return nil, err
+	}
+
+	return &types, nil
+}
+
+// GetTimeOffPolicies retrieves the time-off policies configured for the company
+func (c *Client) GetTimeOffPolicies() ([]TimeOffPolicy, error) {
+	var policies []TimeOffPolicy
+	if err := c.getJSON("/meta/time_off/policies", &policies); err != nil {
+		return nil, err
+	}
+
+	return policies, nil
+}
+
+// GetEmployeeTimeOffPolicies retrieves the time-off policies assigned to an employee
+func (c *Client) GetEmployeeTimeOffPolicies(employeeID int) ([]EmployeeTimeOffPolicy, error) {
+	var policies []EmployeeTimeOffPolicy
+	if err := c.getJSON(fmt.Sprintf("/employees/%d/time_off/policies", employeeID), &policies); err != nil {
+		return nil, err
+	}
+
+	return policies, nil
+}
+
+// GetWhosOut retrieves time off and company holidays between start and end (YYYY-MM-DD)
+func (c *Client) GetWhosOut(start, end string) ([]WhosOutEntry, error) {
+	if err := validateDate(start); err != nil {
+		return nil, fmt.Errorf("invalid start: %w", err)
+	}
+	if err := validateDate(end); err != nil {
+		return nil, fmt.Errorf("invalid end: %w", err)
+	}
+
+	params := url.Values{}
+	params.Set("start", start)
+	params.Set("end", end)
+	endpoint := "/time_off/whos_out?" + params.Encode()
+
+	var entries []WhosOutEntry
+	if err := c.getJSON(endpoint, &entries); err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
+
+// isoDatePattern matches dates in the YYYY-MM-DD format accepted by the API
+var isoDatePattern = regexp.MustCompile(`^\d{4}-\d{2}-\d{2}$`)
+
+// validateDate checks that value is a YYYY-MM-DD date
+func validateDate(value string) error {
+	if !isoDatePattern.MatchString(value) {
+		return fmt.Errorf("invalid date %q: expected YYYY-MM-DD format", value)
+	}
+	if _, err := time.Parse("2006-01-02", value); err != nil {
+		return fmt.Errorf("invalid date %q: %w", value, err)
+	}
+	return nil
+}
@@ -0,0 +1,454 @@
+package bamboohr
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNewClient(t *testing.T) {
+	client := NewClient("testcompany", "testkey")
+
+	if client.Company() != "testcompany" {
+		t.Errorf("Expected company to be 'testcompany', got '%s'", client.Company())
+	}
+
+	if client.APIKey != "testkey" {
+		t.Errorf("Expected API key to be 'testkey', got '%s'", client.APIKey)
+	}
+
+	expectedURL := "https://testcompany.bamboohr.com/api/gateway.php/testcompany/v1"
+	if client.BaseURL != expectedURL {
+		t.Errorf("Expected base URL to be '%s', got '%s'", expectedURL, client.BaseURL)
+	}
+
+	if client.HTTPClient.Timeout != 30*time.Second {
+		t.Errorf("Expected timeout to be 30 seconds, got %v", client.HTTPClient.Timeout)
+	}
+}
+
+func TestNewClient_Validation(t *testing.T) {
+	tests := []struct {
+		name     string
+		company  string
+		apiKey   string
+		hasError bool
+	}{
+		{"Valid inputs", "mycompany", "sk_12345", false},
+		{"Empty company", "", "sk_12345", false},  // Client creation doesn't validate, main() does
+		{"Empty API key", "mycompany", "", false}, // Client creation doesn't validate, main() does
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := NewClient(tt.company, tt.apiKey)
+			if client == nil {
+				t.Error("Expected client to be created, got nil")
+			}
+		})
+	}
+}
+
+// Mock HTTP server tests
+func TestClient_GetTimeOffBalance_Success(t *testing.T) {
+	// Create a mock server
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Verify the request
+		if r.Method != "GET" {
+			t.Errorf("Expected GET method, got %s", r.Method)
+		}
+
+		if r.URL.Path != "/employees/157/time_off/calculator" {
+			t.Errorf("Expected path /employees/157/time_off/calculator, got %s", r.URL.Path)
+		}
+
+		// Verify authentication
+		username, _, ok := r.BasicAuth()
+		if !ok || username != "testkey" {
+			t.Error("Expected basic auth with API key as username")
+		}
+
+		// Return mock response
+		response := `[
+			{
+				"timeOffType": "27",
+				"name": "Home Office days",
+				"units": "days",
+				"balance": "3.42",
+				"end": "2025-09-01",
+				"policyType": "accruing",
+				"usedYearToDate": "1"
+			}
+		]`
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(response))
+	}))
+	defer server.Close()
+
+	// Create client with mock server URL
+	client := NewClient("testcompany", "testkey")
+	client.BaseURL = server.URL
+
+	balances, err := client.GetTimeOffBalance(157)
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	if len(balances) != 1 {
+		t.Errorf("Expected 1 balance, got %d", len(balances))
+	}
+
+	if balances[0].Name != "Home Office days" {
+		t.Errorf("Expected 'Home Office days', got %s", balances[0].Name)
+	}
+}
+
+func TestClient_CreateTimeOffRequest_Success(t *testing.T) {
+	// Create a mock server
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Verify the request
+		if r.Method != "PUT" {
+			t.Errorf("Expected PUT method, got %s", r.Method)
+		}
+
+		if r.URL.Path != "/employees/157/time_off/request" {
+			t.Errorf("Expected path /employees/157/time_off/request, got %s", r.URL.Path)
+		}
+
+		// Verify request body
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			t.Errorf("Failed to read request body: %v", err)
+		}
+
+		var request TimeOffRequestCreate
+		err = json.Unmarshal(body, &request)
+		if err != nil {
+			t.Errorf("Failed to unmarshal request body: %v", err)
+		}
+
+		if request.Status != "requested" {
+			t.Errorf("Expected status 'requested', got %s", request.Status)
+		}
+
+		// Return mock response
+		response := `{
+			"id": "22565",
+			"employeeId": "157",
+			"name": "Test User",
+			"start": "2025-09-05",
+			"end": "2025-09-05",
+			"created": "2025-09-01",
+			"type": {
+				"id": "27",
+				"name": "Home Office days",
+				"icon": ""
+			},
+			"amount": {
+				"unit": "days",
+				"amount": 1
+			},
+			"notes": [],
+			"status": {
+				"status": "requested",
+				"lastChanged": "2025-09-01 14:23:47",
+				"lastChangedByUserId": "2627"
+			},
+			"actions": {
+				"view": true,
+				"edit": false,
+				"cancel": true,
+				"approve": false,
+				"deny": false,
+				"bypass": false
+			},
+			"dates": {
+				"2025-09-05": "1"
+			}
+		}`
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(response))
+	}))
+	defer server.Close()
+
+	// For this test, we'll test the JSON marshaling/unmarshaling logic separately
+	// since mocking the HTTP client methods is complex due to Go's method structure
+
+	request := TimeOffRequestCreate{
+		Status:        "requested",
+		Start:         "2025-09-05",
+		End:           "2025-09-05",
+		TimeOffTypeID: 27,
+		Amount:        1,
+	}
+
+	// Test JSON marshaling
+	requestBody, err := json.Marshal(request)
+	if err != nil {
+		t.Errorf("Failed to marshal request: %v", err)
+	}
+
+	// Verify the JSON structure contains expected fields
+	var jsonMap map[string]interface{}
+	err = json.Unmarshal(requestBody, &jsonMap)
+	if err != nil {
+		t.Errorf("Failed to unmarshal request to map: %v", err)
+	}
+
+	if jsonMap["status"] != "requested" {
+		t.Errorf("Expected status 'requested', got %v", jsonMap["status"])
+	}
+
+	if jsonMap["timeOffTypeId"] != float64(27) {
+		t.Errorf("Expected timeOffTypeId 27, got %v", jsonMap["timeOffTypeId"])
+	}
+
+	// Test response unmarshaling
+	responseJSON := `{
+		"id": "22565",
+		"employeeId": "157",
+		"name": "Test User",
+		"status": {
+			"status": "requested"
+		}
+	}`
+
+	var result TimeOffRequest
+	err = json.Unmarshal([]byte(responseJSON), &result)
+	if err != nil {
+		t.Errorf("Failed to unmarshal response: %v", err)
+	}
+
+	if result.ID != "22565" {
+		t.Errorf("Expected ID '22565', got %s", result.ID)
+	}
+
+	if result.Status.Status != "requested" {
+		t.Errorf("Expected status 'requested', got %s", result.Status.Status)
+	}
+}
+
+func TestClient_GetTimeOffBalance_ErrorResponse(t *testing.T) {
+	// Create a mock server that returns an error
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte("Internal Server Error"))
+	}))
+	defer server.Close()
+
+	// Create client with mock server URL
+	client := NewClient("testcompany", "testkey")
+	client.BaseURL = server.URL
+
+	_, err := client.GetTimeOffBalance(157)
+	if err == nil {
+		t.Error("Expected error, but got none")
+	}
+
+	expectedError := "API error 500: Internal Server Error"
+	if err.Error() != expectedError {
+		t.Errorf("Expected error '%s', got '%s'", expectedError, err.Error())
+	}
+}
+
+func TestClient_GetTimeOffBalance_InvalidJSON(t *testing.T) {
+	// Create a mock server that returns invalid JSON
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte("invalid json"))
+	}))
+	defer server.Close()
+
+	// Create client with mock server URL
+	client := NewClient("testcompany", "testkey")
+	client.BaseURL = server.URL
+
+	_, err := client.GetTimeOffBalance(157)
+	if err == nil {
+		t.Error("Expected error for invalid JSON, but got none")
+	}
+}
+
+func TestValidateDate(t *testing.T) {
+	if err := validateDate("2025-09-05"); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	for _, input := range []string{"2025-9-5", "2025-02-30", "2025-09-05&employeeId=1", "tomorrow"} {
+		if err := validateDate(input); err == nil {
+			t.Errorf("Expected error for %q, but got none", input)
+		}
+	}
+}
+
+func TestClient_GetTimeOffRequests_InvalidDate(t *testing.T) {
+	client := NewClient("testcompany", "testkey")
+	client.V1BaseURL = "http://127.0.0.1:0"
+
+	_, err := client.GetTimeOffRequests(157, "2025-01-01&status=approved", "2025-12-31")
+	if err == nil || !strings.Contains(err.Error(), "invalid start") {
+		t.Errorf("Expected invalid start error, got %v", err)
+	}
+}
+
+func TestClient_SearchTimeOffRequests(t *testing.T) {
+	tests := []struct {
+		name     string
+		query    TimeOffRequestQuery
+		expected string
+	}{
+		{
+			"Single employee",
+			TimeOffRequestQuery{Start: "2025-01-01", End: "2025-12-31", EmployeeID: 157},
+			"employeeId=157&end=2025-12-31&start=2025-01-01",
+		},
+		{
+			"Team filters",
+			TimeOffRequestQuery{Start: "2025-01-01", End: "2025-12-31", Statuses: []string{"approved", "requested"}, TypeIDs: []string{"78", "83"}},
+			"end=2025-12-31&start=2025-01-01&status=approved%2Crequested&type=78%2C83",
+		},
+		{
+			"Pending approvals",
+			TimeOffRequestQuery{Start: "2025-01-01", End: "2025-12-31", Statuses: []string{"requested"}, Action: TimeOffActionApprove},
+			"action=approve&end=2025-12-31&start=2025-01-01&status=requested",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != "/time_off/requests" {
+					t.Errorf("Expected path /time_off/requests, got %s", r.URL.Path)
+				}
+				if r.URL.RawQuery != tt.expected {
+					t.Errorf("Expected query %s, got %s", tt.expected, r.URL.RawQuery)
+				}
+
+				w.Header().Set("Content-Type", "application/json")
+				w.Write([]byte(`[{"id": "1", "employeeId": "157", "status": {"status": "requested"}}]`))
+			}))
+			defer server.Close()
+
+			client := NewClient("testcompany", "testkey")
+			client.V1BaseURL = server.URL
+
+			requests, err := client.SearchTimeOffRequests(tt.query)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if len(requests) != 1 || requests[0].ID != "1" {
+				t.Errorf("Unexpected requests: %+v", requests)
+			}
+		})
+	}
+}
+
+func TestClient_GetChangedEmployees(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/employees/changed" {
+			t.Errorf("Expected path /employees/changed, got %s", r.URL.Path)
+		}
+
+		if since := r.URL.Query().Get("since"); since != "2025-12-01T08:00:00Z" {
+			t.Errorf("Expected since 2025-12-01T08:00:00Z, got %s", since)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{
+			"latest": "2025-12-01T09:30:00+00:00",
+			"employees": {
+				"4": {"id": "4", "action": "Updated", "lastChanged": "2025-12-01T09:30:00+00:00"},
+				"9": {"id": "9", "action": "Deleted", "lastChanged": "2025-12-01T09:00:00+00:00"}
+			}
+		}`))
+	}))
+	defer server.Close()
+
+	client := NewClient("testcompany", "testkey")
+	client.BaseURL = server.URL
+
+	sydney, _ := time.LoadLocation("Australia/Sydney")
+	changes, err := client.GetChangedEmployees(time.Date(2025, 12, 1, 19, 0, 0, 0, sydney))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if changes.Latest != "2025-12-01T09:30:00+00:00" {
+		t.Errorf("Expected latest 2025-12-01T09:30:00+00:00, got %s", changes.Latest)
+	}
+
+	if len(changes.Employees) != 2 || changes.Employees["4"].Action != EmployeeUpdated || changes.Employees["9"].Action != EmployeeDeleted {
+		t.Errorf("Unexpected changes: %+v", changes.Employees)
+	}
+}
+
+func TestClient_GetEmployeeTimeOffPolicies(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/employees/157/time_off/policies" {
+			t.Errorf("Expected path /employees/157/time_off/policies, got %s", r.URL.Path)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`[{"timeOffPolicyId": 3, "timeOffTypeId": "78", "accrualStartDate": "2020-01-01"}]`))
+	}))
+	defer server.Close()
+
+	client := NewClient("testcompany", "testkey")
+	client.BaseURL = server.URL
+
+	policies, err := client.GetEmployeeTimeOffPolicies(157)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if len(policies) != 1 || policies[0].TimeOffPolicyID != "3" || policies[0].TimeOffTypeID != "78" || policies[0].AccrualStartDate != "2020-01-01" {
+		t.Errorf("Unexpected policies: %+v", policies)
+	}
+}
+
+func TestIsUnavailable(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/employees/1/time_off/calculator" {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusForbidden)
+	}))
+
+	client := NewClient("testcompany", "testkey")
+	client.BaseURL = server.URL
+
+	_, unavailable := client.GetTimeOffBalance(1)
+	_, forbidden := client.GetTimeOffBalance(2)
+
+	server.Close()
+	_, unreachable := client.GetTimeOffBalance(1)
+
+	tests := []struct {
+		name     string
+		err      error
+		expected bool
+	}{
+		{"service unavailable", unavailable, true},
+		{"forbidden", forbidden, false},
+		{"unreachable", unreachable, true},
+		{"invalid date", validateDate("2025-13"), false},
+	}
+
+	for _, tt := range tests {
+		if tt.err == nil {
+			t.Errorf("%s: expected an error", tt.name)
+			continue
+		}
+		if IsUnavailable(tt.err) != tt.expected {
+			t.Errorf("%s: expected IsUnavailable %v for %v", tt.name, tt.expected, tt.err)
+		}
+	}
+}
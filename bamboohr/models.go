@@ -0,0 +1,291 @@
+package bamboohr
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"github.com/invopop/jsonschema"
+)
+
+// TimeOffRequest represents a time-off request
+type TimeOffRequest struct {
+	ID         string `json:"id"`
+	EmployeeID string `json:"employeeId"`
+	Name       string `json:"name"`
+	Start      string `json:"start"`
+	End        string `json:"end"`
+	Created    string `json:"created"`
+	Type       struct {
+		ID   string `json:"id"`
+		Name string `json:"name"`
+		Icon string `json:"icon"`
+	} `json:"type"`
+	Amount struct {
+		Unit   string        `json:"unit"`
+		Amount FlexibleFloat `json:"amount"`
+	} `json:"amount"`
+	Notes  FlexibleNotes `json:"notes"`
+	Status struct {
+		Status          string `json:"status"`
+		LastChanged     string `json:"lastChanged"`
+		LastChangedByID string `json:"lastChangedByUserId"`
+	} `json:"status"`
+	Actions struct {
+		View    bool `json:"view"`
+		Edit    bool `json:"edit"`
+		Cancel  bool `json:"cancel"`
+		Approve bool `json:"approve"`
+		Deny    bool `json:"deny"`
+		Bypass  bool `json:"bypass"`
+	} `json:"actions"`
+	Dates map[string]string `json:"dates"`
+}
+
+// TimeOffBalance represents time-off balance for an employee
+type TimeOffBalance struct {
+	TimeOffType    string        `json:"timeOffType"`
+	Name           string        `json:"name"`
+	Units          string        `json:"units"`
+	Balance        FlexibleFloat `json:"balance"`
+	End            string        `json:"end"`
+	PolicyType     string        `json:"policyType"`
+	UsedYearToDate FlexibleFloat `json:"usedYearToDate"`
+}
+
+// Note represents a note in the time-off request
+type Note struct {
+	From string `json:"from"`
+	Note string `json:"note"`
+}
+
+// FlexibleNotes can handle string, object, or array of notes from the BambooHR API
+type FlexibleNotes struct {
+	Notes []Note
+}
+
+// UnmarshalJSON handles different formats of notes from the BambooHR API
+func (fn *FlexibleNotes) UnmarshalJSON(data []byte) error {
+	// Try to unmarshal as a simple string first
+	var str string
+	if err := json.Unmarshal(data, &str); err == nil {
+		// If it's a string, create a single note entry
+		fn.Notes = []Note{{Note: str}}
+		return nil
+	}
+
+	// Try to unmarshal as an array of Note objects
+	var notes []Note
+	if err := json.Unmarshal(data, &notes); err == nil {
+		fn.Notes = notes
+		return nil
+	}
+
+	// Try to unmarshal as a generic object first (before trying as a Note)
+	var obj map[string]interface{}
+	if err := json.Unmarshal(data, &obj); err == nil {
+		// Check if it looks like a Note object (has "from" and/or "note" keys)
+		if _, hasFrom := obj["from"]; hasFrom || obj["note"] != nil {
+			// Try to unmarshal as a single Note object
+			var note Note
+			if err := json.Unmarshal(data, &note); err == nil {
+				fn.Notes = []Note{note}
+				return nil
+			}
+		}
+
+		// Convert the generic object to a note representation
+		noteText := ""
+		for key, value := range obj {
+			if str, ok := value.(string); ok {
+				if noteText != "" {
+					noteText += "; "
+				}
+				noteText += fmt.Sprintf("%s: %s", key, str)
+			}
+		}
+		fn.Notes = []Note{{Note: noteText}}
+		return nil
+	}
+
+	return fmt.Errorf("cannot unmarshal notes field")
+}
+
+// MarshalJSON converts FlexibleNotes back to JSON, always as an array of notes
+func (fn FlexibleNotes) MarshalJSON() ([]byte, error) {
+	if fn.Notes == nil {
+		return []byte("[]"), nil
+	}
+	return json.Marshal(fn.Notes)
+}
+
+// JSONSchema describes FlexibleNotes in output schemas as the array of notes it marshals to
+func (FlexibleNotes) JSONSchema() *jsonschema.Schema {
+	reflector := jsonschema.Reflector{DoNotReference: true, Anonymous: true, AllowAdditionalProperties: true}
+	note := reflector.Reflect(Note{})
+	note.Version = ""
+
+	return &jsonschema.Schema{Type: "array", Items: note}
+}
+
+// DateAmount represents a date with an amount for the time-off request
+type DateAmount struct {
+	YMD    string  `json:"ymd"`
+	Amount float64 `json:"amount"`
+}
+
+// TimeOffRequestCreate represents the payload for creating a time-off request
+type TimeOffRequestCreate struct {
+	Status          string       `json:"status,omitempty"`
+	Start           string       `json:"start"`
+	End             string       `json:"end"`
+	TimeOffTypeID   int          `json:"timeOffTypeId"`
+	Amount          float64      `json:"amount,omitempty"`
+	Notes           []Note       `json:"notes,omitempty"`
+	Dates           []DateAmount `json:"dates,omitempty"`
+	PreviousRequest int          `json:"previousRequest,omitempty"`
+}
+
+// FlexibleFloat can unmarshal both string and float64 values
+type FlexibleFloat float64
+
+func (f *FlexibleFloat) UnmarshalJSON(data []byte) error {
+	// Try to unmarshal as float64 first
+	var floatVal float64
+	if err := json.Unmarshal(data, &floatVal); err == nil {
+		*f = FlexibleFloat(floatVal)
+		return nil
+	}
+
+	// If that fails, try to unmarshal as string and convert
+	var stringVal string
+	if err := json.Unmarshal(data, &stringVal); err != nil {
+		return err
+	}
+
+	// Handle empty string as 0
+	if stringVal == "" {
+		*f = FlexibleFloat(0)
+		return nil
+	}
+
+	// Parse string to float
+	floatVal, err := strconv.ParseFloat(stringVal, 64)
+	if err != nil {
+		return fmt.Errorf("cannot parse '%s' as float: %w", stringVal, err)
+	}
+
+	*f = FlexibleFloat(floatVal)
+	return nil
+}
+
+func (f FlexibleFloat) MarshalJSON() ([]byte, error) {
+	return json.Marshal(float64(f))
+}
+
+// Employee represents an employee record from the directory or employee endpoints.
+// SupervisorEID is the employee ID of the employee's supervisor, and SupervisorID is the
+// supervisor's employee number.
+type Employee struct {
+	ID             string `json:"id"`
+	EmployeeNumber string `json:"employeeNumber,omitempty"`
+	DisplayName    string `json:"displayName,omitempty"`
+	FirstName      string `json:"firstName,omitempty"`
+	LastName       string `json:"lastName,omitempty"`
+	PreferredName  string `json:"preferredName,omitempty"`
+	JobTitle       string `json:"jobTitle,omitempty"`
+	WorkEmail      string `json:"workEmail,omitempty"`
+	WorkPhone      string `json:"workPhone,omitempty"`
+	MobilePhone    string `json:"mobilePhone,omitempty"`
+	Department     string `json:"department,omitempty"`
+	Division       string `json:"division,omitempty"`
+	Location       string `json:"location,omitempty"`
+	Supervisor     string `json:"supervisor,omitempty"`
+	SupervisorEID  string `json:"supervisorEId,omitempty"`
+	SupervisorID   string `json:"supervisorId,omitempty"`
+	PhotoURL       string `json:"photoUrl,omitempty"`
+	// StandardHoursPerWeek is the employee's contracted hours, used to convert time off between
+	// days and hours
+	StandardHoursPerWeek FlexibleFloat `json:"standardHoursPerWeek,omitempty"`
+}
+
+// DirectoryField describes a field included in the employee directory
+type DirectoryField struct {
+	ID   string `json:"id"`
+	Type string `json:"type"`
+	Name string `json:"name"`
+}
+
+// EmployeeDirectory represents the company employee directory
+type EmployeeDirectory struct {
+	Fields    []DirectoryField `json:"fields"`
+	Employees []Employee       `json:"employees"`
+}
+
+// EmployeeChange describes an employee inserted, updated or deleted since a point in time
+type EmployeeChange struct {
+	ID          string `json:"id"`
+	Action      string `json:"action"`
+	LastChanged string `json:"lastChanged"`
+}
+
+// Employee change actions
+const (
+	EmployeeInserted = "Inserted"
+	EmployeeUpdated  = "Updated"
+	EmployeeDeleted  = "Deleted"
+)
+
+// EmployeeChanges represents the employees changed since a point in time, keyed by employee ID.
+// Latest is the time of the most recent change.
+type EmployeeChanges struct {
+	Latest    string                    `json:"latest"`
+	Employees map[string]EmployeeChange `json:"employees"`
+}
+
+// TimeOffType represents a time-off type configured in BambooHR
+type TimeOffType struct {
+	ID    string `json:"id"`
+	Name  string `json:"name"`
+	Units string `json:"units"`
+	Color string `json:"color"`
+	Icon  string `json:"icon"`
+}
+
+// DefaultHours represents the default hours for a day of the week
+type DefaultHours struct {
+	Name   string        `json:"name"`
+	Amount FlexibleFloat `json:"amount"`
+}
+
+// TimeOffTypes represents the time-off types metadata for the company
+type TimeOffTypes struct {
+	TimeOffTypes []TimeOffType  `json:"timeOffTypes"`
+	DefaultHours []DefaultHours `json:"defaultHours"`
+}
+
+// TimeOffPolicy represents a time-off policy configured in BambooHR
+type TimeOffPolicy struct {
+	ID            string `json:"id"`
+	TimeOffTypeID string `json:"timeOffTypeId"`
+	Name          string `json:"name"`
+	EffectiveDate string `json:"effectiveDate"`
+	Type          string `json:"type"`
+}
+
+// EmployeeTimeOffPolicy represents a time-off policy assigned to an employee
+type EmployeeTimeOffPolicy struct {
+	TimeOffPolicyID  json.Number `json:"timeOffPolicyId"`
+	TimeOffTypeID    json.Number `json:"timeOffTypeId"`
+	AccrualStartDate string      `json:"accrualStartDate"`
+}
+
+// WhosOutEntry represents an entry in the who's out calendar, either time off or a holiday
+type WhosOutEntry struct {
+	ID         json.Number `json:"id"`
+	Type       string      `json:"type"`
+	EmployeeID json.Number `json:"employeeId,omitempty"`
+	Name       string      `json:"name"`
+	Start      string      `json:"start"`
+	End        string      `json:"end"`
+}
@@ -1,58 +1,11 @@
-package main
+package bamboohr
 
 import (
 	"encoding/json"
-	"io"
-	"net/http"
-	"net/http/httptest"
 	"strings"
 	"testing"
-	"time"
 )
 
-func TestNewBambooHRClient(t *testing.T) {
-	client := NewBambooHRClient("testcompany", "testkey")
-
-	if client.Company != "testcompany" {
-		t.Errorf("Expected company to be 'testcompany', got '%s'", client.Company)
-	}
-
-	if client.APIKey != "testkey" {
-		t.Errorf("Expected API key to be 'testkey', got '%s'", client.APIKey)
-	}
-
-	expectedURL := "https://testcompany.bamboohr.com/api/gateway.php/testcompany/v1"
-	if client.BaseURL != expectedURL {
-		t.Errorf("Expected base URL to be '%s', got '%s'", expectedURL, client.BaseURL)
-	}
-
-	if client.HTTPClient.Timeout != 30*time.Second {
-		t.Errorf("Expected timeout to be 30 seconds, got %v", client.HTTPClient.Timeout)
-	}
-}
-
-func TestBambooHRClientValidation(t *testing.T) {
-	tests := []struct {
-		name     string
-		company  string
-		apiKey   string
-		hasError bool
-	}{
-		{"Valid inputs", "mycompany", "sk_12345", false},
-		{"Empty company", "", "sk_12345", false},  // Client creation doesn't validate, main() does
-		{"Empty API key", "mycompany", "", false}, // Client creation doesn't validate, main() does
-	}
-
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			client := NewBambooHRClient(tt.company, tt.apiKey)
-			if client == nil {
-				t.Error("Expected client to be created, got nil")
-			}
-		})
-	}
-}
-
 func TestFlexibleFloat_UnmarshalJSON(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -196,7 +149,7 @@ func TestDateAmount_JSONStructure(t *testing.T) {
 	}
 
 	if unmarshaled.Amount != dateAmount.Amount {
-		t.Errorf("Expected amount %d, got %d", dateAmount.Amount, unmarshaled.Amount)
+		t.Errorf("Expected amount %v, got %v", dateAmount.Amount, unmarshaled.Amount)
 	}
 }
 
@@ -295,229 +248,6 @@ func TestTimeOffBalance_JSONStructure(t *testing.T) {
 	}
 }
 
-// Mock HTTP server tests
-func TestBambooHRClient_GetTimeOffBalance_Success(t *testing.T) {
-	// Create a mock server
-	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		// Verify the request
-		if r.Method != "GET" {
-			t.Errorf("Expected GET method, got %s", r.Method)
-		}
-
-		if r.URL.Path != "/employees/157/time_off/calculator" {
-			t.Errorf("Expected path /employees/157/time_off/calculator, got %s", r.URL.Path)
-		}
-
-		// Verify authentication
-		username, _, ok := r.BasicAuth()
-		if !ok || username != "testkey" {
-			t.Error("Expected basic auth with API key as username")
-		}
-
-		// Return mock response
-		response := `[
-			{
-				"timeOffType": "27",
-				"name": "Home Office days",
-				"units": "days",
-				"balance": "3.42",
-				"end": "2025-09-01",
-				"policyType": "accruing",
-				"usedYearToDate": "1"
-			}
-		]`
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusOK)
-		w.Write([]byte(response))
-	}))
-	defer server.Close()
-
-	// Create client with mock server URL
-	client := NewBambooHRClient("testcompany", "testkey")
-	client.BaseURL = server.URL
-
-	balances, err := client.GetTimeOffBalance(157)
-	if err != nil {
-		t.Errorf("Unexpected error: %v", err)
-	}
-
-	if len(balances) != 1 {
-		t.Errorf("Expected 1 balance, got %d", len(balances))
-	}
-
-	if balances[0].Name != "Home Office days" {
-		t.Errorf("Expected 'Home Office days', got %s", balances[0].Name)
-	}
-}
-
-func TestBambooHRClient_CreateTimeOffRequest_Success(t *testing.T) {
-	// Create a mock server
-	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		// Verify the request
-		if r.Method != "PUT" {
-			t.Errorf("Expected PUT method, got %s", r.Method)
-		}
-
-		if r.URL.Path != "/employees/157/time_off/request" {
-			t.Errorf("Expected path /employees/157/time_off/request, got %s", r.URL.Path)
-		}
-
-		// Verify request body
-		body, err := io.ReadAll(r.Body)
-		if err != nil {
-			t.Errorf("Failed to read request body: %v", err)
-		}
-
-		var request TimeOffRequestCreate
-		err = json.Unmarshal(body, &request)
-		if err != nil {
-			t.Errorf("Failed to unmarshal request body: %v", err)
-		}
-
-		if request.Status != "requested" {
-			t.Errorf("Expected status 'requested', got %s", request.Status)
-		}
-
-		// Return mock response
-		response := `{
-			"id": "22565",
-			"employeeId": "157",
-			"name": "Test User",
-			"start": "2025-09-05",
-			"end": "2025-09-05",
-			"created": "2025-09-01",
-			"type": {
-				"id": "27",
-				"name": "Home Office days",
-				"icon": ""
-			},
-			"amount": {
-				"unit": "days",
-				"amount": 1
-			},
-			"notes": [],
-			"status": {
-				"status": "requested",
-				"lastChanged": "2025-09-01 14:23:47",
-				"lastChangedByUserId": "2627"
-			},
-			"actions": {
-				"view": true,
-				"edit": false,
-				"cancel": true,
-				"approve": false,
-				"deny": false,
-				"bypass": false
-			},
-			"dates": {
-				"2025-09-05": "1"
-			}
-		}`
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusCreated)
-		w.Write([]byte(response))
-	}))
-	defer server.Close()
-
-	// For this test, we'll test the JSON marshaling/unmarshaling logic separately
-	// since mocking the HTTP client methods is complex due to Go's method structure
-
-	request := TimeOffRequestCreate{
-		Status:        "requested",
-		Start:         "2025-09-05",
-		End:           "2025-09-05",
-		TimeOffTypeID: 27,
-		Amount:        1,
-	}
-
-	// Test JSON marshaling
-	requestBody, err := json.Marshal(request)
-	if err != nil {
-		t.Errorf("Failed to marshal request: %v", err)
-	}
-
-	// Verify the JSON structure contains expected fields
-	var jsonMap map[string]interface{}
-	err = json.Unmarshal(requestBody, &jsonMap)
-	if err != nil {
-		t.Errorf("Failed to unmarshal request to map: %v", err)
-	}
-
-	if jsonMap["status"] != "requested" {
-		t.Errorf("Expected status 'requested', got %v", jsonMap["status"])
-	}
-
-	if jsonMap["timeOffTypeId"] != float64(27) {
-		t.Errorf("Expected timeOffTypeId 27, got %v", jsonMap["timeOffTypeId"])
-	}
-
-	// Test response unmarshaling
-	responseJSON := `{
-		"id": "22565",
-		"employeeId": "157",
-		"name": "Test User",
-		"status": {
-			"status": "requested"
-		}
-	}`
-
-	var result TimeOffRequest
-	err = json.Unmarshal([]byte(responseJSON), &result)
-	if err != nil {
-		t.Errorf("Failed to unmarshal response: %v", err)
-	}
-
-	if result.ID != "22565" {
-		t.Errorf("Expected ID '22565', got %s", result.ID)
-	}
-
-	if result.Status.Status != "requested" {
-		t.Errorf("Expected status 'requested', got %s", result.Status.Status)
-	}
-}
-
-func TestBambooHRClient_GetTimeOffBalance_ErrorResponse(t *testing.T) {
-	// Create a mock server that returns an error
-	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		w.WriteHeader(http.StatusInternalServerError)
-		w.Write([]byte("Internal Server Error"))
-	}))
-	defer server.Close()
-
-	// Create client with mock server URL
-	client := NewBambooHRClient("testcompany", "testkey")
-	client.BaseURL = server.URL
-
-	_, err := client.GetTimeOffBalance(157)
-	if err == nil {
-		t.Error("Expected error, but got none")
-	}
-
-	expectedError := "API error 500: Internal Server Error"
-	if err.Error() != expectedError {
-		t.Errorf("Expected error '%s', got '%s'", expectedError, err.Error())
-	}
-}
-
-func TestBambooHRClient_GetTimeOffBalance_InvalidJSON(t *testing.T) {
-	// Create a mock server that returns invalid JSON
-	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusOK)
-		w.Write([]byte("invalid json"))
-	}))
-	defer server.Close()
-
-	// Create client with mock server URL
-	client := NewBambooHRClient("testcompany", "testkey")
-	client.BaseURL = server.URL
-
-	_, err := client.GetTimeOffBalance(157)
-	if err == nil {
-		t.Error("Expected error for invalid JSON, but got none")
-	}
-}
-
 func TestTimeOffRequestCreate_EmptyOptionalFields(t *testing.T) {
 	request := TimeOffRequestCreate{
 		Start:         "2025-09-05",
@@ -552,17 +282,6 @@ func TestTimeOffRequestCreate_EmptyOptionalFields(t *testing.T) {
 	}
 }
 
-func TestVersion(t *testing.T) {
-	if Version == "" {
-		t.Error("Version should not be empty")
-	}
-
-	// Version should be in format X.Y.Z
-	if len(Version) < 5 {
-		t.Errorf("Version '%s' seems too short", Version)
-	}
-}
-
 func TestFlexibleNotes_UnmarshalJSON(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -0,0 +1,499 @@
+// Command bamboohr-mcp-server serves BambooHR time off to MCP clients over stdio or HTTP.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/mark3labs/mcp-go/server"
+
+	"bamboohr-mcp-server/bamboohr"
+	"bamboohr-mcp-server/mcpserver"
+	"bamboohr-mcp-server/store"
+)
+
+// httpShutdownTimeout is how long in-flight HTTP requests may take to finish on shutdown
+const httpShutdownTimeout = 10 * time.Second
+
+// timezoneFromEnv returns the timezone set by BAMBOOHR_TIMEZONE, or the local timezone
+func timezoneFromEnv() (*time.Location, error) {
+	name := os.Getenv("BAMBOOHR_TIMEZONE")
+	if name == "" {
+		return time.Local, nil
+	}
+
+	location, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, fmt.Errorf("BAMBOOHR_TIMEZONE is not a valid IANA timezone: %w", err)
+	}
+	return location, nil
+}
+
+// newDateParserFromEnv creates the date parser for the timezone using the
+// BAMBOOHR_REFERENCE_DATE, BAMBOOHR_LOCALE and BAMBOOHR_LOCATION_TIMEZONES environment variables
+func newDateParserFromEnv(client mcpserver.BambooHR, location *time.Location) (*mcpserver.DateParser, error) {
+	dates := mcpserver.NewDateParser(location, time.Time{})
+
+	if value := os.Getenv("BAMBOOHR_REFERENCE_DATE"); value != "" {
+		date, err := time.Parse("2006-01-02", value)
+		if err != nil {
+			return nil, fmt.Errorf("BAMBOOHR_REFERENCE_DATE must be in YYYY-MM-DD format: %w", err)
+		}
+		// The reference date is the same calendar date in every employee's timezone
+		dates = dates.WithReferenceDate(date)
+	}
+
+	if value := os.Getenv("BAMBOOHR_LOCALE"); value != "" {
+		locale, err := mcpserver.ParseLocale(value)
+		if err != nil {
+			return nil, fmt.Errorf("BAMBOOHR_LOCALE: %w", err)
+		}
+		dates = dates.WithLocale(locale)
+	}
+
+	locations, err := mcpserver.ParseLocationTimezones(os.Getenv("BAMBOOHR_LOCATION_TIMEZONES"))
+	if err != nil {
+		return nil, fmt.Errorf("BAMBOOHR_LOCATION_TIMEZONES: %w", err)
+	}
+
+	return dates.WithTimezones(mcpserver.NewTimezoneResolver(client, locations)), nil
+}
+
+// newCacheFromEnv creates the response cache configured by BAMBOOHR_CACHE, BAMBOOHR_CACHE_FILE
+// and BAMBOOHR_CACHE_TTLS. The cache is in memory by default and nil if disabled. When employees
+// are synced, the directory and employees are not cached, as the sync keeps its own copy and
+// must see the latest version of changed employees.
+func newCacheFromEnv(syncing bool) (*bamboohr.Cache, error) {
+	if value := os.Getenv("BAMBOOHR_CACHE"); value != "" {
+		enabled, err := strconv.ParseBool(value)
+		if err != nil {
+			return nil, fmt.Errorf("BAMBOOHR_CACHE must be true or false: %w", err)
+		}
+		if !enabled {
+			return nil, nil
+		}
+	}
+
+	ttls, err := bamboohr.ParseCacheTTLs(os.Getenv("BAMBOOHR_CACHE_TTLS"), bamboohr.DefaultCacheTTLs)
+	if err != nil {
+		return nil, fmt.Errorf("BAMBOOHR_CACHE_TTLS: %w", err)
+	}
+
+	if syncing {
+		ttls[bamboohr.CacheDirectory] = 0
+		ttls[bamboohr.CacheEmployee] = 0
+	}
+
+	if path := os.Getenv("BAMBOOHR_CACHE_FILE"); path != "" {
+		cache, err := bamboohr.OpenCache(path, ttls)
+		if err != nil {
+			return nil, fmt.Errorf("BAMBOOHR_CACHE_FILE: %w", err)
+		}
+		return cache, nil
+	}
+
+	return bamboohr.NewCache(ttls), nil
+}
+
+// syncIntervalFromEnv returns the employee sync interval set by BAMBOOHR_SYNC_INTERVAL, or zero
+// if employees are not synced
+func syncIntervalFromEnv() (time.Duration, error) {
+	value := os.Getenv("BAMBOOHR_SYNC_INTERVAL")
+	if value == "" {
+		return 0, nil
+	}
+
+	interval, err := time.ParseDuration(value)
+	if err != nil || interval <= 0 {
+		return 0, fmt.Errorf("BAMBOOHR_SYNC_INTERVAL must be a positive duration such as 5m: %q", value)
+	}
+
+	return interval, nil
+}
+
+// decorateClientFromEnv wraps the client with the employee sync if syncInterval is set, with the
+// policy set by BAMBOOHR_READ_ONLY and BAMBOOHR_ALLOWED_EMPLOYEES, and with an audit log of every
+// call if BAMBOOHR_AUDIT_LOG is set. The local store queried by the history tools, if any, is
+// returned with the same policy and audit log.
+func decorateClientFromEnv(ctx context.Context, client mcpserver.BambooHR, history mcpserver.TimeOffHistory, syncInterval time.Duration) (mcpserver.BambooHR, mcpserver.TimeOffHistory, error) {
+	if syncInterval > 0 {
+		employeeSync := mcpserver.NewEmployeeSync(client)
+		go employeeSync.Run(ctx, syncInterval, func(err error) {
+			fmt.Fprintf(os.Stderr, "Employee sync error: %v\n", err)
+		})
+		client = employeeSync
+	}
+
+	var policy mcpserver.Policy
+	if value := os.Getenv("BAMBOOHR_READ_ONLY"); value != "" {
+		readOnly, err := strconv.ParseBool(value)
+		if err != nil {
+			return nil, nil, fmt.Errorf("BAMBOOHR_READ_ONLY must be true or false: %w", err)
+		}
+		policy.ReadOnly = readOnly
+	}
+
+	ids, err := mcpserver.ParseEmployeeIDs(os.Getenv("BAMBOOHR_ALLOWED_EMPLOYEES"))
+	if err != nil {
+		return nil, nil, fmt.Errorf("BAMBOOHR_ALLOWED_EMPLOYEES: %w", err)
+	}
+	policy.EmployeeIDs = ids
+
+	if policy.ReadOnly || len(policy.EmployeeIDs) > 0 {
+		client = mcpserver.NewPolicyEnforcer(client, policy)
+	}
+	if history != nil && len(policy.EmployeeIDs) > 0 {
+		history = mcpserver.NewHistoryPolicyEnforcer(history, policy)
+	}
+
+	// The audit log wraps the policy so that refused calls are recorded too
+	if path := os.Getenv("BAMBOOHR_AUDIT_LOG"); path != "" {
+		output := os.Stderr
+		if path != "-" {
+			file, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+			if err != nil {
+				return nil, nil, fmt.Errorf("BAMBOOHR_AUDIT_LOG: %w", err)
+			}
+			output = file
+		}
+		logger := slog.New(slog.NewJSONHandler(output, &slog.HandlerOptions{Level: slog.LevelDebug}))
+		client = mcpserver.NewAuditLog(client, logger)
+		if history != nil {
+			history = mcpserver.NewHistoryAuditLog(history, logger)
+		}
+	}
+
+	return client, history, nil
+}
+
+// serverOptionsFromEnv returns the server options configured by BAMBOOHR_EMPLOYEE_ID and
+// BAMBOOHR_HOURS_PER_DAY
+func serverOptionsFromEnv() ([]mcpserver.Option, error) {
+	var opts []mcpserver.Option
+
+	if value := os.Getenv("BAMBOOHR_EMPLOYEE_ID"); value != "" {
+		id, err := strconv.Atoi(value)
+		if err != nil || id <= 0 {
+			return nil, fmt.Errorf("BAMBOOHR_EMPLOYEE_ID must be a positive employee ID, got %q", value)
+		}
+		opts = append(opts, mcpserver.WithCurrentEmployee(id))
+	}
+
+	hours, err := hoursPerDayFromEnv()
+	if err != nil {
+		return nil, err
+	}
+	if hours > 0 {
+		opts = append(opts, mcpserver.WithHoursPerDay(hours))
+	}
+
+	if path := os.Getenv("BAMBOOHR_TEMPLATES"); path != "" {
+		if os.Getenv("BAMBOOHR_EMPLOYEE_ID") == "" {
+			return nil, fmt.Errorf("BAMBOOHR_TEMPLATES requires BAMBOOHR_EMPLOYEE_ID, as templates are kept per employee")
+		}
+		templates, err := mcpserver.OpenTemplateStore(path)
+		if err != nil {
+			return nil, fmt.Errorf("BAMBOOHR_TEMPLATES: %w", err)
+		}
+		opts = append(opts, mcpserver.WithTemplates(templates))
+	}
+
+	return opts, nil
+}
+
+// hoursPerDayFromEnv reads BAMBOOHR_HOURS_PER_DAY, returning zero when it isn't set
+func hoursPerDayFromEnv() (float64, error) {
+	value := os.Getenv("BAMBOOHR_HOURS_PER_DAY")
+	if value == "" {
+		return 0, nil
+	}
+
+	hours, err := strconv.ParseFloat(value, 64)
+	if err != nil || hours <= 0 || hours > 24 {
+		return 0, fmt.Errorf("BAMBOOHR_HOURS_PER_DAY must be a number of hours between 0 and 24, got %q", value)
+	}
+	return hours, nil
+}
+
+// checkHTTPAddr refuses to serve MCP without BAMBOOHR_HTTP_TOKEN on an address other hosts can
+// reach, as every tool runs with the company's API key
+func checkHTTPAddr(addr, token string) error {
+	if token != "" {
+		return nil
+	}
+
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return fmt.Errorf("BAMBOOHR_HTTP_ADDR must be host:port, e.g. 127.0.0.1:8080: %w", err)
+	}
+	if host == "localhost" {
+		return nil
+	}
+	if ip := net.ParseIP(host); ip != nil && ip.IsLoopback() {
+		return nil
+	}
+	return fmt.Errorf("BAMBOOHR_HTTP_TOKEN is required to serve on %s; set it or listen on 127.0.0.1", addr)
+}
+
+// runStoreSync implements the sync command, which downloads employees, time-off requests and
+// balances into the local store at BAMBOOHR_STORE
+func runStoreSync(client *bamboohr.Client, args []string) error {
+	path := os.Getenv("BAMBOOHR_STORE")
+	if path == "" {
+		return fmt.Errorf("BAMBOOHR_STORE environment variable is required for sync")
+	}
+
+	// By default sync last year, for history, through the end of this year, for planned time off
+	now := time.Now()
+	flags := flag.NewFlagSet("sync", flag.ContinueOnError)
+	start := flags.String("start", fmt.Sprintf("%d-01-01", now.Year()-1), "start of the time-off requests to sync (YYYY-MM-DD)")
+	end := flags.String("end", fmt.Sprintf("%d-12-31", now.Year()), "end of the time-off requests to sync (YYYY-MM-DD)")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	st, err := store.Open(path)
+	if err != nil {
+		return err
+	}
+	defer st.Close()
+
+	result, err := st.Sync(client, *start, *end, now)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(os.Stderr, "Synced %d employees, %d time-off requests and %d balances from %s to %s\n",
+		result.Employees, result.Requests, result.Balances, *start, *end)
+	return nil
+}
+
+// runBulkCreate implements the bulk-create command, which creates many time-off requests for one
+// employee from a list of dates, a recurrence rule or a CSV file
+func runBulkCreate(client mcpserver.BambooHR, dates *mcpserver.DateParser, args []string) error {
+	flags := flag.NewFlagSet("bulk-create", flag.ContinueOnError)
+	employee := flags.String("employee", os.Getenv("BAMBOOHR_EMPLOYEE_ID"), "ID of the employee (defaults to BAMBOOHR_EMPLOYEE_ID)")
+	timeOffType := flags.String("type", "", "ID or name of the time off type")
+	list := flags.String("dates", "", "dates or ranges separated by semicolons")
+	every := flags.String("every", "", "recurrence rule such as \"friday until june\"")
+	csvPath := flags.String("csv", "", "CSV file of start, end, amount and note, or - for stdin")
+	amount := flags.Float64("amount", 0, "amount of each entry (defaults to its working days)")
+	unit := flags.String("unit", "", "days or hours (defaults to the units of the time off type)")
+	note := flags.String("note", "", "employee note on every entry")
+	dryRun := flags.Bool("dry-run", false, "check the entries without creating them")
+	ignoreBalance := flags.Bool("ignore-balance", false, "create entries even when they exceed the balance")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	employeeID, err := strconv.Atoi(*employee)
+	if err != nil {
+		return fmt.Errorf("-employee must be an employee ID, got %q", *employee)
+	}
+
+	hoursPerDay, err := hoursPerDayFromEnv()
+	if err != nil {
+		return err
+	}
+
+	request := mcpserver.BulkTimeOffRequest{
+		EmployeeID:    employeeID,
+		TimeOffType:   *timeOffType,
+		Dates:         *list,
+		Amount:        *amount,
+		Unit:          *unit,
+		Note:          *note,
+		DryRun:        *dryRun,
+		IgnoreBalance: *ignoreBalance,
+	}
+	if *every != "" {
+		request.Recurrence = "every " + strings.TrimPrefix(strings.TrimSpace(*every), "every ")
+	}
+	if *csvPath != "" {
+		var data []byte
+		if *csvPath == "-" {
+			data, err = io.ReadAll(os.Stdin)
+		} else {
+			data, err = os.ReadFile(*csvPath)
+		}
+		if err != nil {
+			return fmt.Errorf("reading CSV: %w", err)
+		}
+		request.CSV = string(data)
+	}
+
+	result, err := mcpserver.BulkCreateTimeOff(client, dates, request, hoursPerDay)
+	if err != nil {
+		return err
+	}
+
+	for _, item := range result.Items {
+		line := fmt.Sprintf("%s\t%s\t%v\t%s", item.Start, item.End, item.Amount, item.Status)
+		if item.RequestID != "" {
+			line += "\trequest " + item.RequestID
+		}
+		if item.Error != "" {
+			line += "\t" + item.Error
+		}
+		fmt.Println(line)
+	}
+	fmt.Fprintln(os.Stderr, mcpserver.BulkTimeOffSummary(*result))
+
+	if result.Skipped > 0 || result.Failed > 0 {
+		return fmt.Errorf("%d of %d entries were not created", result.Skipped+result.Failed, len(result.Items))
+	}
+	return nil
+}
+
+func main() {
+	// Check for version flag
+	if len(os.Args) > 1 && (os.Args[1] == "--version" || os.Args[1] == "-v") {
+		fmt.Printf("BambooHR MCP Server v%s\n", mcpserver.Version)
+		os.Exit(0)
+	}
+
+	// Print version information
+	fmt.Fprintf(os.Stderr, "BambooHR MCP Server v%s starting...\n", mcpserver.Version)
+
+	// Stop serving on an interrupt, so that the cache and store are written and closed on exit
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	err := run(ctx)
+	stop()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// run configures the client from the environment and runs the command or serves MCP until ctx
+// is done. It returns instead of exiting so that the deferred cache flush and store close run.
+func run(ctx context.Context) error {
+	// Get configuration from environment variables
+	apiKey := os.Getenv("BAMBOOHR_API_KEY")
+	company := os.Getenv("BAMBOOHR_COMPANY")
+
+	if apiKey == "" {
+		return fmt.Errorf("BAMBOOHR_API_KEY environment variable is required")
+	}
+
+	if company == "" {
+		return fmt.Errorf("BAMBOOHR_COMPANY environment variable is required")
+	}
+
+	location, err := timezoneFromEnv()
+	if err != nil {
+		return err
+	}
+
+	// Create BambooHR client
+	apiClient := bamboohr.NewClient(company, apiKey)
+	apiClient.Location = location
+
+	// The sync command populates the local store and exits
+	if len(os.Args) > 1 && os.Args[1] == "sync" {
+		return runStoreSync(apiClient, os.Args[2:])
+	}
+
+	syncInterval, err := syncIntervalFromEnv()
+	if err != nil {
+		return err
+	}
+
+	cache, err := newCacheFromEnv(syncInterval > 0)
+	if err != nil {
+		return err
+	}
+	apiClient.Cache = cache
+	if cache != nil {
+		// The cache file is written in the background, so write the latest changes on exit
+		defer cache.Flush()
+	}
+
+	opts, err := serverOptionsFromEnv()
+	if err != nil {
+		return err
+	}
+
+	// With a local store, reads fall back to it while BambooHR is unreachable
+	var client mcpserver.BambooHR = apiClient
+	var history mcpserver.TimeOffHistory
+	if path := os.Getenv("BAMBOOHR_STORE"); path != "" {
+		st, err := store.Open(path)
+		if err != nil {
+			return fmt.Errorf("BAMBOOHR_STORE: %w", err)
+		}
+		defer st.Close()
+
+		client = mcpserver.NewOfflineFallback(client, st, location)
+		history = st
+		opts = append(opts, mcpserver.WithOfflineNotice(client))
+	}
+
+	client, history, err = decorateClientFromEnv(ctx, client, history, syncInterval)
+	if err != nil {
+		return err
+	}
+
+	// Create date parser for relative date arguments
+	dates, err := newDateParserFromEnv(client, location)
+	if err != nil {
+		return err
+	}
+
+	// The bulk-create command creates time off through the same policy and audit log and exits
+	if len(os.Args) > 1 && os.Args[1] == "bulk-create" {
+		return runBulkCreate(client, dates, os.Args[2:])
+	}
+
+	// Create MCP server
+	s := mcpserver.New(client, dates, opts...)
+	if history != nil {
+		mcpserver.RegisterStoreTools(s, history, dates)
+	}
+
+	// Serve over HTTP if an address is configured, otherwise over stdio
+	if addr := os.Getenv("BAMBOOHR_HTTP_ADDR"); addr != "" {
+		token := os.Getenv("BAMBOOHR_HTTP_TOKEN")
+		if err := checkHTTPAddr(addr, token); err != nil {
+			return err
+		}
+
+		httpServer := &http.Server{
+			Addr:              addr,
+			Handler:           mcpserver.NewHTTPHandler(s, client, dates, token, os.Getenv("BAMBOOHR_CALENDAR_TOKEN")),
+			ReadHeaderTimeout: 10 * time.Second,
+		}
+
+		shutdown := make(chan error, 1)
+		go func() {
+			<-ctx.Done()
+			timeout, cancel := context.WithTimeout(context.Background(), httpShutdownTimeout)
+			defer cancel()
+			shutdown <- httpServer.Shutdown(timeout)
+		}()
+
+		fmt.Fprintf(os.Stderr, "Listening on %s\n", addr)
+		if err := httpServer.ListenAndServe(); !errors.Is(err, http.ErrServerClosed) {
+			return fmt.Errorf("server: %w", err)
+		}
+		return <-shutdown
+	}
+
+	// Start the server
+	if err := server.NewStdioServer(s).Listen(ctx, os.Stdin, os.Stdout); err != nil && !errors.Is(err, context.Canceled) {
+		return fmt.Errorf("server: %w", err)
+	}
+	return nil
+}
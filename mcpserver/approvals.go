@@ -0,0 +1,290 @@
+package mcpserver
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+
+	"bamboohr-mcp-server/bamboohr"
+)
+
+// TeamAbsence is time off of a colleague that overlaps a pending request
+type TeamAbsence struct {
+	EmployeeID string `json:"employeeId"`
+	Name       string `json:"name"`
+	Start      string `json:"start"`
+	End        string `json:"end"`
+	Status     string `json:"status" jsonschema:"description=approved, or requested if the absence is also waiting for approval"`
+}
+
+// PendingApproval is a time-off request waiting for approval, with what an approver needs to
+// decide on it
+type PendingApproval struct {
+	Request      bamboohr.TimeOffRequest `json:"request"`
+	EmployeeName string                  `json:"employeeName"`
+	JobTitle     string                  `json:"jobTitle,omitempty"`
+	Department   string                  `json:"department,omitempty"`
+	Balance      *float64                `json:"balance,omitempty" jsonschema:"description=The employee's remaining balance of the requested time off type"`
+	BalanceUnit  string                  `json:"balanceUnit,omitempty"`
+	BalanceAfter *float64                `json:"balanceAfter,omitempty" jsonschema:"description=The remaining balance if the request is approved"`
+	Overlaps     []TeamAbsence           `json:"overlaps" jsonschema:"description=Time off of the employee's team that overlaps the request"`
+}
+
+// PendingApprovalsResult is the structured output of list_pending_approvals
+type PendingApprovalsResult struct {
+	ManagerID   string            `json:"managerId,omitempty" jsonschema:"description=The manager whose reports' requests are listed, empty for every request the API key may approve"`
+	ManagerName string            `json:"managerName,omitempty"`
+	Start       string            `json:"start" jsonschema:"description=Start of the period searched (YYYY-MM-DD)"`
+	End         string            `json:"end" jsonschema:"description=End of the period searched (YYYY-MM-DD)"`
+	Approvals   []PendingApproval `json:"approvals"`
+	WorkDay     WorkDay           `json:"workDay" jsonschema:"description=The working day used to convert between days and hours"`
+}
+
+func handleListPendingApprovals(client BambooHR, dates *DateParser, me int, hoursPerDay float64) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		format, err := toolFormat(request, FormatCompact)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		locale, err := toolLocale(request, dates)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		// Default to the caller's reports when the caller is known, and to everything the API key
+		// may approve otherwise
+		managerID := 0
+		scope := request.GetString("managerId", "")
+		if scope == "" && me != 0 {
+			scope = "me"
+		}
+		if scope != "" && !strings.EqualFold(scope, "all") {
+			managerID, err = resolveEmployeeID(scope, me)
+			if err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("managerId: %s", err.Error())), nil
+			}
+		}
+
+		// Resolve the period in the manager's timezone
+		parser := dates.ForEmployee(managerID)
+		today := parser.Today()
+		period, err := parser.ResolveRange(request.GetString("start", ""), request.GetString("end", ""), DateRange{Start: today, End: today.AddDate(1, 0, -1)})
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		workDay := companyWorkDay(client, hoursPerDay)
+
+		result, err := listPendingApprovals(client, managerID, period, workDay)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Failed to list pending approvals: %s", err.Error())), nil
+		}
+
+		return renderToolResult(result, pendingApprovalsView(result, locale), format), nil
+	}
+}
+
+// listPendingApprovals finds the requested time off in the period that the API key may approve,
+// limited to the manager's reports unless managerID is zero, and adds each employee's
+// remaining balance and the overlapping time off of their team. Requests and balances in
+// different units are compared by converting with the working day.
+func listPendingApprovals(client BambooHR, managerID int, period DateRange, workDay WorkDay) (PendingApprovalsResult, error) {
+	result := PendingApprovalsResult{
+		Start:     period.StartYMD(),
+		End:       period.EndYMD(),
+		Approvals: []PendingApproval{},
+		WorkDay:   workDay,
+	}
+
+	directory, err := client.GetEmployeeDirectory()
+	if err != nil {
+		return result, fmt.Errorf("failed to get employee directory: %w", err)
+	}
+	chart := newOrgChart(directory)
+
+	var reports map[string]bool
+	if managerID != 0 {
+		result.ManagerID = strconv.Itoa(managerID)
+		manager, ok := chart.employees[result.ManagerID]
+		if !ok {
+			return result, fmt.Errorf("employee %d is not in the employee directory", managerID)
+		}
+		result.ManagerName = manager.DisplayName
+
+		reports = make(map[string]bool)
+		for _, id := range chart.reportIDs(managerID) {
+			reports[strconv.Itoa(id)] = true
+		}
+	}
+
+	requests, err := client.SearchTimeOffRequests(bamboohr.TimeOffRequestQuery{
+		Start:    result.Start,
+		End:      result.End,
+		Statuses: []string{"requested"},
+		Action:   bamboohr.TimeOffActionApprove,
+	})
+	if err != nil {
+		return result, fmt.Errorf("failed to get requested time off: %w", err)
+	}
+
+	var pending []bamboohr.TimeOffRequest
+	for _, request := range requests {
+		if reports == nil || reports[request.EmployeeID] {
+			pending = append(pending, request)
+		}
+	}
+	if len(pending) == 0 {
+		return result, nil
+	}
+
+	sort.SliceStable(pending, func(i, j int) bool {
+		return pending[i].Start < pending[j].Start
+	})
+
+	whosOut, err := client.GetWhosOut(result.Start, result.End)
+	if err != nil {
+		return result, fmt.Errorf("failed to get who's out: %w", err)
+	}
+
+	// Absences of the team: approved time off from who's out, and the other pending requests
+	var absences []TeamAbsence
+	for _, entry := range whosOut {
+		if entry.Type == "timeOff" && entry.EmployeeID != "" {
+			absences = append(absences, TeamAbsence{
+				EmployeeID: entry.EmployeeID.String(),
+				Name:       entry.Name,
+				Start:      entry.Start,
+				End:        entry.End,
+				Status:     "approved",
+			})
+		}
+	}
+	for _, request := range pending {
+		absences = append(absences, TeamAbsence{
+			EmployeeID: request.EmployeeID,
+			Name:       request.Name,
+			Start:      request.Start,
+			End:        request.End,
+			Status:     "requested",
+		})
+	}
+
+	balances := make(map[string][]bamboohr.TimeOffBalance)
+	for _, request := range pending {
+		approval := PendingApproval{
+			Request:      request,
+			EmployeeName: request.Name,
+			Overlaps:     []TeamAbsence{},
+		}
+
+		if employee, ok := chart.employees[request.EmployeeID]; ok {
+			approval.EmployeeName = employee.DisplayName
+			approval.JobTitle = employee.JobTitle
+			approval.Department = employee.Department
+		}
+
+		employeeBalances, ok := balances[request.EmployeeID]
+		if !ok {
+			employeeID, err := strconv.Atoi(request.EmployeeID)
+			if err != nil {
+				return result, fmt.Errorf("invalid employee ID %q in request %s", request.EmployeeID, request.ID)
+			}
+			employeeBalances, err = client.GetTimeOffBalance(employeeID)
+			if err != nil {
+				return result, fmt.Errorf("failed to get time-off balance for employee %d: %w", employeeID, err)
+			}
+			balances[request.EmployeeID] = employeeBalances
+		}
+
+		for _, balance := range employeeBalances {
+			if balance.TimeOffType != request.Type.ID {
+				continue
+			}
+
+			remaining := float64(balance.Balance)
+			approval.Balance = &remaining
+			approval.BalanceUnit = balance.Units
+			after := roundAmount(remaining - workDay.convert(float64(request.Amount.Amount), request.Amount.Unit, balance.Units))
+			approval.BalanceAfter = &after
+			break
+		}
+
+		for _, absence := range absences {
+			if absence.EmployeeID == request.EmployeeID || !chart.sameTeam(absence.EmployeeID, request.EmployeeID) {
+				continue
+			}
+			if absence.Start <= request.End && absence.End >= request.Start {
+				approval.Overlaps = append(approval.Overlaps, absence)
+			}
+		}
+
+		result.Approvals = append(result.Approvals, approval)
+	}
+
+	return result, nil
+}
+
+// pendingApprovalsView shows the pending approvals as text, one request per line with its
+// balance and overlaps
+func pendingApprovalsView(result PendingApprovalsResult, locale Locale) textView {
+	title := pluralize(len(result.Approvals), "time-off request", "time-off requests") + " waiting for approval"
+	if result.ManagerID != "" {
+		title += fmt.Sprintf(" from the reports of %s (ID %s)", result.ManagerName, result.ManagerID)
+	}
+	title += fmt.Sprintf(" between %s and %s", locale.FormatDate(result.Start), locale.FormatDate(result.End))
+
+	view := textView{
+		Title:   title,
+		Columns: []string{"Request ID", "Employee ID", "Name", "Start", "End", "Type", "Amount", "Unit", "Balance", "Balance After", "Overlaps"},
+	}
+
+	for _, approval := range result.Approvals {
+		request := approval.Request
+
+		item := fmt.Sprintf("%s (ID %s): %s", approval.EmployeeName, request.EmployeeID, summarizeTimeOffRequest(request, locale, result.WorkDay))
+		var balance, balanceAfter string
+		if approval.Balance != nil {
+			balance = strconv.FormatFloat(*approval.Balance, 'f', -1, 64)
+			item += fmt.Sprintf("; %s left", result.WorkDay.format(*approval.Balance, approval.BalanceUnit))
+		}
+		if approval.BalanceAfter != nil {
+			balanceAfter = strconv.FormatFloat(*approval.BalanceAfter, 'f', -1, 64)
+			item += fmt.Sprintf(", %s after approval", result.WorkDay.format(*approval.BalanceAfter, approval.BalanceUnit))
+		}
+
+		var overlaps []string
+		for _, absence := range approval.Overlaps {
+			dates := locale.FormatDate(absence.Start)
+			if absence.End != absence.Start {
+				dates += " to " + locale.FormatDate(absence.End)
+			}
+			overlaps = append(overlaps, fmt.Sprintf("%s (%s, %s)", absence.Name, absence.Status, dates))
+		}
+		if len(overlaps) > 0 {
+			item += "; overlaps " + strings.Join(overlaps, ", ")
+		}
+
+		view.Items = append(view.Items, item)
+		view.Rows = append(view.Rows, []string{
+			request.ID,
+			request.EmployeeID,
+			approval.EmployeeName,
+			locale.FormatDate(request.Start),
+			locale.FormatDate(request.End),
+			request.Type.Name,
+			strconv.FormatFloat(float64(request.Amount.Amount), 'f', -1, 64),
+			request.Amount.Unit,
+			balance,
+			balanceAfter,
+			strings.Join(overlaps, "; "),
+		})
+	}
+
+	return view
+}
@@ -0,0 +1,99 @@
+package mcpserver
+
+import (
+	"encoding/json"
+	"testing"
+
+	"bamboohr-mcp-server/bamboohr"
+)
+
+// approvalsTestRequest creates a request for the pending approvals tests
+func approvalsTestRequest(id, employeeID, name, start, end, status string, amount float64, approve bool) bamboohr.TimeOffRequest {
+	request := bamboohr.TimeOffRequest{ID: id, EmployeeID: employeeID, Name: name, Start: start, End: end}
+	request.Type.ID = "78"
+	request.Type.Name = "Vacation"
+	request.Amount.Unit = "days"
+	request.Amount.Amount = bamboohr.FlexibleFloat(amount)
+	request.Status.Status = status
+	request.Actions.Approve = approve
+	return request
+}
+
+func newApprovalsTestFake() *fakeBambooHR {
+	fake := newFakeBambooHR(orgChartTestEmployees()...)
+	fake.requests[3] = []bamboohr.TimeOffRequest{
+		approvalsTestRequest("12", "3", "Cleo Cruz", "2025-10-20", "2025-10-20", "requested", 1, true),
+	}
+	fake.requests[4] = []bamboohr.TimeOffRequest{
+		approvalsTestRequest("10", "4", "Dan Diaz", "2025-10-06", "2025-10-08", "requested", 3, true),
+		approvalsTestRequest("13", "4", "Dan Diaz", "2025-11-03", "2025-11-03", "approved", 1, true),
+	}
+	fake.requests[5] = []bamboohr.TimeOffRequest{
+		approvalsTestRequest("11", "5", "Eve Evans", "2025-10-07", "2025-10-07", "requested", 1, true),
+		approvalsTestRequest("14", "5", "Eve Evans", "2025-12-01", "2025-12-01", "requested", 1, false),
+	}
+	fake.balances[4] = []bamboohr.TimeOffBalance{{TimeOffType: "78", Name: "Vacation", Units: "days", Balance: 10}}
+	fake.whosOut = []bamboohr.WhosOutEntry{
+		{ID: "20", Type: "timeOff", EmployeeID: "5", Name: "Eve Evans", Start: "2025-10-08", End: "2025-10-09"},
+		{ID: "21", Type: "timeOff", EmployeeID: "3", Name: "Cleo Cruz", Start: "2025-10-06", End: "2025-10-06"},
+		{ID: "22", Type: "holiday", Name: "Labour Day", Start: "2025-10-06", End: "2025-10-06"},
+	}
+	return fake
+}
+
+func TestListPendingApprovals(t *testing.T) {
+	s := New(newApprovalsTestFake(), newTestDateParser(), WithCurrentEmployee(2))
+
+	text, isError := callTool(t, s, "list_pending_approvals", map[string]string{})
+	expected := "2 time-off requests waiting for approval from the reports of Ben Brown (ID 2) between 2025-09-03 and 2026-09-02:\n" +
+		"- Dan Diaz (ID 4): 2025-10-06 to 2025-10-08: Vacation, 3 days / 24 hours (requested) [request 10]; 10 days / 80 hours left, 7 days / 56 hours after approval; overlaps Eve Evans (approved, 2025-10-08 to 2025-10-09), Eve Evans (requested, 2025-10-07)\n" +
+		"- Eve Evans (ID 5): 2025-10-07: Vacation, 1 day / 8 hours (requested) [request 11]; overlaps Dan Diaz (requested, 2025-10-06 to 2025-10-08)"
+	if isError || text != expected {
+		t.Errorf("Expected %q, got %q", expected, text)
+	}
+
+	text, isError = callTool(t, s, "list_pending_approvals", map[string]string{"managerId": "all", "format": "full"})
+	if isError {
+		t.Fatalf("Unexpected tool error: %s", text)
+	}
+
+	var result PendingApprovalsResult
+	if err := json.Unmarshal([]byte(text), &result); err != nil {
+		t.Fatalf("Failed to decode result: %v", err)
+	}
+	if result.ManagerID != "" || len(result.Approvals) != 3 {
+		t.Fatalf("Expected 3 approvals across the company, got %+v", result)
+	}
+
+	dan := result.Approvals[0]
+	if dan.Request.ID != "10" || dan.Balance == nil || *dan.Balance != 10 || dan.BalanceAfter == nil || *dan.BalanceAfter != 7 {
+		t.Errorf("Unexpected approval for Dan: %+v", dan)
+	}
+
+	// Cleo reports to Ava, as does Ben, who is not out
+	cleo := result.Approvals[2]
+	if cleo.Request.ID != "12" || cleo.Department != "Finance" || len(cleo.Overlaps) != 0 || cleo.Balance != nil {
+		t.Errorf("Unexpected approval for Cleo: %+v", cleo)
+	}
+}
+
+func TestListPendingApprovals_Errors(t *testing.T) {
+	fake := newApprovalsTestFake()
+
+	s := New(fake, newTestDateParser())
+	tests := []map[string]string{
+		{"managerId": "me"},
+		{"managerId": "99"},
+		{"start": "not a date"},
+	}
+	for _, arguments := range tests {
+		if text, isError := callTool(t, s, "list_pending_approvals", arguments); !isError {
+			t.Errorf("Expected tool error for %v, got %q", arguments, text)
+		}
+	}
+
+	fake.err = errNotReachable
+	if text, isError := callTool(t, s, "list_pending_approvals", map[string]string{}); !isError {
+		t.Errorf("Expected tool error when BambooHR is unreachable, got %q", text)
+	}
+}
@@ -0,0 +1,179 @@
+package mcpserver
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"bamboohr-mcp-server/bamboohr"
+	"bamboohr-mcp-server/store"
+)
+
+// auditLogger records calls in the audit log
+type auditLogger struct {
+	logger *slog.Logger
+}
+
+// auditedClient is a BambooHR decorator that logs every call with its arguments, duration and outcome
+type auditedClient struct {
+	auditLogger
+	next BambooHR
+}
+
+// NewAuditLog wraps the client so that every BambooHR call is recorded in the logger. Writes are
+// logged at info level and reads at debug level; failed calls are logged at warn level.
+func NewAuditLog(client BambooHR, logger *slog.Logger) BambooHR {
+	return &auditedClient{auditLogger: auditLogger{logger}, next: client}
+}
+
+// record logs a completed call
+func (a auditLogger) record(operation string, write bool, started time.Time, err error, args ...any) {
+	level := slog.LevelDebug
+	if write {
+		level = slog.LevelInfo
+	}
+
+	attrs := append([]any{
+		slog.String("operation", operation),
+		slog.Bool("write", write),
+		slog.Duration("duration", time.Since(started)),
+	}, args...)
+
+	if err != nil {
+		level = slog.LevelWarn
+		attrs = append(attrs, slog.String("error", err.Error()))
+	}
+
+	a.logger.Log(context.Background(), level, "bamboohr call", attrs...)
+}
+
+func (a *auditedClient) Company() string {
+	return a.next.Company()
+}
+
+func (a *auditedClient) GetTimeOffRequests(employeeID int, start, end string) ([]bamboohr.TimeOffRequest, error) {
+	started := time.Now()
+	requests, err := a.next.GetTimeOffRequests(employeeID, start, end)
+	a.record("GetTimeOffRequests", false, started, err,
+		slog.Int("employeeId", employeeID), slog.String("start", start), slog.String("end", end))
+	return requests, err
+}
+
+func (a *auditedClient) SearchTimeOffRequests(query bamboohr.TimeOffRequestQuery) ([]bamboohr.TimeOffRequest, error) {
+	started := time.Now()
+	requests, err := a.next.SearchTimeOffRequests(query)
+	a.record("SearchTimeOffRequests", false, started, err,
+		slog.Int("employeeId", query.EmployeeID), slog.String("start", query.Start), slog.String("end", query.End),
+		slog.Any("statuses", query.Statuses), slog.Any("typeIds", query.TypeIDs), slog.String("action", query.Action))
+	return requests, err
+}
+
+func (a *auditedClient) GetTimeOffBalance(employeeID int) ([]bamboohr.TimeOffBalance, error) {
+	started := time.Now()
+	balances, err := a.next.GetTimeOffBalance(employeeID)
+	a.record("GetTimeOffBalance", false, started, err, slog.Int("employeeId", employeeID))
+	return balances, err
+}
+
+func (a *auditedClient) CreateTimeOffRequest(employeeID int, request bamboohr.TimeOffRequestCreate) (*bamboohr.TimeOffRequest, error) {
+	started := time.Now()
+	created, err := a.next.CreateTimeOffRequest(employeeID, request)
+
+	args := []any{
+		slog.Int("employeeId", employeeID),
+		slog.Int("timeOffTypeId", request.TimeOffTypeID),
+		slog.String("start", request.Start),
+		slog.String("end", request.End),
+		slog.Float64("amount", request.Amount),
+	}
+	if request.PreviousRequest != 0 {
+		args = append(args, slog.Int("previousRequest", request.PreviousRequest))
+	}
+	if created != nil {
+		args = append(args, slog.String("requestId", created.ID))
+	}
+	a.record("CreateTimeOffRequest", true, started, err, args...)
+
+	return created, err
+}
+
+func (a *auditedClient) GetEmployeeDirectory() (*bamboohr.EmployeeDirectory, error) {
+	started := time.Now()
+	directory, err := a.next.GetEmployeeDirectory()
+	a.record("GetEmployeeDirectory", false, started, err)
+	return directory, err
+}
+
+func (a *auditedClient) GetEmployee(employeeID int) (*bamboohr.Employee, error) {
+	started := time.Now()
+	employee, err := a.next.GetEmployee(employeeID)
+	a.record("GetEmployee", false, started, err, slog.Int("employeeId", employeeID))
+	return employee, err
+}
+
+func (a *auditedClient) GetChangedEmployees(since time.Time) (*bamboohr.EmployeeChanges, error) {
+	started := time.Now()
+	changes, err := a.next.GetChangedEmployees(since)
+	a.record("GetChangedEmployees", false, started, err, slog.Time("since", since))
+	return changes, err
+}
+
+func (a *auditedClient) GetTimeOffTypes() (*bamboohr.TimeOffTypes, error) {
+	started := time.Now()
+	types, err := a.next.GetTimeOffTypes()
+	a.record("GetTimeOffTypes", false, started, err)
+	return types, err
+}
+
+func (a *auditedClient) GetTimeOffPolicies() ([]bamboohr.TimeOffPolicy, error) {
+	started := time.Now()
+	policies, err := a.next.GetTimeOffPolicies()
+	a.record("GetTimeOffPolicies", false, started, err)
+	return policies, err
+}
+
+func (a *auditedClient) GetEmployeeTimeOffPolicies(employeeID int) ([]bamboohr.EmployeeTimeOffPolicy, error) {
+	started := time.Now()
+	policies, err := a.next.GetEmployeeTimeOffPolicies(employeeID)
+	a.record("GetEmployeeTimeOffPolicies", false, started, err, slog.Int("employeeId", employeeID))
+	return policies, err
+}
+
+func (a *auditedClient) GetWhosOut(start, end string) ([]bamboohr.WhosOutEntry, error) {
+	started := time.Now()
+	entries, err := a.next.GetWhosOut(start, end)
+	a.record("GetWhosOut", false, started, err, slog.String("start", start), slog.String("end", end))
+	return entries, err
+}
+
+// auditedHistory is a TimeOffHistory decorator that logs every query of the local store
+type auditedHistory struct {
+	auditLogger
+	next TimeOffHistory
+}
+
+// NewHistoryAuditLog wraps the local store so that the history tools' queries are recorded in
+// the logger like BambooHR calls, at debug level or warn level if they fail
+func NewHistoryAuditLog(history TimeOffHistory, logger *slog.Logger) TimeOffHistory {
+	return &auditedHistory{auditLogger: auditLogger{logger}, next: history}
+}
+
+func (a *auditedHistory) SyncedAt() (time.Time, error) {
+	return a.next.SyncedAt()
+}
+
+func (a *auditedHistory) EmployeeDirectory() (*bamboohr.EmployeeDirectory, error) {
+	started := time.Now()
+	directory, err := a.next.EmployeeDirectory()
+	a.record("store.EmployeeDirectory", false, started, err)
+	return directory, err
+}
+
+func (a *auditedHistory) SearchTimeOffRequests(filter store.RequestFilter) ([]bamboohr.TimeOffRequest, error) {
+	started := time.Now()
+	requests, err := a.next.SearchTimeOffRequests(filter)
+	a.record("store.SearchTimeOffRequests", false, started, err,
+		slog.String("start", filter.Start), slog.String("end", filter.End), slog.Any("employeeIds", filter.EmployeeIDs),
+		slog.String("department", filter.Department), slog.Any("statuses", filter.Statuses), slog.String("timeOffType", filter.TimeOffType))
+	return requests, err
+}
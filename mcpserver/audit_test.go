@@ -0,0 +1,54 @@
+package mcpserver
+
+import (
+	"bytes"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"bamboohr-mcp-server/bamboohr"
+)
+
+func TestAuditLog(t *testing.T) {
+	fake := newFakeBambooHR(bamboohr.Employee{ID: "4", DisplayName: "Charlotte Abbott"})
+
+	var buf bytes.Buffer
+	client := NewAuditLog(fake, slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug})))
+
+	if _, err := client.GetEmployee(4); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if _, err := client.CreateTimeOffRequest(4, bamboohr.TimeOffRequestCreate{Status: "requested", Start: "2025-12-22", End: "2025-12-23", TimeOffTypeID: 78, Amount: 2}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	fake.err = errors.New("API request failed with status 503")
+	if _, err := client.GetTimeOffBalance(4); err == nil {
+		t.Fatal("Expected the underlying error to be returned")
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("Expected 3 audit entries, got %d: %q", len(lines), buf.String())
+	}
+
+	expected := []string{
+		"level=DEBUG msg=\"bamboohr call\" operation=GetEmployee write=false",
+		"level=INFO msg=\"bamboohr call\" operation=CreateTimeOffRequest write=true",
+		"level=WARN msg=\"bamboohr call\" operation=GetTimeOffBalance write=false",
+	}
+	for i, prefix := range expected {
+		if !strings.Contains(lines[i], prefix) {
+			t.Errorf("Expected entry %d to contain %q, got %q", i, prefix, lines[i])
+		}
+	}
+
+	if !strings.Contains(lines[1], "employeeId=4 timeOffTypeId=78 start=2025-12-22 end=2025-12-23 amount=2 requestId=1") {
+		t.Errorf("Expected write entry to record its arguments, got %q", lines[1])
+	}
+
+	if !strings.Contains(lines[2], `error="API request failed with status 503"`) {
+		t.Errorf("Expected failed call to record its error, got %q", lines[2])
+	}
+}
@@ -0,0 +1,650 @@
+package mcpserver
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+
+	"bamboohr-mcp-server/bamboohr"
+)
+
+// maxBulkEntries caps the time-off requests created by one bulk request
+const maxBulkEntries = 100
+
+// Outcomes of the entries of a bulk request
+const (
+	bulkEntryCreated = "created"
+	bulkEntryValid   = "valid"
+	bulkEntrySkipped = "skipped"
+	bulkEntryFailed  = "failed"
+)
+
+// explicitYear matches a year written out in a date expression
+var explicitYear = regexp.MustCompile(`\b\d{4}\b`)
+
+// BulkTimeOffRequest is time off to create for one employee in one go. The entries come from
+// exactly one of Dates, Recurrence or CSV.
+type BulkTimeOffRequest struct {
+	EmployeeID int
+	// TimeOffType is the ID or name of the time off type
+	TimeOffType string
+	// Dates are dates or ranges separated by semicolons or new lines
+	Dates string
+	// Recurrence is a rule such as "every friday until june"
+	Recurrence string
+	// CSV has start, end, amount and note columns, or any of start, end, amount, unit and note
+	// named in a header row
+	CSV string
+	// Amount is the amount of each entry, zero for the working days in it
+	Amount float64
+	// Unit is days or hours, empty for the units of the time off type
+	Unit string
+	Note string
+	// DryRun runs the checks without creating anything
+	DryRun bool
+	// IgnoreBalance creates entries even when they exceed the balance
+	IgnoreBalance bool
+}
+
+// bulkEntry is one entry of a bulk request, before it is validated
+type bulkEntry struct {
+	Period DateRange
+	Amount float64
+	Unit   string
+	Note   string
+}
+
+// BulkTimeOffItem is the outcome of one entry of a bulk request
+type BulkTimeOffItem struct {
+	Start     string  `json:"start"`
+	End       string  `json:"end"`
+	Amount    float64 `json:"amount" jsonschema:"description=The amount in the units of the time off type"`
+	Status    string  `json:"status" jsonschema:"description=created, valid (passed the checks in a dry run), skipped (failed a check and was not sent) or failed (BambooHR refused it)"`
+	RequestID string  `json:"requestId,omitempty"`
+	Error     string  `json:"error,omitempty"`
+}
+
+// BulkTimeOffResult is the structured output of bulk_create_time_off
+type BulkTimeOffResult struct {
+	EmployeeID      string            `json:"employeeId"`
+	TimeOffTypeID   string            `json:"timeOffTypeId"`
+	TimeOffTypeName string            `json:"timeOffTypeName,omitempty"`
+	Units           string            `json:"units"`
+	DryRun          bool              `json:"dryRun"`
+	Created         int               `json:"created"`
+	Valid           int               `json:"valid"`
+	Skipped         int               `json:"skipped"`
+	Failed          int               `json:"failed"`
+	TotalAmount     float64           `json:"totalAmount" jsonschema:"description=The amount of the created entries, or of the valid ones in a dry run"`
+	Balance         *float64          `json:"balance,omitempty" jsonschema:"description=The balance of the time off type before the entries, absent if it wasn't checked"`
+	WorkDay         WorkDay           `json:"workDay"`
+	Items           []BulkTimeOffItem `json:"items"`
+}
+
+// BulkCreateTimeOff checks every entry of the bulk request and creates the ones that pass. Badly
+// formed entries fail the whole request before anything is created. Entries overlapping existing
+// time off or an earlier entry, or exceeding the remaining balance, are skipped, and the others
+// are created even if BambooHR refuses some of them.
+func BulkCreateTimeOff(client BambooHR, dates *DateParser, request BulkTimeOffRequest, hoursPerDay float64) (*BulkTimeOffResult, error) {
+	typeIDs, err := resolveTimeOffTypeIDs(client, request.TimeOffType)
+	if err != nil {
+		return nil, err
+	}
+	if len(typeIDs) != 1 {
+		return nil, fmt.Errorf("give exactly one time off type")
+	}
+	typeID, err := strconv.Atoi(typeIDs[0])
+	if err != nil {
+		return nil, fmt.Errorf("timeOffTypeId must be a valid integer")
+	}
+
+	entries, err := bulkEntries(dates.ForEmployee(request.EmployeeID), request)
+	if err != nil {
+		return nil, err
+	}
+
+	// Every entry is validated before anything is created
+	prepared := make([]preparedTimeOffRequest, len(entries))
+	for i, entry := range entries {
+		input := timeOffRequestInput{
+			EmployeeID:    request.EmployeeID,
+			TimeOffTypeID: typeID,
+			Period:        entry.Period,
+			Amount:        entry.Amount,
+			Unit:          entry.Unit,
+			Note:          entry.Note,
+		}
+		if prepared[i], err = prepareTimeOffRequest(client, input, hoursPerDay); err != nil {
+			return nil, fmt.Errorf("entry %d (%s): %w", i+1, entry.Period.StartYMD(), err)
+		}
+	}
+
+	result := &BulkTimeOffResult{
+		EmployeeID:    strconv.Itoa(request.EmployeeID),
+		TimeOffTypeID: typeIDs[0],
+		Units:         prepared[0].Units,
+		DryRun:        request.DryRun,
+		WorkDay:       prepared[0].WorkDay,
+	}
+	if types, err := client.GetTimeOffTypes(); err == nil {
+		for _, timeOffType := range types.TimeOffTypes {
+			if timeOffType.ID == result.TimeOffTypeID {
+				result.TimeOffTypeName = timeOffType.Name
+			}
+		}
+	}
+
+	first, last := entries[0].Period, entries[0].Period
+	for _, entry := range entries {
+		if entry.Period.Start.Before(first.Start) {
+			first = entry.Period
+		}
+		if entry.Period.End.After(last.End) {
+			last = entry.Period
+		}
+	}
+	existing, err := client.SearchTimeOffRequests(bamboohr.TimeOffRequestQuery{
+		Start:      first.StartYMD(),
+		End:        last.EndYMD(),
+		EmployeeID: request.EmployeeID,
+		Statuses:   []string{"requested", "approved"},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get time-off requests: %w", err)
+	}
+
+	// Types without a balance, such as home office days, aren't limited
+	var remaining float64
+	if !request.IgnoreBalance {
+		balances, err := client.GetTimeOffBalance(request.EmployeeID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get time-off balance: %w", err)
+		}
+		for _, balance := range balances {
+			if balance.TimeOffType == result.TimeOffTypeID {
+				available := float64(balance.Balance)
+				result.Balance, remaining = &available, available
+			}
+		}
+	}
+
+	for i, entry := range prepared {
+		item := BulkTimeOffItem{Start: entry.Payload.Start, End: entry.Payload.End, Amount: entry.Payload.Amount}
+
+		if problem := bulkEntryProblem(entry, prepared[:i], result.Items, existing); problem != "" {
+			item.Status, item.Error = bulkEntrySkipped, problem
+		} else if result.Balance != nil && roundAmount(remaining-item.Amount) < 0 {
+			item.Status = bulkEntrySkipped
+			item.Error = "exceeds the remaining balance of " + formatAmount(roundAmount(remaining), result.Units)
+		} else if request.DryRun {
+			item.Status = bulkEntryValid
+		} else if created, err := entry.create(client); err != nil {
+			item.Status, item.Error = bulkEntryFailed, err.Error()
+		} else {
+			item.Status, item.RequestID = bulkEntryCreated, created.ID
+		}
+
+		switch item.Status {
+		case bulkEntryCreated, bulkEntryValid:
+			remaining -= item.Amount
+			result.TotalAmount = roundAmount(result.TotalAmount + item.Amount)
+			if item.Status == bulkEntryCreated {
+				result.Created++
+			} else {
+				result.Valid++
+			}
+		case bulkEntrySkipped:
+			result.Skipped++
+		case bulkEntryFailed:
+			result.Failed++
+		}
+		result.Items = append(result.Items, item)
+	}
+
+	return result, nil
+}
+
+// bulkEntryProblem returns why an entry can't be created alongside the existing time off and
+// the earlier entries that passed, or "" if it can
+func bulkEntryProblem(entry preparedTimeOffRequest, earlier []preparedTimeOffRequest, outcomes []BulkTimeOffItem, existing []bamboohr.TimeOffRequest) string {
+	start, end := entry.Payload.Start, entry.Payload.End
+	for _, request := range existing {
+		if request.Start <= end && request.End >= start {
+			return fmt.Sprintf("overlaps %s request %s (%s to %s)", request.Status.Status, request.ID, request.Start, request.End)
+		}
+	}
+
+	for i, other := range earlier {
+		if outcomes[i].Status != bulkEntryCreated && outcomes[i].Status != bulkEntryValid {
+			continue
+		}
+		if other.Payload.Start <= end && other.Payload.End >= start {
+			return fmt.Sprintf("overlaps entry %d (%s to %s)", i+1, other.Payload.Start, other.Payload.End)
+		}
+	}
+
+	return ""
+}
+
+// bulkEntries parses the entries of a bulk request and fills in their amounts, reporting every
+// badly formed entry at once
+func bulkEntries(dates *DateParser, request BulkTimeOffRequest) ([]bulkEntry, error) {
+	var entries []bulkEntry
+	var err error
+	switch {
+	case countNonEmpty(request.Dates, request.Recurrence, request.CSV) != 1:
+		return nil, fmt.Errorf("give exactly one of dates, recurrence or csv")
+	case request.Dates != "":
+		entries, err = parseBulkDates(dates, request.Dates)
+	case request.Recurrence != "":
+		var periods []DateRange
+		periods, err = parseRecurrence(dates, request.Recurrence)
+		for _, period := range periods {
+			entries = append(entries, bulkEntry{Period: period})
+		}
+	default:
+		entries, err = parseBulkCSV(dates, request.CSV)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if len(entries) == 0 {
+		return nil, fmt.Errorf("no time off to create")
+	}
+	if len(entries) > maxBulkEntries {
+		return nil, fmt.Errorf("%d entries is more than the %d that can be created at once", len(entries), maxBulkEntries)
+	}
+
+	var errs []error
+	for i := range entries {
+		entry := &entries[i]
+		if entry.Note == "" {
+			entry.Note = request.Note
+		}
+		if entry.Amount != 0 {
+			continue
+		}
+		if request.Amount != 0 {
+			entry.Amount, entry.Unit = request.Amount, request.Unit
+			continue
+		}
+
+		// Without an amount each entry takes the working days in it
+		entry.Amount, entry.Unit = float64(workingDays(entry.Period)), "days"
+		if entry.Amount == 0 {
+			errs = append(errs, fmt.Errorf("entry %d (%s) has no working days, give an amount", i+1, entry.Period.StartYMD()))
+		}
+	}
+	return entries, errors.Join(errs...)
+}
+
+// parseBulkDates parses dates and ranges separated by semicolons or new lines
+func parseBulkDates(dates *DateParser, value string) ([]bulkEntry, error) {
+	var entries []bulkEntry
+	var errs []error
+	for _, item := range strings.FieldsFunc(value, func(r rune) bool { return r == ';' || r == '\n' }) {
+		if item = strings.TrimSpace(item); item == "" {
+			continue
+		}
+
+		period, err := dates.ResolveRange(item, "", DateRange{})
+		if err != nil {
+			errs = append(errs, fmt.Errorf("entry %d: %w", len(entries)+len(errs)+1, err))
+			continue
+		}
+		entries = append(entries, bulkEntry{Period: period})
+	}
+	return entries, errors.Join(errs...)
+}
+
+// parseRecurrence expands a rule such as "every friday until june", "every other monday from
+// oct 6 until dec 31" or "every weekday through next friday" into single days. The rule starts
+// today unless it says otherwise. An end date is included, while a period such as "june" ends
+// the rule before it starts, and an end without a year that has passed this year means next year.
+func parseRecurrence(dates *DateParser, rule string) ([]DateRange, error) {
+	value := strings.Join(strings.Fields(strings.ToLower(rule)), " ")
+	rest, ok := strings.CutPrefix(value, "every ")
+	if !ok {
+		return nil, fmt.Errorf("recurrence must start with 'every', e.g. 'every friday until june'")
+	}
+
+	var untilExpr string
+	for _, separator := range []string{" until ", " till ", " through ", " to "} {
+		if left, right, found := strings.Cut(rest, separator); found {
+			rest, untilExpr = left, right
+			break
+		}
+	}
+	if untilExpr == "" {
+		return nil, fmt.Errorf("recurrence needs an end, e.g. 'every friday until june'")
+	}
+
+	var fromExpr string
+	for _, separator := range []string{" from ", " starting "} {
+		if left, right, found := strings.Cut(rest, separator); found {
+			rest, fromExpr = left, right
+			break
+		}
+	}
+
+	interval := 1
+	if days, found := strings.CutPrefix(rest, "other "); found {
+		rest, interval = days, 2
+	}
+
+	repeated, err := parseWeekdays(rest)
+	if err != nil {
+		return nil, err
+	}
+	if len(repeated) == 0 {
+		return nil, fmt.Errorf("recurrence needs a day, e.g. 'every friday until june'")
+	}
+
+	from := dates.Today()
+	if fromExpr != "" {
+		if from, err = dates.ParseDate(fromExpr); err != nil {
+			return nil, fmt.Errorf("invalid recurrence start: %w", err)
+		}
+	}
+
+	until, err := dates.ParseRange(untilExpr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid recurrence end: %w", err)
+	}
+	end := until.End
+	if !until.Start.Equal(until.End) {
+		end = until.Start.AddDate(0, 0, -1)
+	}
+	if end.Before(from) && !explicitYear.MatchString(untilExpr) {
+		end = end.AddDate(1, 0, 0)
+	}
+	if end.Before(from) {
+		return nil, fmt.Errorf("recurrence ends on %s, before it starts on %s", end.Format(dateLayout), from.Format(dateLayout))
+	}
+
+	var periods []DateRange
+	var firstMonday time.Time
+	for date := from; !date.After(end); date = date.AddDate(0, 0, 1) {
+		if !repeated[date.Weekday()] {
+			continue
+		}
+
+		monday, _ := weekRange(date)
+		if firstMonday.IsZero() {
+			firstMonday = monday
+		}
+		if weeks := int(monday.Sub(firstMonday).Hours()/24/7 + 0.5); weeks%interval != 0 {
+			continue
+		}
+
+		periods = append(periods, DateRange{Start: date, End: date})
+		if len(periods) > maxBulkEntries {
+			return nil, fmt.Errorf("recurrence gives more than the %d entries that can be created at once", maxBulkEntries)
+		}
+	}
+	return periods, nil
+}
+
+// parseWeekdays parses a list of weekdays such as "monday, wednesday and fridays", where
+// "weekday" means Monday to Friday
+func parseWeekdays(value string) (map[time.Weekday]bool, error) {
+	days := map[time.Weekday]bool{}
+	for _, name := range strings.FieldsFunc(strings.ReplaceAll(strings.ToLower(value), " and ", ","), func(r rune) bool { return r == ',' }) {
+		name = strings.TrimSpace(name)
+		if name == "weekday" || name == "weekdays" {
+			for day := time.Monday; day <= time.Friday; day++ {
+				days[day] = true
+			}
+			continue
+		}
+
+		weekday, ok := weekdays[name]
+		if !ok {
+			weekday, ok = weekdays[strings.TrimSuffix(name, "s")]
+		}
+		if !ok {
+			return nil, fmt.Errorf("unknown day %q, expected a weekday such as 'friday' or 'weekday'", name)
+		}
+		days[weekday] = true
+	}
+	return days, nil
+}
+
+// parseBulkCSV parses CSV rows of start, end, amount and note. A header row naming the columns
+// (start, end, amount, unit and note) may put them in any order.
+func parseBulkCSV(dates *DateParser, value string) ([]bulkEntry, error) {
+	reader := csv.NewReader(strings.NewReader(value))
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	columns := map[string]int{"start": 0, "end": 1, "amount": 2, "note": 3, "unit": -1}
+	field := func(record []string, name string) string {
+		if i := columns[name]; i >= 0 && i < len(record) {
+			return strings.TrimSpace(record[i])
+		}
+		return ""
+	}
+
+	var entries []bulkEntry
+	var errs []error
+	for row := 1; ; row++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid CSV: %w", err)
+		}
+
+		if row == 1 && isBulkCSVHeader(record) {
+			columns = map[string]int{"start": -1, "end": -1, "amount": -1, "note": -1, "unit": -1}
+			for i, name := range record {
+				columns[strings.ToLower(strings.TrimSpace(name))] = i
+			}
+			continue
+		}
+		if countNonEmpty(record...) == 0 {
+			continue
+		}
+
+		period, err := dates.ResolveRange(field(record, "start"), field(record, "end"), DateRange{})
+		if err != nil {
+			errs = append(errs, fmt.Errorf("row %d: %w", row, err))
+			continue
+		}
+
+		entry := bulkEntry{Period: period, Unit: field(record, "unit"), Note: field(record, "note")}
+		if amount := field(record, "amount"); amount != "" {
+			if entry.Amount, err = strconv.ParseFloat(amount, 64); err != nil || entry.Amount <= 0 {
+				errs = append(errs, fmt.Errorf("row %d: amount must be a positive number, got %q", row, amount))
+				continue
+			}
+		}
+		entries = append(entries, entry)
+	}
+	return entries, errors.Join(errs...)
+}
+
+// isBulkCSVHeader reports whether a CSV row names the columns, including start
+func isBulkCSVHeader(record []string) bool {
+	hasStart := false
+	for _, name := range record {
+		switch strings.ToLower(strings.TrimSpace(name)) {
+		case "start":
+			hasStart = true
+		case "end", "amount", "unit", "note", "":
+		default:
+			return false
+		}
+	}
+	return hasStart
+}
+
+// workingDays returns the number of Monday to Friday days in the range
+func workingDays(r DateRange) int {
+	days := 0
+	for date := r.Start; !date.After(r.End); date = date.AddDate(0, 0, 1) {
+		if date.Weekday() != time.Saturday && date.Weekday() != time.Sunday {
+			days++
+		}
+	}
+	return days
+}
+
+// workingDayAmounts spreads the amount over the working days of the period, in equal parts
+// rounded to two decimals with the remainder on the last day. A period without working days
+// books the amount on its start date.
+func workingDayAmounts(r DateRange, amount float64) []bamboohr.DateAmount {
+	var days []string
+	for date := r.Start; !date.After(r.End); date = date.AddDate(0, 0, 1) {
+		if date.Weekday() != time.Saturday && date.Weekday() != time.Sunday {
+			days = append(days, date.Format(dateLayout))
+		}
+	}
+	if len(days) == 0 {
+		return []bamboohr.DateAmount{{YMD: r.StartYMD(), Amount: amount}}
+	}
+
+	share := roundAmount(amount / float64(len(days)))
+	amounts := make([]bamboohr.DateAmount, len(days))
+	for i, day := range days {
+		amounts[i] = bamboohr.DateAmount{YMD: day, Amount: share}
+	}
+	amounts[len(days)-1].Amount = roundAmount(amount - share*float64(len(days)-1))
+	return amounts
+}
+
+// countNonEmpty returns how many of the values are not blank
+func countNonEmpty(values ...string) int {
+	count := 0
+	for _, value := range values {
+		if strings.TrimSpace(value) != "" {
+			count++
+		}
+	}
+	return count
+}
+
+func handleBulkCreateTimeOff(client BambooHR, dates *DateParser, me int, hoursPerDay float64) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		employeeIDStr, err := request.RequireString("employeeId")
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("employeeId is required: %s", err.Error())), nil
+		}
+
+		employeeID, err := resolveEmployeeID(employeeIDStr, me)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		timeOffType, err := request.RequireString("timeOffType")
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("timeOffType is required: %s", err.Error())), nil
+		}
+
+		locale, err := toolLocale(request, dates)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		format, err := toolFormat(request, FormatCompact)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		bulk := BulkTimeOffRequest{
+			EmployeeID:    employeeID,
+			TimeOffType:   timeOffType,
+			Dates:         request.GetString("dates", ""),
+			Recurrence:    request.GetString("recurrence", ""),
+			CSV:           request.GetString("csv", ""),
+			Unit:          request.GetString("unit", ""),
+			Note:          request.GetString("employeeNote", ""),
+			DryRun:        request.GetBool("dryRun", false),
+			IgnoreBalance: request.GetBool("ignoreBalance", false),
+		}
+		if amount := request.GetString("amount", ""); amount != "" {
+			if bulk.Amount, err = strconv.ParseFloat(amount, 64); err != nil || bulk.Amount <= 0 {
+				return mcp.NewToolResultError("amount must be a positive number, e.g. '1', '0.5' or '4'"), nil
+			}
+		}
+
+		result, err := BulkCreateTimeOff(client, dates, bulk, hoursPerDay)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		return renderToolResult(result, bulkTimeOffView(*result, locale), format), nil
+	}
+}
+
+// bulkTimeOffView lists the outcome of every entry of a bulk request
+func bulkTimeOffView(result BulkTimeOffResult, locale Locale) textView {
+	view := textView{
+		Title:   BulkTimeOffSummary(result),
+		Columns: []string{"Start", "End", "Amount", "Status", "Request ID", "Error"},
+	}
+	for _, item := range result.Items {
+		dates := locale.FormatDate(item.Start)
+		if item.End != item.Start {
+			dates += " to " + locale.FormatDate(item.End)
+		}
+
+		outcome := item.Status
+		if item.RequestID != "" {
+			outcome += " as request " + item.RequestID
+		}
+		if item.Error != "" {
+			outcome += ": " + item.Error
+		}
+
+		view.Items = append(view.Items, fmt.Sprintf("%s, %s: %s", dates, result.WorkDay.format(item.Amount, result.Units), outcome))
+		view.Rows = append(view.Rows, []string{
+			locale.FormatDate(item.Start),
+			locale.FormatDate(item.End),
+			strconv.FormatFloat(item.Amount, 'f', -1, 64),
+			item.Status,
+			item.RequestID,
+			item.Error,
+		})
+	}
+	return view
+}
+
+// BulkTimeOffSummary summarizes the outcome of a bulk request in one line
+func BulkTimeOffSummary(result BulkTimeOffResult) string {
+	typeName := result.TimeOffTypeName
+	if typeName == "" {
+		typeName = "time off type " + result.TimeOffTypeID
+	}
+
+	var summary string
+	if result.DryRun {
+		summary = fmt.Sprintf("Checked %s of %s for employee %s: %d valid", pluralize(len(result.Items), "entry", "entries"), typeName, result.EmployeeID, result.Valid)
+	} else {
+		summary = fmt.Sprintf("Created %d of %s of %s for employee %s", result.Created, pluralize(len(result.Items), "entry", "entries"), typeName, result.EmployeeID)
+	}
+	summary += fmt.Sprintf(" (%s)", result.WorkDay.format(result.TotalAmount, result.Units))
+
+	return joinNonEmpty(", ", summary,
+		countSummary(result.Skipped, "skipped"),
+		countSummary(result.Failed, "failed"))
+}
+
+// countSummary returns "3 skipped" for a non-zero count, or ""
+func countSummary(count int, outcome string) string {
+	if count == 0 {
+		return ""
+	}
+	return fmt.Sprintf("%d %s", count, outcome)
+}
@@ -0,0 +1,293 @@
+package mcpserver
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"bamboohr-mcp-server/bamboohr"
+)
+
+func TestParseRecurrence(t *testing.T) {
+	dates := newTestDateParser()
+
+	tests := []struct {
+		rule     string
+		expected []string
+	}{
+		{"every friday until 2025-09-26", []string{"2025-09-05", "2025-09-12", "2025-09-19", "2025-09-26"}},
+		{"Every Friday until October", []string{"2025-09-05", "2025-09-12", "2025-09-19", "2025-09-26"}},
+		{"every other monday from sep 8 until sep 30", []string{"2025-09-08", "2025-09-22"}},
+		{"every tuesday and thursday through sep 12", []string{"2025-09-04", "2025-09-09", "2025-09-11"}},
+		{"every weekday until sep 8", []string{"2025-09-03", "2025-09-04", "2025-09-05", "2025-09-08"}},
+		{"every mondays, fri from 2025-09-04 to 2025-09-08", []string{"2025-09-05", "2025-09-08"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.rule, func(t *testing.T) {
+			periods, err := parseRecurrence(dates, tt.rule)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			var got []string
+			for _, period := range periods {
+				got = append(got, period.StartYMD())
+			}
+			if strings.Join(got, ",") != strings.Join(tt.expected, ",") {
+				t.Errorf("Expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+
+	// A month that has passed this year means next year's
+	periods, err := parseRecurrence(dates, "every friday until june")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if last := periods[len(periods)-1].StartYMD(); last != "2026-05-29" {
+		t.Errorf("Expected the last Friday before June 2026, got %s", last)
+	}
+}
+
+func TestParseRecurrence_Invalid(t *testing.T) {
+	dates := newTestDateParser()
+
+	tests := []struct {
+		rule          string
+		expectedError string
+	}{
+		{"friday until june", "must start with 'every'"},
+		{"every friday", "needs an end"},
+		{"every funday until june", "unknown day"},
+		{"every friday until 2025-01-01", "before it starts"},
+		{"every friday until someday", "invalid recurrence end"},
+		{"every weekday until 2026-12-31", "more than the 100 entries"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.rule, func(t *testing.T) {
+			_, err := parseRecurrence(dates, tt.rule)
+			if err == nil || !strings.Contains(err.Error(), tt.expectedError) {
+				t.Errorf("Expected error containing %q, got %v", tt.expectedError, err)
+			}
+		})
+	}
+}
+
+func TestParseBulkCSV(t *testing.T) {
+	dates := newTestDateParser()
+
+	entries, err := parseBulkCSV(dates, "2025-10-06,2025-10-08,,Trip\n\n2025-10-10,,0.5\n")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("Expected 2 entries, got %d", len(entries))
+	}
+	if entries[0].Period.EndYMD() != "2025-10-08" || entries[0].Amount != 0 || entries[0].Note != "Trip" {
+		t.Errorf("Unexpected first entry: %+v", entries[0])
+	}
+	if entries[1].Period.EndYMD() != "2025-10-10" || entries[1].Amount != 0.5 {
+		t.Errorf("Unexpected second entry: %+v", entries[1])
+	}
+
+	entries, err = parseBulkCSV(dates, "note,unit,amount,start\nDentist,hours,3,Oct 14\n")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(entries) != 1 || entries[0].Period.StartYMD() != "2025-10-14" || entries[0].Amount != 3 || entries[0].Unit != "hours" || entries[0].Note != "Dentist" {
+		t.Errorf("Expected the columns named in the header, got %+v", entries)
+	}
+
+	_, err = parseBulkCSV(dates, "someday\n2025-10-10,,lots\n")
+	if err == nil || !strings.Contains(err.Error(), "row 1") || !strings.Contains(err.Error(), "row 2") {
+		t.Errorf("Expected errors for both rows, got %v", err)
+	}
+}
+
+// refusingBambooHR is a fake that refuses requests starting on one date
+type refusingBambooHR struct {
+	*fakeBambooHR
+	start string
+}
+
+func (r refusingBambooHR) CreateTimeOffRequest(employeeID int, request bamboohr.TimeOffRequestCreate) (*bamboohr.TimeOffRequest, error) {
+	if request.Start == r.start {
+		return nil, errors.New("BambooHR API error (status 400): blackout period")
+	}
+	return r.fakeBambooHR.CreateTimeOffRequest(employeeID, request)
+}
+
+// newBulkTestFake returns a fake where Dan has 4 days of vacation and a home office day booked
+// on Friday 2025-09-12
+func newBulkTestFake() *fakeBambooHR {
+	fake := newFakeBambooHR(orgChartTestEmployees()...)
+	fake.types.TimeOffTypes = []bamboohr.TimeOffType{
+		{ID: "27", Name: "Home Office", Units: "days"},
+		{ID: "78", Name: "Vacation", Units: "days"},
+	}
+	booked := approvalsTestRequest("30", "4", "Dan Diaz", "2025-09-12", "2025-09-12", "approved", 1, false)
+	booked.Type.ID, booked.Type.Name = "27", "Home Office"
+	fake.requests[4] = []bamboohr.TimeOffRequest{booked}
+	fake.balances[4] = []bamboohr.TimeOffBalance{{TimeOffType: "78", Name: "Vacation", Units: "days", Balance: 4}}
+	return fake
+}
+
+func TestBulkCreateTimeOff(t *testing.T) {
+	tests := []struct {
+		name     string
+		request  BulkTimeOffRequest
+		refuse   string
+		expected []string
+		summary  string
+	}{
+		{
+			name:     "recurrence skips booked days",
+			request:  BulkTimeOffRequest{TimeOffType: "Home Office", Recurrence: "every friday until 2025-09-26"},
+			expected: []string{"2025-09-05 created", "2025-09-12 skipped", "2025-09-19 created", "2025-09-26 created"},
+			summary:  "Created 3 of 4 entries of Home Office for employee 4 (3 days / 24 hours), 1 skipped",
+		},
+		{
+			name:     "overlapping entries and the balance",
+			request:  BulkTimeOffRequest{TimeOffType: "78", Dates: "2025-10-06 - 2025-10-08; 2025-10-07\n2025-10-13 - 2025-10-14"},
+			expected: []string{"2025-10-06 created", "2025-10-07 skipped", "2025-10-13 skipped"},
+			summary:  "Created 1 of 3 entries of Vacation for employee 4 (3 days / 24 hours), 2 skipped",
+		},
+		{
+			name:     "ignoring the balance",
+			request:  BulkTimeOffRequest{TimeOffType: "78", Dates: "2025-10-06 - 2025-10-08; 2025-10-13 - 2025-10-14", IgnoreBalance: true},
+			expected: []string{"2025-10-06 created", "2025-10-13 created"},
+			summary:  "Created 2 of 2 entries of Vacation for employee 4 (5 days / 40 hours)",
+		},
+		{
+			name:     "dry run",
+			request:  BulkTimeOffRequest{TimeOffType: "27", CSV: "start,amount\n2025-09-05,0.5\n2025-09-12,0.5\n", DryRun: true},
+			expected: []string{"2025-09-05 valid", "2025-09-12 skipped"},
+			summary:  "Checked 2 entries of Home Office for employee 4: 1 valid (0.5 days / 4 hours), 1 skipped",
+		},
+		{
+			name:     "partial failure",
+			request:  BulkTimeOffRequest{TimeOffType: "27", Dates: "2025-09-04; 2025-09-05", Amount: 4, Unit: "hours"},
+			refuse:   "2025-09-04",
+			expected: []string{"2025-09-04 failed", "2025-09-05 created"},
+			summary:  "Created 1 of 2 entries of Home Office for employee 4 (0.5 days / 4 hours), 1 failed",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := newBulkTestFake()
+			tt.request.EmployeeID = 4
+
+			result, err := BulkCreateTimeOff(refusingBambooHR{fake, tt.refuse}, newTestDateParser(), tt.request, 0)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			var got []string
+			for _, item := range result.Items {
+				got = append(got, item.Start+" "+item.Status)
+			}
+			if strings.Join(got, ", ") != strings.Join(tt.expected, ", ") {
+				t.Errorf("Expected %v, got %v", tt.expected, got)
+			}
+			if summary := BulkTimeOffSummary(*result); summary != tt.summary {
+				t.Errorf("Expected summary %q, got %q", tt.summary, summary)
+			}
+			if len(fake.created) != result.Created {
+				t.Errorf("Expected %d requests sent to BambooHR, got %d", result.Created, len(fake.created))
+			}
+		})
+	}
+}
+
+func TestBulkCreateTimeOff_DailyAmounts(t *testing.T) {
+	fake := newBulkTestFake()
+	request := BulkTimeOffRequest{EmployeeID: 4, TimeOffType: "78", Dates: "2025-10-10 - 2025-10-14", IgnoreBalance: true}
+	if _, err := BulkCreateTimeOff(fake, newTestDateParser(), request, 0); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	// Friday to Tuesday books a day on each of its 3 working days, not 3 days on Friday
+	expected := []bamboohr.DateAmount{{YMD: "2025-10-10", Amount: 1}, {YMD: "2025-10-13", Amount: 1}, {YMD: "2025-10-14", Amount: 1}}
+	if len(fake.created) != 1 || !slices.Equal(fake.created[0].Dates, expected) {
+		t.Errorf("Expected %v, got %+v", expected, fake.created)
+	}
+
+	period := DateRange{Start: time.Date(2025, 10, 6, 0, 0, 0, 0, time.UTC), End: time.Date(2025, 10, 8, 0, 0, 0, 0, time.UTC)}
+	expected = []bamboohr.DateAmount{{YMD: "2025-10-06", Amount: 0.33}, {YMD: "2025-10-07", Amount: 0.33}, {YMD: "2025-10-08", Amount: 0.34}}
+	if amounts := workingDayAmounts(period, 1); !slices.Equal(amounts, expected) {
+		t.Errorf("Expected the remainder on the last day, got %v", amounts)
+	}
+}
+
+func TestBulkCreateTimeOff_Invalid(t *testing.T) {
+	tests := []struct {
+		name          string
+		request       BulkTimeOffRequest
+		expectedError string
+	}{
+		{"no entries", BulkTimeOffRequest{TimeOffType: "27"}, "exactly one of dates, recurrence or csv"},
+		{"two sources", BulkTimeOffRequest{TimeOffType: "27", Dates: "2025-09-05", Recurrence: "every friday until june"}, "exactly one of dates, recurrence or csv"},
+		{"unknown type", BulkTimeOffRequest{TimeOffType: "Sabbatical", Dates: "2025-09-05"}, "unknown time off type"},
+		{"invalid date", BulkTimeOffRequest{TimeOffType: "27", Dates: "2025-09-05; someday"}, "entry 2"},
+		{"weekend without amount", BulkTimeOffRequest{TimeOffType: "27", Dates: "2025-09-06"}, "has no working days"},
+		{"invalid unit", BulkTimeOffRequest{TimeOffType: "27", Dates: "2025-09-05", Amount: 1, Unit: "weeks"}, "unit must be"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := newBulkTestFake()
+			tt.request.EmployeeID = 4
+
+			_, err := BulkCreateTimeOff(fake, newTestDateParser(), tt.request, 0)
+			if err == nil || !strings.Contains(err.Error(), tt.expectedError) {
+				t.Errorf("Expected error containing %q, got %v", tt.expectedError, err)
+			}
+			if len(fake.created) != 0 {
+				t.Errorf("Expected nothing to be created, got %+v", fake.created)
+			}
+		})
+	}
+}
+
+func TestHandleBulkCreateTimeOff(t *testing.T) {
+	fake := newBulkTestFake()
+	s := New(fake, newTestDateParser(), WithCurrentEmployee(4))
+
+	text, isError := callTool(t, s, "bulk_create_time_off", map[string]string{
+		"employeeId":   "me",
+		"timeOffType":  "27",
+		"recurrence":   "every friday until 2025-09-19",
+		"employeeNote": "Working from home",
+	})
+	if isError {
+		t.Fatalf("Unexpected tool error: %s", text)
+	}
+
+	for _, expected := range []string{
+		"Created 2 of 3 entries of Home Office for employee 4 (2 days / 16 hours), 1 skipped:",
+		"- 2025-09-05, 1 day / 8 hours: created as request 1",
+		"- 2025-09-12, 1 day / 8 hours: skipped: overlaps approved request 30 (2025-09-12 to 2025-09-12)",
+	} {
+		if !strings.Contains(text, expected) {
+			t.Errorf("Expected %q in %q", expected, text)
+		}
+	}
+	if len(fake.created) != 2 || fake.created[1].Notes[0].Note != "Working from home" {
+		t.Errorf("Expected the note on every created request, got %+v", fake.created)
+	}
+
+	text, isError = callTool(t, s, "bulk_create_time_off", map[string]string{
+		"employeeId":  "4",
+		"timeOffType": "27",
+		"dates":       "2025-10-03",
+		"amount":      "none",
+	})
+	if !isError || !strings.Contains(text, "amount must be a positive number") {
+		t.Errorf("Expected an amount error, got %q", text)
+	}
+}
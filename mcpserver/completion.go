@@ -0,0 +1,278 @@
+package mcpserver
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"bamboohr-mcp-server/bamboohr"
+)
+
+// maxCompletionValues is the maximum number of values the MCP spec allows in a completion response
+const maxCompletionValues = 100
+
+// completionProvider suggests values for prompt and resource template arguments
+type completionProvider struct {
+	client BambooHR
+	dates  *DateParser
+}
+
+// newCompletionProvider creates a completion provider backed by the BambooHR client
+func newCompletionProvider(client BambooHR, dates *DateParser) *completionProvider {
+	return &completionProvider{
+		client: client,
+		dates:  dates,
+	}
+}
+
+// CompletePromptArgument provides completions for a prompt argument
+func (p *completionProvider) CompletePromptArgument(ctx context.Context, promptName string, argument mcp.CompleteArgument, context mcp.CompleteContext) (*mcp.Completion, error) {
+	return p.complete(argument, context)
+}
+
+// CompleteResourceArgument provides completions for a resource template argument
+func (p *completionProvider) CompleteResourceArgument(ctx context.Context, uri string, argument mcp.CompleteArgument, context mcp.CompleteContext) (*mcp.Completion, error) {
+	return p.complete(argument, context)
+}
+
+// complete dispatches on the argument name, which is shared between prompts and resource templates
+func (p *completionProvider) complete(argument mcp.CompleteArgument, context mcp.CompleteContext) (*mcp.Completion, error) {
+	switch argument.Name {
+	case "employeeId", "id":
+		return p.completeEmployeeIDs(argument.Value)
+	case "employeeIds":
+		return p.completeEmployeeIDList(argument.Value)
+	case "team":
+		return p.completeTeam(argument.Value)
+	case "timeOffTypeId":
+		return p.completeTimeOffTypeIDs(argument.Value)
+	case "start":
+		return completeDates(dateShortcuts(p.dates, time.Time{}), argument.Value), nil
+	case "end":
+		var start time.Time
+		if value, ok := context.Arguments["start"]; ok {
+			if r, err := p.dates.ParseRange(value); err == nil {
+				start = r.Start
+			}
+		}
+		return completeDates(dateShortcuts(p.dates, start), argument.Value), nil
+	case "year":
+		return p.completeYears(argument.Value), nil
+	}
+
+	return &mcp.Completion{Values: []string{}}, nil
+}
+
+// completeEmployeeIDs suggests employee IDs whose ID or name matches the partial value
+func (p *completionProvider) completeEmployeeIDs(value string) (*mcp.Completion, error) {
+	directory, err := p.client.GetEmployeeDirectory()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get employee directory: %w", err)
+	}
+
+	employees := make([]bamboohr.Employee, len(directory.Employees))
+	copy(employees, directory.Employees)
+	sort.SliceStable(employees, func(i, j int) bool {
+		return strings.ToLower(employees[i].DisplayName) < strings.ToLower(employees[j].DisplayName)
+	})
+
+	query := strings.ToLower(strings.TrimSpace(value))
+	var values []string
+	for _, employee := range employees {
+		if query == "" || strings.HasPrefix(employee.ID, query) || employeeNameMatches(employee, query) {
+			values = append(values, employee.ID)
+		}
+	}
+
+	return truncateCompletion(values), nil
+}
+
+// completeEmployeeIDList completes the last entry of a comma-separated list of employee IDs
+func (p *completionProvider) completeEmployeeIDList(value string) (*mcp.Completion, error) {
+	prefix := ""
+	last := value
+	if i := strings.LastIndex(value, ","); i >= 0 {
+		prefix = value[:i+1]
+		last = value[i+1:]
+	}
+
+	completion, err := p.completeEmployeeIDs(last)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, id := range completion.Values {
+		completion.Values[i] = prefix + id
+	}
+
+	return completion, nil
+}
+
+// completeTeam suggests department names matching the partial value
+func (p *completionProvider) completeTeam(value string) (*mcp.Completion, error) {
+	directory, err := p.client.GetEmployeeDirectory()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get employee directory: %w", err)
+	}
+
+	seen := make(map[string]bool)
+	var departments []string
+	for _, employee := range directory.Employees {
+		if employee.Department != "" && !seen[employee.Department] {
+			seen[employee.Department] = true
+			departments = append(departments, employee.Department)
+		}
+	}
+	sort.Strings(departments)
+
+	return completionOf(departments, value), nil
+}
+
+// completeTimeOffTypeIDs suggests time-off type IDs whose ID or name matches the partial value
+func (p *completionProvider) completeTimeOffTypeIDs(value string) (*mcp.Completion, error) {
+	types, err := p.client.GetTimeOffTypes()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get time-off types: %w", err)
+	}
+
+	query := strings.ToLower(strings.TrimSpace(value))
+	var values []string
+	for _, timeOffType := range types.TimeOffTypes {
+		if query == "" || strings.HasPrefix(timeOffType.ID, query) || strings.Contains(strings.ToLower(timeOffType.Name), query) {
+			values = append(values, timeOffType.ID)
+		}
+	}
+
+	return truncateCompletion(values), nil
+}
+
+// completeYears suggests the previous, current and next year
+func (p *completionProvider) completeYears(value string) *mcp.Completion {
+	year := p.dates.Today().Year()
+	years := []string{
+		fmt.Sprintf("%d", year),
+		fmt.Sprintf("%d", year+1),
+		fmt.Sprintf("%d", year-1),
+	}
+
+	return completionOf(years, value)
+}
+
+// employeeNameMatches reports whether any of the employee's names contains the lower-case query
+func employeeNameMatches(employee bamboohr.Employee, query string) bool {
+	for _, name := range []string{
+		employee.DisplayName,
+		employee.FirstName,
+		employee.LastName,
+		employee.PreferredName,
+		employee.FirstName + " " + employee.LastName,
+	} {
+		if name != "" && name != " " && strings.Contains(strings.ToLower(name), query) {
+			return true
+		}
+	}
+	return false
+}
+
+// dateShortcut is a named date suggested for start and end arguments
+type dateShortcut struct {
+	Label string
+	Date  time.Time
+}
+
+// dateShortcutLabels are the relative date expressions suggested for start and end arguments
+var dateShortcutLabels = []string{
+	"today",
+	"tomorrow",
+	"start of this week",
+	"end of this week",
+	"start of next week",
+	"end of next week",
+	"start of this month",
+	"end of this month",
+	"start of next month",
+	"end of next month",
+	"start of this year",
+	"end of this year",
+	"next monday",
+	"next tuesday",
+	"next wednesday",
+	"next thursday",
+	"next friday",
+	"next saturday",
+	"next sunday",
+}
+
+// dateShortcuts returns the date suggestions, including dates relative to start when completing an end date
+func dateShortcuts(dates *DateParser, start time.Time) []dateShortcut {
+	var shortcuts []dateShortcut
+	if !start.IsZero() {
+		startMonday, startSunday := weekRange(start)
+		shortcuts = append(shortcuts,
+			dateShortcut{"same day", start},
+			dateShortcut{"end of that week", startSunday},
+			dateShortcut{"end of that working week", startMonday.AddDate(0, 0, 4)},
+			dateShortcut{"one week later", start.AddDate(0, 0, 6)},
+			dateShortcut{"two weeks later", start.AddDate(0, 0, 13)},
+		)
+	}
+
+	for _, label := range dateShortcutLabels {
+		if date, err := dates.ParseDate(label); err == nil {
+			shortcuts = append(shortcuts, dateShortcut{label, date})
+		}
+	}
+
+	return shortcuts
+}
+
+// completionOf returns the candidates that start with the partial value, ignoring case
+func completionOf(candidates []string, value string) *mcp.Completion {
+	query := strings.ToLower(strings.TrimSpace(value))
+	var values []string
+	for _, candidate := range candidates {
+		if query == "" || strings.HasPrefix(strings.ToLower(candidate), query) {
+			values = append(values, candidate)
+		}
+	}
+
+	return truncateCompletion(values)
+}
+
+// completeDates returns the YYYY-MM-DD dates of shortcuts whose label or date starts with the partial value
+func completeDates(shortcuts []dateShortcut, value string) *mcp.Completion {
+	query := strings.ToLower(strings.TrimSpace(value))
+	seen := make(map[string]bool)
+	var values []string
+	for _, shortcut := range shortcuts {
+		date := shortcut.Date.Format(dateLayout)
+		if seen[date] {
+			continue
+		}
+		if query == "" || strings.HasPrefix(shortcut.Label, query) || strings.HasPrefix(date, query) {
+			seen[date] = true
+			values = append(values, date)
+		}
+	}
+
+	return truncateCompletion(values)
+}
+
+// truncateCompletion limits values to the maximum allowed in a completion response
+func truncateCompletion(values []string) *mcp.Completion {
+	if values == nil {
+		values = []string{}
+	}
+
+	completion := &mcp.Completion{Values: values, Total: len(values)}
+	if len(values) > maxCompletionValues {
+		completion.Values = values[:maxCompletionValues]
+		completion.HasMore = true
+	}
+
+	return completion
+}
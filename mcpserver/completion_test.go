@@ -0,0 +1,177 @@
+package mcpserver
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+const completionDirectoryJSON = `{
+	"fields": [],
+	"employees": [
+		{"id": "157", "displayName": "John Doe", "firstName": "John", "lastName": "Doe", "department": "Engineering"},
+		{"id": "158", "displayName": "Jane Smith", "firstName": "Jane", "lastName": "Smith", "department": "Sales"},
+		{"id": "201", "displayName": "Johanna Berg", "firstName": "Johanna", "lastName": "Berg", "department": "Engineering"}
+	]
+}`
+
+const completionTypesJSON = `{
+	"timeOffTypes": [
+		{"id": "1", "name": "Vacation", "units": "days"},
+		{"id": "2", "name": "Sick Days", "units": "days"},
+		{"id": "27", "name": "Home Office days", "units": "days"}
+	],
+	"defaultHours": []
+}`
+
+// completeArgument sends a completion request for a prompt argument and returns the completion
+func completeArgument(t *testing.T, ref map[string]string, name, value string, context map[string]string) mcp.Completion {
+	t.Helper()
+
+	_, client := newMockBambooHR(t, map[string]string{
+		"/employees/directory": completionDirectoryJSON,
+		"/meta/time_off/types": completionTypesJSON,
+	})
+	s := New(client, newTestDateParser())
+
+	result := sendMessage(t, s, "completion/complete", map[string]interface{}{
+		"ref":      ref,
+		"argument": map[string]string{"name": name, "value": value},
+		"context":  map[string]interface{}{"arguments": context},
+	})
+
+	var complete struct {
+		Completion mcp.Completion `json:"completion"`
+	}
+	if err := json.Unmarshal(result, &complete); err != nil {
+		t.Fatalf("Failed to unmarshal completion: %v", err)
+	}
+
+	return complete.Completion
+}
+
+func TestCompletion_EmployeeIDByName(t *testing.T) {
+	ref := map[string]string{"type": "ref/prompt", "name": "plan_vacation"}
+
+	completion := completeArgument(t, ref, "employeeId", "joh", nil)
+	if strings.Join(completion.Values, ",") != "201,157" {
+		t.Errorf("Expected employees 201 and 157 sorted by name, got %v", completion.Values)
+	}
+
+	completion = completeArgument(t, ref, "employeeId", "smith", nil)
+	if strings.Join(completion.Values, ",") != "158" {
+		t.Errorf("Expected employee 158, got %v", completion.Values)
+	}
+
+	completion = completeArgument(t, ref, "employeeId", "15", nil)
+	if strings.Join(completion.Values, ",") != "158,157" {
+		t.Errorf("Expected employees with an ID prefix of 15, got %v", completion.Values)
+	}
+}
+
+func TestCompletion_EmployeeIDList(t *testing.T) {
+	ref := map[string]string{"type": "ref/prompt", "name": "review_pending_requests"}
+
+	completion := completeArgument(t, ref, "employeeIds", "157, jane", nil)
+	if strings.Join(completion.Values, ",") != "157,158" || len(completion.Values) != 1 {
+		t.Errorf("Expected the list to be completed with Jane's ID, got %v", completion.Values)
+	}
+}
+
+func TestCompletion_ResourceTemplateEmployeeID(t *testing.T) {
+	ref := map[string]string{"type": "ref/resource", "uri": "bamboohr://employees/{id}/time-off/balance"}
+
+	completion := completeArgument(t, ref, "id", "doe", nil)
+	if strings.Join(completion.Values, ",") != "157" {
+		t.Errorf("Expected employee 157, got %v", completion.Values)
+	}
+}
+
+func TestCompletion_Team(t *testing.T) {
+	ref := map[string]string{"type": "ref/prompt", "name": "team_absence_summary"}
+
+	completion := completeArgument(t, ref, "team", "", nil)
+	if strings.Join(completion.Values, ",") != "Engineering,Sales" {
+		t.Errorf("Expected each department once, got %v", completion.Values)
+	}
+}
+
+func TestCompletion_TimeOffTypeIDByName(t *testing.T) {
+	ref := map[string]string{"type": "ref/prompt", "name": "plan_vacation"}
+
+	completion := completeArgument(t, ref, "timeOffTypeId", "home", nil)
+	if strings.Join(completion.Values, ",") != "27" {
+		t.Errorf("Expected type 27, got %v", completion.Values)
+	}
+
+	completion = completeArgument(t, ref, "timeOffTypeId", "days", nil)
+	if strings.Join(completion.Values, ",") != "2,27" {
+		t.Errorf("Expected types 2 and 27, got %v", completion.Values)
+	}
+}
+
+func TestCompletion_Dates(t *testing.T) {
+	ref := map[string]string{"type": "ref/prompt", "name": "plan_vacation"}
+	completion := completeArgument(t, ref, "start", "tod", nil)
+	if strings.Join(completion.Values, ",") != "2025-09-03" {
+		t.Errorf("Expected the reference date, got %v", completion.Values)
+	}
+
+	completion = completeArgument(t, ref, "start", "next f", nil)
+	if strings.Join(completion.Values, ",") != "2025-09-05" {
+		t.Errorf("Expected next Friday, got %v", completion.Values)
+	}
+
+	completion = completeArgument(t, ref, "end", "end of that w", map[string]string{"start": "2025-09-03"})
+	if strings.Join(completion.Values, ",") != "2025-09-07,2025-09-05" {
+		t.Errorf("Expected the end of the week of the start date, got %v", completion.Values)
+	}
+}
+
+func TestDateShortcuts(t *testing.T) {
+	parser := NewDateParser(time.UTC, time.Date(2025, 12, 31, 15, 0, 0, 0, time.UTC))
+	dates := make(map[string]string)
+	for _, shortcut := range dateShortcuts(parser, time.Time{}) {
+		dates[shortcut.Label] = shortcut.Date.Format(dateLayout)
+	}
+
+	expected := map[string]string{
+		"today":               "2025-12-31",
+		"tomorrow":            "2026-01-01",
+		"start of this week":  "2025-12-29",
+		"end of next week":    "2026-01-11",
+		"end of this month":   "2025-12-31",
+		"start of next month": "2026-01-01",
+		"end of next month":   "2026-01-31",
+		"next friday":         "2026-01-02",
+		"next wednesday":      "2026-01-07",
+	}
+	for label, date := range expected {
+		if dates[label] != date {
+			t.Errorf("Expected %q to be %s, got %s", label, date, dates[label])
+		}
+	}
+}
+
+func TestTruncateCompletion(t *testing.T) {
+	values := make([]string, maxCompletionValues+5)
+	for i := range values {
+		values[i] = "x"
+	}
+
+	completion := truncateCompletion(values)
+	if len(completion.Values) != maxCompletionValues {
+		t.Errorf("Expected %d values, got %d", maxCompletionValues, len(completion.Values))
+	}
+
+	if !completion.HasMore || completion.Total != maxCompletionValues+5 {
+		t.Errorf("Expected hasMore with total %d, got %+v", maxCompletionValues+5, completion)
+	}
+
+	if empty := truncateCompletion(nil); empty.Values == nil {
+		t.Error("Expected empty values to be an empty slice")
+	}
+}
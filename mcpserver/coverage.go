@@ -0,0 +1,266 @@
+package mcpserver
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+
+	"bamboohr-mcp-server/bamboohr"
+)
+
+// maxCoverageDays caps the period analyze_coverage looks at
+const maxCoverageDays = 366
+
+// CoverageAbsence is the time off of one employee on a day of the coverage analysis
+type CoverageAbsence struct {
+	EmployeeID string `json:"employeeId"`
+	Name       string `json:"name"`
+	Start      string `json:"start"`
+	End        string `json:"end"`
+	Status     string `json:"status" jsonschema:"description=approved, or requested for time off waiting for approval"`
+	RequestID  string `json:"requestId,omitempty"`
+}
+
+// CoverageDay is the headcount available on one working day
+type CoverageDay struct {
+	Date         string            `json:"date"`
+	Holiday      string            `json:"holiday,omitempty" jsonschema:"description=The company holiday on this day, if any. Holidays are not checked against the minimum."`
+	Available    int               `json:"available"`
+	BelowMinimum bool              `json:"belowMinimum"`
+	Absences     []CoverageAbsence `json:"absences"`
+}
+
+// CoverageResult is the structured output of analyze_coverage
+type CoverageResult struct {
+	EmployeeIDs      []string      `json:"employeeIds" jsonschema:"description=The team whose coverage was analyzed"`
+	Start            string        `json:"start"`
+	End              string        `json:"end"`
+	Minimum          int           `json:"minimum" jsonschema:"description=The minimum headcount that must be available each working day"`
+	IncludeRequested bool          `json:"includeRequested" jsonschema:"description=Whether time off waiting for approval was counted as absent"`
+	Shortfalls       int           `json:"shortfalls" jsonschema:"description=Number of days below the minimum"`
+	Days             []CoverageDay `json:"days"`
+}
+
+func handleAnalyzeCoverage(client BambooHR, dates *DateParser, me int) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		format, err := toolFormat(request, FormatCompact)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		locale, err := toolLocale(request, dates)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		managerID, err := toolManagerID(request, me)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		employeeIDs, err := teamEmployeeIDs(client, request.GetString("employeeIds", ""), request.GetString("department", ""), managerID)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		parser := dates.ForEmployees(employeeIDs)
+		today := parser.Today()
+		period, err := parser.ResolveRange(request.GetString("start", ""), request.GetString("end", ""), DateRange{Start: today, End: today.AddDate(0, 0, 27)})
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		if period.Days() > maxCoverageDays {
+			return mcp.NewToolResultError(fmt.Sprintf("the period is %d days long, at most %d days can be analyzed", period.Days(), maxCoverageDays)), nil
+		}
+
+		// Default to half the team, rounded up
+		minimum := request.GetInt("minimum", (len(employeeIDs)+1)/2)
+		if minimum < 1 || minimum > len(employeeIDs) {
+			return mcp.NewToolResultError(fmt.Sprintf("minimum must be between 1 and the team size of %d", len(employeeIDs))), nil
+		}
+
+		result, err := analyzeCoverage(client, employeeIDs, period, minimum,
+			request.GetBool("includeRequested", false), request.GetBool("includeWeekends", false))
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Failed to analyze coverage: %s", err.Error())), nil
+		}
+
+		return renderToolResult(result, coverageView(result, locale), format), nil
+	}
+}
+
+// analyzeCoverage counts the team members available on each working day of the period, from the
+// approved time off in who's out and, if includeRequested is set, the requests waiting for
+// approval. Weekends are skipped unless includeWeekends is set.
+func analyzeCoverage(client BambooHR, employeeIDs []int, period DateRange, minimum int, includeRequested, includeWeekends bool) (CoverageResult, error) {
+	result := CoverageResult{
+		Start:            period.StartYMD(),
+		End:              period.EndYMD(),
+		Minimum:          minimum,
+		IncludeRequested: includeRequested,
+		Days:             []CoverageDay{},
+	}
+
+	team := make(map[string]bool, len(employeeIDs))
+	for _, id := range employeeIDs {
+		result.EmployeeIDs = append(result.EmployeeIDs, strconv.Itoa(id))
+		team[strconv.Itoa(id)] = true
+	}
+
+	whosOut, err := client.GetWhosOut(result.Start, result.End)
+	if err != nil {
+		return result, fmt.Errorf("failed to get who's out: %w", err)
+	}
+
+	// Absences and holidays by day
+	absences := make(map[string][]CoverageAbsence)
+	holidays := make(map[string]string)
+	addAbsence := func(day string, absence CoverageAbsence) {
+		for _, existing := range absences[day] {
+			if existing.EmployeeID == absence.EmployeeID {
+				return
+			}
+		}
+		absences[day] = append(absences[day], absence)
+	}
+
+	for _, entry := range whosOut {
+		for day := range coverageDays(entry.Start, entry.End, period) {
+			switch {
+			case entry.Type == "holiday":
+				holidays[day] = entry.Name
+			case team[entry.EmployeeID.String()]:
+				addAbsence(day, CoverageAbsence{
+					EmployeeID: entry.EmployeeID.String(),
+					Name:       entry.Name,
+					Start:      entry.Start,
+					End:        entry.End,
+					Status:     "approved",
+				})
+			}
+		}
+	}
+
+	if includeRequested {
+		requests, err := teamTimeOffRequests(client, employeeIDs, bamboohr.TimeOffRequestQuery{
+			Start:    result.Start,
+			End:      result.End,
+			Statuses: []string{"requested"},
+		})
+		if err != nil {
+			return result, err
+		}
+
+		for _, request := range requests {
+			for day, amount := range requestDailyAmounts(request) {
+				if amount <= 0 || day < result.Start || day > result.End {
+					continue
+				}
+				addAbsence(day, CoverageAbsence{
+					EmployeeID: request.EmployeeID,
+					Name:       request.Name,
+					Start:      request.Start,
+					End:        request.End,
+					Status:     "requested",
+					RequestID:  request.ID,
+				})
+			}
+		}
+	}
+
+	for date := period.Start; !date.After(period.End); date = date.AddDate(0, 0, 1) {
+		if !includeWeekends && (date.Weekday() == time.Saturday || date.Weekday() == time.Sunday) {
+			continue
+		}
+
+		key := date.Format(dateLayout)
+		day := CoverageDay{
+			Date:      key,
+			Holiday:   holidays[key],
+			Available: len(employeeIDs) - len(absences[key]),
+			Absences:  absences[key],
+		}
+		if day.Absences == nil {
+			day.Absences = []CoverageAbsence{}
+		}
+		sort.SliceStable(day.Absences, func(i, j int) bool {
+			return day.Absences[i].Name < day.Absences[j].Name
+		})
+
+		if day.Holiday == "" && day.Available < minimum {
+			day.BelowMinimum = true
+			result.Shortfalls++
+		}
+
+		result.Days = append(result.Days, day)
+	}
+
+	return result, nil
+}
+
+// coverageDays returns the days (YYYY-MM-DD) from start to end that fall within the period
+func coverageDays(start, end string, period DateRange) map[string]bool {
+	days := make(map[string]bool)
+
+	from, err := time.Parse(dateLayout, start)
+	if err != nil {
+		return days
+	}
+	to, err := time.Parse(dateLayout, end)
+	if err != nil || to.Before(from) {
+		to = from
+	}
+
+	for day := from; !day.After(to); day = day.AddDate(0, 0, 1) {
+		if key := day.Format(dateLayout); key >= period.StartYMD() && key <= period.EndYMD() {
+			days[key] = true
+		}
+	}
+
+	return days
+}
+
+// coverageView shows the coverage analysis as text. The summary lists only the days below the
+// minimum, while tables list every working day.
+func coverageView(result CoverageResult, locale Locale) textView {
+	view := textView{
+		Title: fmt.Sprintf("Coverage of %s between %s and %s: %s below the minimum of %d available",
+			pluralize(len(result.EmployeeIDs), "employee", "employees"),
+			locale.FormatDate(result.Start), locale.FormatDate(result.End),
+			pluralize(result.Shortfalls, "day", "days"), result.Minimum),
+		Columns: []string{"Date", "Available", "Out", "Below Minimum", "Holiday", "Absences"},
+	}
+
+	for _, day := range result.Days {
+		var absences []string
+		for _, absence := range day.Absences {
+			description := fmt.Sprintf("%s (%s)", absence.Name, absence.Status)
+			if absence.RequestID != "" {
+				description = fmt.Sprintf("%s (%s, request %s)", absence.Name, absence.Status, absence.RequestID)
+			}
+			absences = append(absences, description)
+		}
+
+		if day.BelowMinimum {
+			view.Items = append(view.Items, fmt.Sprintf("%s: %d of %d available, out: %s",
+				locale.FormatDate(day.Date), day.Available, len(result.EmployeeIDs), strings.Join(absences, ", ")))
+		}
+
+		view.Rows = append(view.Rows, []string{
+			locale.FormatDate(day.Date),
+			strconv.Itoa(day.Available),
+			strconv.Itoa(len(day.Absences)),
+			strconv.FormatBool(day.BelowMinimum),
+			day.Holiday,
+			strings.Join(absences, "; "),
+		})
+	}
+
+	return view
+}
@@ -0,0 +1,66 @@
+package mcpserver
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestAnalyzeCoverage(t *testing.T) {
+	s := New(newApprovalsTestFake(), newTestDateParser())
+
+	arguments := map[string]string{"employeeIds": "2,4,5", "start": "2025-10-06", "end": "2025-10-12"}
+	text, isError := callTool(t, s, "analyze_coverage", arguments)
+	if isError || text != "Coverage of 3 employees between 2025-10-06 and 2025-10-12: 0 days below the minimum of 2 available." {
+		t.Errorf("Unexpected coverage: %q", text)
+	}
+
+	arguments["includeRequested"] = "true"
+	text, isError = callTool(t, s, "analyze_coverage", arguments)
+	expected := "Coverage of 3 employees between 2025-10-06 and 2025-10-12: 2 days below the minimum of 2 available:\n" +
+		"- 2025-10-07: 1 of 3 available, out: Dan Diaz (requested, request 10), Eve Evans (requested, request 11)\n" +
+		"- 2025-10-08: 1 of 3 available, out: Dan Diaz (requested, request 10), Eve Evans (approved)"
+	if isError || text != expected {
+		t.Errorf("Expected %q, got %q", expected, text)
+	}
+
+	arguments["format"] = "full"
+	text, _ = callTool(t, s, "analyze_coverage", arguments)
+	var result CoverageResult
+	if err := json.Unmarshal([]byte(text), &result); err != nil {
+		t.Fatalf("Failed to decode result: %v", err)
+	}
+
+	// Weekends are skipped, and the holiday is not checked against the minimum
+	if len(result.Days) != 5 {
+		t.Fatalf("Expected 5 working days, got %d", len(result.Days))
+	}
+	if monday := result.Days[0]; monday.Holiday != "Labour Day" || monday.BelowMinimum || monday.Available != 2 {
+		t.Errorf("Unexpected holiday: %+v", monday)
+	}
+
+	arguments["includeWeekends"] = "true"
+	arguments["minimum"] = "3"
+	text, _ = callTool(t, s, "analyze_coverage", arguments)
+	if err := json.Unmarshal([]byte(text), &result); err != nil {
+		t.Fatalf("Failed to decode result: %v", err)
+	}
+	if len(result.Days) != 7 || result.Shortfalls != 3 {
+		t.Errorf("Expected 7 days with 3 shortfalls, got %d days with %d shortfalls", len(result.Days), result.Shortfalls)
+	}
+}
+
+func TestAnalyzeCoverage_Errors(t *testing.T) {
+	s := New(newApprovalsTestFake(), newTestDateParser())
+
+	tests := []map[string]string{
+		{},
+		{"employeeIds": "4,5", "minimum": "3"},
+		{"employeeIds": "4,5", "minimum": "0"},
+		{"employeeIds": "4,5", "start": "2025-01-01", "end": "2026-12-31"},
+	}
+	for _, arguments := range tests {
+		if text, isError := callTool(t, s, "analyze_coverage", arguments); !isError {
+			t.Errorf("Expected tool error for %v, got %q", arguments, text)
+		}
+	}
+}
@@ -0,0 +1,518 @@
+package mcpserver
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// dateLayout is the YYYY-MM-DD date format used by the BambooHR API
+const dateLayout = "2006-01-02"
+
+// dateHint is appended to date parsing errors to show which inputs are accepted
+const dateHint = `expected YYYY-MM-DD or an expression such as "today", "next friday", "Dec 23", "Dec 23 - Jan 2" or "this quarter"`
+
+// DateRange is an inclusive range of calendar dates
+type DateRange struct {
+	Start time.Time
+	End   time.Time
+}
+
+// StartYMD returns the start of the range in YYYY-MM-DD format
+func (r DateRange) StartYMD() string {
+	return r.Start.Format(dateLayout)
+}
+
+// EndYMD returns the end of the range in YYYY-MM-DD format
+func (r DateRange) EndYMD() string {
+	return r.End.Format(dateLayout)
+}
+
+// Days returns the number of calendar days in the range
+func (r DateRange) Days() int {
+	return int(r.End.Sub(r.Start).Hours()/24+0.5) + 1
+}
+
+// DateParser parses the date arguments of tools and prompts. Relative expressions such as
+// "tomorrow" or "this quarter" are resolved against a reference date in a configured timezone.
+// It also carries the locale dates in results are formatted for.
+type DateParser struct {
+	location  *time.Location
+	reference time.Time
+	// fixed means the reference is a calendar date, the same in every timezone
+	fixed     bool
+	locale    Locale
+	timezones *TimezoneResolver
+}
+
+// NewDateParser creates a date parser for the given timezone. A zero reference date means
+// relative expressions are resolved against the current date.
+func NewDateParser(location *time.Location, reference time.Time) *DateParser {
+	if location == nil {
+		location = time.Local
+	}
+
+	return &DateParser{
+		location:  location,
+		reference: reference,
+	}
+}
+
+// WithReferenceDate returns a copy of the parser that resolves relative expressions against
+// the calendar date of the given time, whichever timezone dates are resolved in
+func (p *DateParser) WithReferenceDate(date time.Time) *DateParser {
+	parser := *p
+	parser.reference = date
+	parser.fixed = true
+	return &parser
+}
+
+// WithLocale returns a copy of the parser that formats dates in results for the locale
+func (p *DateParser) WithLocale(locale Locale) *DateParser {
+	parser := *p
+	parser.locale = locale
+	return &parser
+}
+
+// WithTimezones returns a copy of the parser that resolves dates for an employee in the
+// timezone of their work location
+func (p *DateParser) WithTimezones(timezones *TimezoneResolver) *DateParser {
+	parser := *p
+	parser.timezones = timezones
+	return &parser
+}
+
+// In returns a copy of the parser that resolves dates in the given timezone
+func (p *DateParser) In(location *time.Location) *DateParser {
+	parser := *p
+	parser.location = location
+	return &parser
+}
+
+// ForEmployee returns a parser that resolves dates in the timezone of the employee's work
+// location, or the configured timezone if it cannot be determined
+func (p *DateParser) ForEmployee(employeeID int) *DateParser {
+	if p.timezones == nil || employeeID == 0 {
+		return p
+	}
+
+	location := p.timezones.Resolve(employeeID)
+	if location == nil {
+		return p
+	}
+
+	return p.In(location)
+}
+
+// ForEmployees returns the parser for the employee when the IDs name a single employee, and the
+// parser itself for a team, as a team has no one timezone
+func (p *DateParser) ForEmployees(employeeIDs []int) *DateParser {
+	if len(employeeIDs) != 1 {
+		return p
+	}
+	return p.ForEmployee(employeeIDs[0])
+}
+
+// Location returns the timezone dates are resolved in
+func (p *DateParser) Location() *time.Location {
+	return p.location
+}
+
+// Locale returns the locale dates in results are formatted for
+func (p *DateParser) Locale() Locale {
+	if p.locale.layout == "" {
+		return ISOLocale
+	}
+	return p.locale
+}
+
+// Today returns the current date, or the reference date, at midnight in the parser's timezone
+func (p *DateParser) Today() time.Time {
+	now := p.reference
+	if now.IsZero() {
+		now = time.Now()
+	}
+	if !p.fixed {
+		now = now.In(p.location)
+	}
+
+	return time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, p.location)
+}
+
+// CurrentYear returns the range from January 1st to December 31st of the current year
+func (p *DateParser) CurrentYear() DateRange {
+	return yearRange(p.Today().Year(), p.location)
+}
+
+// ParseDate parses a single date such as "2025-12-23", "tomorrow", "next friday" or "Dec 23"
+func (p *DateParser) ParseDate(value string) (time.Time, error) {
+	normalized := normalizeDateInput(value)
+	if normalized == "" {
+		return time.Time{}, fmt.Errorf("date is empty: %s", dateHint)
+	}
+
+	date, _, ok := p.parseDate(normalized)
+	if !ok {
+		return time.Time{}, fmt.Errorf("invalid date %q: %s", strings.TrimSpace(value), dateHint)
+	}
+
+	return date, nil
+}
+
+// ParseRange parses a single date, a period such as "this quarter" or "December 2025",
+// or a range such as "Dec 23 - Jan 2" or "2025-12-23 to 2026-01-02"
+func (p *DateParser) ParseRange(value string) (DateRange, error) {
+	normalized := normalizeDateInput(value)
+	if normalized == "" {
+		return DateRange{}, fmt.Errorf("date is empty: %s", dateHint)
+	}
+
+	invalid := fmt.Errorf("invalid date %q: %s", strings.TrimSpace(value), dateHint)
+
+	if r, ok := p.parsePeriod(normalized); ok {
+		return r, nil
+	}
+
+	if date, _, ok := p.parseDate(normalized); ok {
+		return DateRange{Start: date, End: date}, nil
+	}
+
+	left, right, ok := splitDateRange(normalized)
+	if !ok {
+		return DateRange{}, invalid
+	}
+
+	var r DateRange
+	if period, ok := p.parsePeriod(left); ok {
+		r.Start = period.Start
+	} else if date, _, ok := p.parseDate(left); ok {
+		r.Start = date
+	} else {
+		return DateRange{}, invalid
+	}
+
+	if day, err := strconv.Atoi(right); err == nil && len(right) <= 2 {
+		// "Dec 23 - 27" ends in the same month as it starts
+		r.End = time.Date(r.Start.Year(), r.Start.Month(), day, 0, 0, 0, 0, p.location)
+		if day < 1 || r.End.Month() != r.Start.Month() {
+			return DateRange{}, invalid
+		}
+	} else if period, ok := p.parsePeriod(right); ok {
+		r.End = period.End
+	} else if date, explicitYear, ok := p.parseDate(right); ok {
+		r.End = date
+		// "Dec 23 - Jan 2" ends in the year after it starts
+		if !explicitYear && r.End.Before(r.Start) {
+			r.End = r.End.AddDate(1, 0, 0)
+		}
+	} else {
+		return DateRange{}, invalid
+	}
+
+	if r.End.Before(r.Start) {
+		return DateRange{}, fmt.Errorf("invalid date range %q: end %s is before start %s", strings.TrimSpace(value), r.EndYMD(), r.StartYMD())
+	}
+
+	return r, nil
+}
+
+// ResolveRange resolves the start and end arguments of a tool or prompt into a date range.
+// The start may itself be a range or period when no end is given. If neither is given the
+// fallback range is returned.
+func (p *DateParser) ResolveRange(start, end string, fallback DateRange) (DateRange, error) {
+	start = strings.TrimSpace(start)
+	end = strings.TrimSpace(end)
+
+	if start == "" && end == "" {
+		return fallback, nil
+	}
+
+	if start == "" {
+		return DateRange{}, fmt.Errorf("start date is required when an end date is given")
+	}
+
+	startRange, err := p.ParseRange(start)
+	if err != nil {
+		return DateRange{}, fmt.Errorf("invalid start: %w", err)
+	}
+
+	if end == "" {
+		return startRange, nil
+	}
+
+	endRange, err := p.ParseRange(end)
+	if err != nil {
+		return DateRange{}, fmt.Errorf("invalid end: %w", err)
+	}
+
+	r := DateRange{Start: startRange.Start, End: endRange.End}
+	if r.End.Before(r.Start) {
+		return DateRange{}, fmt.Errorf("end date %s is before start date %s", r.EndYMD(), r.StartYMD())
+	}
+
+	return r, nil
+}
+
+var (
+	ordinalSuffix  = regexp.MustCompile(`\b(\d{1,2})(st|nd|rd|th)\b`)
+	relativeOffset = regexp.MustCompile(`^in (\d+) (day|week|month)s?$`)
+	pastOffset     = regexp.MustCompile(`^(\d+) (day|week|month)s? ago$`)
+	comingPeriod   = regexp.MustCompile(`^(?:next|the next|coming) (\d+) (day|week)s?$`)
+	quarterPattern = regexp.MustCompile(`^q([1-4])(?: (\d{4}))?$|^(\d{4}) q([1-4])$`)
+	yearPattern    = regexp.MustCompile(`^\d{4}$`)
+	isoDatePattern = regexp.MustCompile(`^\d{4}-\d{2}-\d{2}$`)
+)
+
+// monthDayLayouts are the layouts accepted for dates written with a month name
+var monthDayLayouts = []struct {
+	layout       string
+	explicitYear bool
+}{
+	{"Jan 2 2006", true},
+	{"January 2 2006", true},
+	{"2 Jan 2006", true},
+	{"2 January 2006", true},
+	{"Jan 2", false},
+	{"January 2", false},
+	{"2 Jan", false},
+	{"2 January", false},
+}
+
+// normalizeDateInput lower-cases the input and removes punctuation that does not affect its meaning
+func normalizeDateInput(value string) string {
+	value = strings.ToLower(strings.TrimSpace(value))
+	value = strings.NewReplacer("–", " - ", "—", " - ", ",", " ", ".", " ").Replace(value)
+	value = ordinalSuffix.ReplaceAllString(value, "$1")
+	value = strings.Join(strings.Fields(value), " ")
+	value = strings.TrimPrefix(value, "from ")
+	value = strings.TrimPrefix(value, "on ")
+
+	if strings.HasPrefix(value, "between ") {
+		value = strings.Replace(strings.TrimPrefix(value, "between "), " and ", " - ", 1)
+	}
+
+	return value
+}
+
+// splitDateRange splits a range expression into its start and end parts
+func splitDateRange(value string) (string, string, bool) {
+	for _, separator := range []string{" - ", " to ", " until ", " till ", " through "} {
+		if left, right, ok := strings.Cut(value, separator); ok {
+			left, right = strings.TrimSpace(left), strings.TrimSpace(right)
+			if left != "" && right != "" {
+				return left, right, true
+			}
+		}
+	}
+
+	return "", "", false
+}
+
+// parseDate parses a normalized single date expression, reporting whether it included a year
+func (p *DateParser) parseDate(value string) (time.Time, bool, bool) {
+	today := p.Today()
+
+	if isoDatePattern.MatchString(value) {
+		date, err := time.ParseInLocation(dateLayout, value, p.location)
+		return date, true, err == nil
+	}
+
+	switch value {
+	case "today", "now":
+		return today, true, true
+	case "tomorrow":
+		return today.AddDate(0, 0, 1), true, true
+	case "yesterday":
+		return today.AddDate(0, 0, -1), true, true
+	}
+
+	if m := relativeOffset.FindStringSubmatch(value); m != nil {
+		n, _ := strconv.Atoi(m[1])
+		return addUnits(today, m[2], n), true, true
+	}
+
+	if m := pastOffset.FindStringSubmatch(value); m != nil {
+		n, _ := strconv.Atoi(m[1])
+		return addUnits(today, m[2], -n), true, true
+	}
+
+	for _, prefix := range []string{"start of ", "beginning of ", "end of "} {
+		if rest, ok := strings.CutPrefix(value, prefix); ok {
+			period, ok := p.parsePeriod(rest)
+			if !ok {
+				return time.Time{}, false, false
+			}
+			if prefix == "end of " {
+				return period.End, true, true
+			}
+			return period.Start, true, true
+		}
+	}
+
+	if date, ok := parseWeekday(today, value); ok {
+		return date, true, true
+	}
+
+	for _, candidate := range monthDayLayouts {
+		date, err := time.ParseInLocation(candidate.layout, value, p.location)
+		if err != nil {
+			continue
+		}
+		if !candidate.explicitYear {
+			month, day := date.Month(), date.Day()
+			date = time.Date(today.Year(), month, day, 0, 0, 0, 0, p.location)
+			// "Feb 29" is not a date this year, rather than March 1
+			if date.Month() != month || date.Day() != day {
+				return time.Time{}, false, false
+			}
+		}
+		return date, candidate.explicitYear, true
+	}
+
+	return time.Time{}, false, false
+}
+
+// parsePeriod parses a normalized expression naming a period, such as "next week" or "q3 2026"
+func (p *DateParser) parsePeriod(value string) (DateRange, bool) {
+	today := p.Today()
+	loc := p.location
+
+	if relative, unit, ok := strings.Cut(value, " "); ok {
+		offset, known := map[string]int{"this": 0, "current": 0, "next": 1, "last": -1, "previous": -1}[relative]
+		if known {
+			switch unit {
+			case "week":
+				monday, sunday := weekRange(today.AddDate(0, 0, 7*offset))
+				return DateRange{Start: monday, End: sunday}, true
+			case "weekend":
+				_, sunday := weekRange(today.AddDate(0, 0, 7*offset))
+				return DateRange{Start: sunday.AddDate(0, 0, -1), End: sunday}, true
+			case "month":
+				first := time.Date(today.Year(), today.Month()+time.Month(offset), 1, 0, 0, 0, 0, loc)
+				return DateRange{Start: first, End: first.AddDate(0, 1, -1)}, true
+			case "quarter":
+				quarterStart := time.Month((int(today.Month())-1)/3*3 + 1)
+				first := time.Date(today.Year(), quarterStart+time.Month(3*offset), 1, 0, 0, 0, 0, loc)
+				return DateRange{Start: first, End: first.AddDate(0, 3, -1)}, true
+			case "year":
+				return yearRange(today.Year()+offset, loc), true
+			}
+		}
+	}
+
+	if m := comingPeriod.FindStringSubmatch(value); m != nil {
+		n, _ := strconv.Atoi(m[1])
+		if n < 1 {
+			return DateRange{}, false
+		}
+		return DateRange{Start: today, End: addUnits(today, m[2], n).AddDate(0, 0, -1)}, true
+	}
+
+	if m := quarterPattern.FindStringSubmatch(value); m != nil {
+		quarter, year := m[1], m[2]
+		if quarter == "" {
+			year, quarter = m[3], m[4]
+		}
+		y := today.Year()
+		if year != "" {
+			y, _ = strconv.Atoi(year)
+		}
+		q, _ := strconv.Atoi(quarter)
+		first := time.Date(y, time.Month(3*(q-1)+1), 1, 0, 0, 0, 0, loc)
+		return DateRange{Start: first, End: first.AddDate(0, 3, -1)}, true
+	}
+
+	if yearPattern.MatchString(value) {
+		y, _ := strconv.Atoi(value)
+		return yearRange(y, loc), true
+	}
+
+	for _, layout := range []string{"Jan 2006", "January 2006", "Jan", "January"} {
+		month, err := time.Parse(layout, value)
+		if err != nil {
+			continue
+		}
+		y := month.Year()
+		if !strings.Contains(layout, "2006") {
+			y = today.Year()
+		}
+		first := time.Date(y, month.Month(), 1, 0, 0, 0, 0, loc)
+		return DateRange{Start: first, End: first.AddDate(0, 1, -1)}, true
+	}
+
+	return DateRange{}, false
+}
+
+// parseWeekday resolves "friday", "this friday", "next friday" and "last friday" relative to today.
+// A bare weekday or "next" means the first such day after today, "this" means the day in the
+// current Monday to Sunday week, and "last" means the most recent such day before today.
+func parseWeekday(today time.Time, value string) (time.Time, bool) {
+	relative, name, ok := strings.Cut(value, " ")
+	if !ok {
+		relative, name = "", value
+	}
+
+	weekday, ok := weekdays[name]
+	if !ok {
+		return time.Time{}, false
+	}
+
+	switch relative {
+	case "", "next", "coming", "upcoming":
+		days := (int(weekday) - int(today.Weekday()) + 7) % 7
+		if days == 0 {
+			days = 7
+		}
+		return today.AddDate(0, 0, days), true
+	case "this":
+		monday, _ := weekRange(today)
+		return monday.AddDate(0, 0, (int(weekday)+6)%7), true
+	case "last", "previous":
+		days := (int(today.Weekday()) - int(weekday) + 7) % 7
+		if days == 0 {
+			days = 7
+		}
+		return today.AddDate(0, 0, -days), true
+	}
+
+	return time.Time{}, false
+}
+
+// weekdays maps full and abbreviated weekday names to their time.Weekday
+var weekdays = map[string]time.Weekday{
+	"sunday": time.Sunday, "sun": time.Sunday,
+	"monday": time.Monday, "mon": time.Monday,
+	"tuesday": time.Tuesday, "tue": time.Tuesday, "tues": time.Tuesday,
+	"wednesday": time.Wednesday, "wed": time.Wednesday,
+	"thursday": time.Thursday, "thu": time.Thursday, "thurs": time.Thursday,
+	"friday": time.Friday, "fri": time.Friday,
+	"saturday": time.Saturday, "sat": time.Saturday,
+}
+
+// addUnits adds n days, weeks or months to date
+func addUnits(date time.Time, unit string, n int) time.Time {
+	switch unit {
+	case "week":
+		return date.AddDate(0, 0, 7*n)
+	case "month":
+		return date.AddDate(0, n, 0)
+	}
+	return date.AddDate(0, 0, n)
+}
+
+// yearRange returns the range from January 1st to December 31st of year
+func yearRange(year int, loc *time.Location) DateRange {
+	return DateRange{
+		Start: time.Date(year, time.January, 1, 0, 0, 0, 0, loc),
+		End:   time.Date(year, time.December, 31, 0, 0, 0, 0, loc),
+	}
+}
+
+// weekRange returns the Monday and Sunday of the week containing t
+func weekRange(t time.Time) (time.Time, time.Time) {
+	offset := (int(t.Weekday()) + 6) % 7
+	monday := time.Date(t.Year(), t.Month(), t.Day()-offset, 0, 0, 0, 0, t.Location())
+	return monday, monday.AddDate(0, 0, 6)
+}
@@ -0,0 +1,328 @@
+package mcpserver
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"bamboohr-mcp-server/bamboohr"
+)
+
+// newTestDateParser returns a UTC date parser whose reference date is Wednesday 2025-09-03
+func newTestDateParser() *DateParser {
+	return NewDateParser(time.UTC, time.Date(2025, 9, 3, 12, 0, 0, 0, time.UTC))
+}
+
+func TestDateParser_ParseDate(t *testing.T) {
+	parser := newTestDateParser()
+
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"2025-12-23", "2025-12-23"},
+		{" 2026-01-02 ", "2026-01-02"},
+		{"today", "2025-09-03"},
+		{"Tomorrow", "2025-09-04"},
+		{"yesterday", "2025-09-02"},
+		{"friday", "2025-09-05"},
+		{"next Friday", "2025-09-05"},
+		{"next wednesday", "2025-09-10"},
+		{"this monday", "2025-09-01"},
+		{"this sunday", "2025-09-07"},
+		{"last friday", "2025-08-29"},
+		{"on fri", "2025-09-05"},
+		{"in 3 days", "2025-09-06"},
+		{"in 2 weeks", "2025-09-17"},
+		{"in 1 month", "2025-10-03"},
+		{"2 weeks ago", "2025-08-20"},
+		{"Dec 23", "2025-12-23"},
+		{"December 23rd", "2025-12-23"},
+		{"23 Dec", "2025-12-23"},
+		{"Dec 23, 2026", "2026-12-23"},
+		{"2 January 2026", "2026-01-02"},
+		{"Feb 29, 2028", "2028-02-29"},
+		{"end of this month", "2025-09-30"},
+		{"start of next month", "2025-10-01"},
+		{"end of this quarter", "2025-09-30"},
+		{"beginning of next year", "2026-01-01"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			date, err := parser.ParseDate(tt.input)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			if date.Format(dateLayout) != tt.expected {
+				t.Errorf("Expected %s, got %s", tt.expected, date.Format(dateLayout))
+			}
+
+			if date.Location() != time.UTC {
+				t.Errorf("Expected date in the parser's timezone, got %v", date.Location())
+			}
+		})
+	}
+}
+
+func TestDateParser_ParseDate_Invalid(t *testing.T) {
+	parser := newTestDateParser()
+
+	for _, input := range []string{"", "someday", "2025-02-30", "2025-13-01", "25-12-2025", "this week", "Dec 32", "Feb 29"} {
+		t.Run(input, func(t *testing.T) {
+			if _, err := parser.ParseDate(input); err == nil {
+				t.Errorf("Expected error for %q, but got none", input)
+			}
+		})
+	}
+}
+
+func TestDateParser_ParseRange(t *testing.T) {
+	parser := newTestDateParser()
+
+	tests := []struct {
+		input         string
+		expectedStart string
+		expectedEnd   string
+	}{
+		{"2025-12-23", "2025-12-23", "2025-12-23"},
+		{"Dec 23 – Jan 2", "2025-12-23", "2026-01-02"},
+		{"Dec 23 - Jan 2", "2025-12-23", "2026-01-02"},
+		{"from Dec 23 to Jan 2 2026", "2025-12-23", "2026-01-02"},
+		{"between 2025-10-06 and 2025-10-10", "2025-10-06", "2025-10-10"},
+		{"Dec 22 - 24", "2025-12-22", "2025-12-24"},
+		{"next friday until next wednesday", "2025-09-05", "2025-09-10"},
+		{"this week", "2025-09-01", "2025-09-07"},
+		{"next week", "2025-09-08", "2025-09-14"},
+		{"last week", "2025-08-25", "2025-08-31"},
+		{"this weekend", "2025-09-06", "2025-09-07"},
+		{"this month", "2025-09-01", "2025-09-30"},
+		{"next month", "2025-10-01", "2025-10-31"},
+		{"this quarter", "2025-07-01", "2025-09-30"},
+		{"next quarter", "2025-10-01", "2025-12-31"},
+		{"last quarter", "2025-04-01", "2025-06-30"},
+		{"Q1 2026", "2026-01-01", "2026-03-31"},
+		{"q4", "2025-10-01", "2025-12-31"},
+		{"this year", "2025-01-01", "2025-12-31"},
+		{"next year", "2026-01-01", "2026-12-31"},
+		{"2024", "2024-01-01", "2024-12-31"},
+		{"December", "2025-12-01", "2025-12-31"},
+		{"February 2028", "2028-02-01", "2028-02-29"},
+		{"next 2 weeks", "2025-09-03", "2025-09-16"},
+		{"next 10 days", "2025-09-03", "2025-09-12"},
+		{"next week - next quarter", "2025-09-08", "2025-12-31"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			r, err := parser.ParseRange(tt.input)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			if r.StartYMD() != tt.expectedStart || r.EndYMD() != tt.expectedEnd {
+				t.Errorf("Expected %s to %s, got %s to %s", tt.expectedStart, tt.expectedEnd, r.StartYMD(), r.EndYMD())
+			}
+		})
+	}
+}
+
+func TestDateParser_ParseRange_Invalid(t *testing.T) {
+	parser := newTestDateParser()
+
+	tests := []struct {
+		input         string
+		expectedError string
+	}{
+		{"whenever", "invalid date"},
+		{"Dec 23 - someday", "invalid date"},
+		{"2026-01-02 - 2025-12-23", "is before start"},
+		{"Feb 27 - 30", "invalid date"},
+		{"next 0 days", "invalid date"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			_, err := parser.ParseRange(tt.input)
+			if err == nil {
+				t.Fatalf("Expected error for %q, but got none", tt.input)
+			}
+
+			if !strings.Contains(err.Error(), tt.expectedError) {
+				t.Errorf("Expected error containing %q, got %q", tt.expectedError, err.Error())
+			}
+		})
+	}
+}
+
+func TestDateParser_ResolveRange(t *testing.T) {
+	parser := newTestDateParser()
+	fallback := parser.CurrentYear()
+
+	tests := []struct {
+		name          string
+		start         string
+		end           string
+		expectedStart string
+		expectedEnd   string
+		expectedError string
+	}{
+		{"Defaults to the fallback", "", "", "2025-01-01", "2025-12-31", ""},
+		{"ISO start and end", "2025-10-01", "2025-10-31", "2025-10-01", "2025-10-31", ""},
+		{"Range in start only", "Dec 23 - Jan 2", "", "2025-12-23", "2026-01-02", ""},
+		{"Period in start only", "this quarter", "", "2025-07-01", "2025-09-30", ""},
+		{"Single date in start only", "next friday", "", "2025-09-05", "2025-09-05", ""},
+		{"Periods in start and end", "next week", "next month", "2025-09-08", "2025-10-31", ""},
+		{"End without start", "", "2025-10-31", "", "", "start date is required"},
+		{"End before start", "2025-10-31", "2025-10-01", "", "", "is before start date"},
+		{"Invalid start", "soon", "", "", "", "invalid start"},
+		{"Invalid end", "today", "later", "", "", "invalid end"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, err := parser.ResolveRange(tt.start, tt.end, fallback)
+			if tt.expectedError != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.expectedError) {
+					t.Errorf("Expected error containing %q, got %v", tt.expectedError, err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			if r.StartYMD() != tt.expectedStart || r.EndYMD() != tt.expectedEnd {
+				t.Errorf("Expected %s to %s, got %s to %s", tt.expectedStart, tt.expectedEnd, r.StartYMD(), r.EndYMD())
+			}
+		})
+	}
+}
+
+func TestDateParser_Timezone(t *testing.T) {
+	sydney, err := time.LoadLocation("Australia/Sydney")
+	if err != nil {
+		t.Skipf("timezone data not available: %v", err)
+	}
+
+	// 20:00 UTC on New Year's Eve is already New Year's Day in Sydney
+	reference := time.Date(2025, 12, 31, 20, 0, 0, 0, time.UTC)
+
+	if today := NewDateParser(time.UTC, reference).Today(); today.Format(dateLayout) != "2025-12-31" {
+		t.Errorf("Expected 2025-12-31 in UTC, got %s", today.Format(dateLayout))
+	}
+
+	parser := NewDateParser(sydney, reference)
+	if today := parser.Today(); today.Format(dateLayout) != "2026-01-01" {
+		t.Errorf("Expected 2026-01-01 in Sydney, got %s", today.Format(dateLayout))
+	}
+
+	if year := parser.CurrentYear(); year.StartYMD() != "2026-01-01" || year.EndYMD() != "2026-12-31" {
+		t.Errorf("Expected the current year to be 2026 in Sydney, got %s to %s", year.StartYMD(), year.EndYMD())
+	}
+}
+
+func TestDateParser_WithReferenceDate(t *testing.T) {
+	kiritimati, err := time.LoadLocation("Pacific/Kiritimati")
+	if err != nil {
+		t.Skipf("timezone data not available: %v", err)
+	}
+
+	// Kiritimati is 14 hours ahead of UTC, and Pago Pago 11 hours behind
+	parser := NewDateParser(time.UTC, time.Time{}).WithReferenceDate(time.Date(2025, 12, 31, 0, 0, 0, 0, time.UTC))
+	for _, location := range []*time.Location{time.UTC, kiritimati, time.FixedZone("Pago Pago", -11*60*60)} {
+		today := parser.In(location).Today()
+		if today.Format(dateLayout) != "2025-12-31" || today.Location() != location {
+			t.Errorf("Expected 2025-12-31 in %s, got %v", location, today)
+		}
+	}
+}
+
+func TestWeekRange(t *testing.T) {
+	tests := []struct {
+		date           string
+		expectedMonday string
+		expectedSunday string
+	}{
+		{"2025-09-03", "2025-09-01", "2025-09-07"},
+		{"2025-09-01", "2025-09-01", "2025-09-07"},
+		{"2025-09-07", "2025-09-01", "2025-09-07"},
+		{"2025-12-31", "2025-12-29", "2026-01-04"},
+	}
+
+	for _, tt := range tests {
+		date, _ := time.Parse(dateLayout, tt.date)
+		monday, sunday := weekRange(date)
+		if monday.Format(dateLayout) != tt.expectedMonday || sunday.Format(dateLayout) != tt.expectedSunday {
+			t.Errorf("weekRange(%s): expected %s to %s, got %s to %s", tt.date, tt.expectedMonday, tt.expectedSunday,
+				monday.Format(dateLayout), sunday.Format(dateLayout))
+		}
+	}
+}
+
+func TestDateRange_Days(t *testing.T) {
+	parser := newTestDateParser()
+
+	r, err := parser.ParseRange("Dec 23 - Jan 2")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if r.Days() != 11 {
+		t.Errorf("Expected 11 days, got %d", r.Days())
+	}
+}
+
+func TestHandleGetTimeOffRequests_RelativeDates(t *testing.T) {
+	var query string
+	mock := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/time_off/requests" {
+			query = r.URL.RawQuery
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`[]`))
+	}))
+	defer mock.Close()
+
+	client := bamboohr.NewClient("testcompany", "testkey")
+	client.BaseURL = mock.URL
+	client.V1BaseURL = mock.URL
+	handler := handleGetTimeOffRequests(client, newTestDateParser(), 0)
+
+	request := mcp.CallToolRequest{}
+	request.Params.Arguments = map[string]any{"employeeId": "157", "start": "Dec 23 – Jan 2"}
+
+	result, err := handler(t.Context(), request)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if result.IsError {
+		t.Fatalf("Unexpected tool error: %v", result.Content)
+	}
+
+	if query != "employeeId=157&end=2026-01-02&start=2025-12-23" {
+		t.Errorf("Expected resolved dates in query, got %s", query)
+	}
+
+	request.Params.Arguments = map[string]any{"employeeId": "157", "start": "2025-12-23", "end": "sometime"}
+	result, err = handler(t.Context(), request)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if !result.IsError {
+		t.Fatal("Expected tool error for invalid end date")
+	}
+
+	data, _ := json.Marshal(result.Content)
+	if !strings.Contains(string(data), "invalid end") {
+		t.Errorf("Expected invalid end error, got %s", data)
+	}
+}
@@ -0,0 +1,251 @@
+package mcpserver
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"slices"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"bamboohr-mcp-server/bamboohr"
+)
+
+// fakeBambooHR is an in-memory BambooHR for handler tests
+type fakeBambooHR struct {
+	mu sync.Mutex
+
+	employees []bamboohr.Employee
+	requests  map[int][]bamboohr.TimeOffRequest
+	balances  map[int][]bamboohr.TimeOffBalance
+	types     bamboohr.TimeOffTypes
+	policies  []bamboohr.TimeOffPolicy
+	assigned  map[int][]bamboohr.EmployeeTimeOffPolicy
+	whosOut   []bamboohr.WhosOutEntry
+	changes   []bamboohr.EmployeeChange
+
+	// err is returned by every call when set
+	err error
+	// calls records the name of every call made
+	calls []string
+	// created records the payload of every created request
+	created []bamboohr.TimeOffRequestCreate
+}
+
+// newFakeBambooHR creates an empty fake with the given employees
+func newFakeBambooHR(employees ...bamboohr.Employee) *fakeBambooHR {
+	return &fakeBambooHR{
+		employees: employees,
+		requests:  make(map[int][]bamboohr.TimeOffRequest),
+		balances:  make(map[int][]bamboohr.TimeOffBalance),
+		assigned:  make(map[int][]bamboohr.EmployeeTimeOffPolicy),
+	}
+}
+
+func (f *fakeBambooHR) record(call string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.calls = append(f.calls, call)
+	return f.err
+}
+
+func (f *fakeBambooHR) Company() string {
+	return "testcompany"
+}
+
+func (f *fakeBambooHR) GetTimeOffRequests(employeeID int, start, end string) ([]bamboohr.TimeOffRequest, error) {
+	if err := f.record("GetTimeOffRequests"); err != nil {
+		return nil, err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	var requests []bamboohr.TimeOffRequest
+	for _, request := range f.requests[employeeID] {
+		if (start == "" || request.End >= start) && (end == "" || request.Start <= end) {
+			requests = append(requests, request)
+		}
+	}
+	return requests, nil
+}
+
+func (f *fakeBambooHR) SearchTimeOffRequests(query bamboohr.TimeOffRequestQuery) ([]bamboohr.TimeOffRequest, error) {
+	if err := f.record("SearchTimeOffRequests"); err != nil {
+		return nil, err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	var employeeIDs []int
+	for id := range f.requests {
+		if query.EmployeeID == 0 || id == query.EmployeeID {
+			employeeIDs = append(employeeIDs, id)
+		}
+	}
+	sort.Ints(employeeIDs)
+
+	var requests []bamboohr.TimeOffRequest
+	for _, id := range employeeIDs {
+		for _, request := range f.requests[id] {
+			if (query.Start != "" && request.End < query.Start) || (query.End != "" && request.Start > query.End) {
+				continue
+			}
+			if len(query.Statuses) > 0 && !slices.Contains(query.Statuses, request.Status.Status) {
+				continue
+			}
+			if len(query.TypeIDs) > 0 && !slices.Contains(query.TypeIDs, request.Type.ID) {
+				continue
+			}
+			if query.Action == bamboohr.TimeOffActionApprove && !request.Actions.Approve {
+				continue
+			}
+			requests = append(requests, request)
+		}
+	}
+	return requests, nil
+}
+
+func (f *fakeBambooHR) GetTimeOffBalance(employeeID int) ([]bamboohr.TimeOffBalance, error) {
+	if err := f.record("GetTimeOffBalance"); err != nil {
+		return nil, err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.balances[employeeID], nil
+}
+
+func (f *fakeBambooHR) CreateTimeOffRequest(employeeID int, request bamboohr.TimeOffRequestCreate) (*bamboohr.TimeOffRequest, error) {
+	if err := f.record("CreateTimeOffRequest"); err != nil {
+		return nil, err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.created = append(f.created, request)
+
+	// BambooHR marks the request a new one supersedes
+	if request.PreviousRequest != 0 {
+		for i, previous := range f.requests[employeeID] {
+			if previous.ID == strconv.Itoa(request.PreviousRequest) {
+				f.requests[employeeID][i].Status.Status = "superceded"
+				f.requests[employeeID][i].Actions.Edit = false
+			}
+		}
+	}
+
+	created := bamboohr.TimeOffRequest{
+		ID:         strconv.Itoa(len(f.created)),
+		EmployeeID: strconv.Itoa(employeeID),
+		Start:      request.Start,
+		End:        request.End,
+	}
+	created.Type.ID = strconv.Itoa(request.TimeOffTypeID)
+	created.Amount.Unit = "days"
+	for _, timeOffType := range f.types.TimeOffTypes {
+		if timeOffType.ID == created.Type.ID && timeOffType.Units != "" {
+			created.Amount.Unit = timeOffType.Units
+		}
+	}
+	created.Amount.Amount = bamboohr.FlexibleFloat(request.Amount)
+	created.Status.Status = request.Status
+	f.requests[employeeID] = append(f.requests[employeeID], created)
+
+	return &created, nil
+}
+
+func (f *fakeBambooHR) GetEmployeeDirectory() (*bamboohr.EmployeeDirectory, error) {
+	if err := f.record("GetEmployeeDirectory"); err != nil {
+		return nil, err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return &bamboohr.EmployeeDirectory{Employees: append([]bamboohr.Employee(nil), f.employees...)}, nil
+}
+
+func (f *fakeBambooHR) GetEmployee(employeeID int) (*bamboohr.Employee, error) {
+	if err := f.record("GetEmployee"); err != nil {
+		return nil, err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, employee := range f.employees {
+		if employee.ID == strconv.Itoa(employeeID) {
+			return &employee, nil
+		}
+	}
+	return nil, &bamboohr.APIError{StatusCode: 404, Body: "Not Found"}
+}
+
+func (f *fakeBambooHR) GetChangedEmployees(since time.Time) (*bamboohr.EmployeeChanges, error) {
+	if err := f.record("GetChangedEmployees"); err != nil {
+		return nil, err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	changes := &bamboohr.EmployeeChanges{Employees: make(map[string]bamboohr.EmployeeChange)}
+	for _, change := range f.changes {
+		changed, _ := time.Parse(time.RFC3339, change.LastChanged)
+		if changed.Before(since) {
+			continue
+		}
+		changes.Employees[change.ID] = change
+		if change.LastChanged > changes.Latest {
+			changes.Latest = change.LastChanged
+		}
+	}
+	return changes, nil
+}
+
+func (f *fakeBambooHR) GetTimeOffTypes() (*bamboohr.TimeOffTypes, error) {
+	if err := f.record("GetTimeOffTypes"); err != nil {
+		return nil, err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	types := f.types
+	return &types, nil
+}
+
+func (f *fakeBambooHR) GetTimeOffPolicies() ([]bamboohr.TimeOffPolicy, error) {
+	if err := f.record("GetTimeOffPolicies"); err != nil {
+		return nil, err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.policies, nil
+}
+
+func (f *fakeBambooHR) GetEmployeeTimeOffPolicies(employeeID int) ([]bamboohr.EmployeeTimeOffPolicy, error) {
+	if err := f.record("GetEmployeeTimeOffPolicies"); err != nil {
+		return nil, err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.assigned[employeeID], nil
+}
+
+func (f *fakeBambooHR) GetWhosOut(start, end string) ([]bamboohr.WhosOutEntry, error) {
+	if err := f.record("GetWhosOut"); err != nil {
+		return nil, err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.whosOut, nil
+}
+
+// errNotReachable is returned by the fake to simulate BambooHR being unreachable
+var errNotReachable = fmt.Errorf("making request: %w", &url.Error{Op: "Get", URL: "https://testcompany.bamboohr.com", Err: errors.New("dial tcp: connection refused")})
@@ -0,0 +1,330 @@
+package mcpserver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+
+	"bamboohr-mcp-server/bamboohr"
+	"bamboohr-mcp-server/store"
+)
+
+// historyGroupings lists the supported groupBy values of query_time_off_history
+var historyGroupings = []string{"type", "employee", "department", "month"}
+
+// TimeOffHistoryGroup is the time off taken by one group in the period
+type TimeOffHistoryGroup struct {
+	Key      string  `json:"key"`
+	Requests int     `json:"requests"`
+	Days     float64 `json:"days"`
+	Hours    float64 `json:"hours"`
+}
+
+// TimeOffHistoryResult is the structured result of the query_time_off_history tool
+type TimeOffHistoryResult struct {
+	Start       string                `json:"start"`
+	End         string                `json:"end"`
+	Department  string                `json:"department,omitempty"`
+	TimeOffType string                `json:"timeOffType,omitempty"`
+	Statuses    []string              `json:"statuses"`
+	GroupBy     string                `json:"groupBy"`
+	SyncedAt    string                `json:"syncedAt"`
+	Groups      []TimeOffHistoryGroup `json:"groups"`
+}
+
+// TimeOffHistory is the local copy of BambooHR that the history tools query. *store.Store
+// implements it, and NewHistoryPolicyEnforcer and NewHistoryAuditLog decorate it like the client.
+type TimeOffHistory interface {
+	SyncedAt() (time.Time, error)
+	EmployeeDirectory() (*bamboohr.EmployeeDirectory, error)
+	SearchTimeOffRequests(filter store.RequestFilter) ([]bamboohr.TimeOffRequest, error)
+}
+
+// RegisterStoreTools adds the tools that query the local store to the server
+func RegisterStoreTools(s *server.MCPServer, history TimeOffHistory, dates *DateParser) {
+	queryTimeOffHistoryTool := mcp.NewTool(
+		"query_time_off_history",
+		mcp.WithDescription("Summarize time off taken from the local store, e.g. how many sick days a department took last year. Answers without calling BambooHR, as of the last sync."),
+		mcp.WithOutputSchema[TimeOffHistoryResult](),
+		mcp.WithTitleAnnotation("Query Time-Off History"),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithIdempotentHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(false),
+		mcp.WithString("start",
+			mcp.Description("Start of the period (YYYY-MM-DD or an expression like 'last year' or 'Q1 2024'). A period may be given without an end. Optional, defaults to the current year."),
+		),
+		mcp.WithString("end",
+			mcp.Description("End of the period (YYYY-MM-DD or an expression). Optional."),
+		),
+		mcp.WithString("department",
+			mcp.Description("Only include employees currently in this department. Optional."),
+		),
+		mcp.WithString("employeeIds",
+			mcp.Description("Comma-separated employee IDs to include. Optional."),
+		),
+		mcp.WithString("timeOffType",
+			mcp.Description("Time off type ID or name, e.g. 'Sick'. Optional."),
+		),
+		mcp.WithString("status",
+			mcp.Description("Comma-separated request statuses to include. Optional, defaults to 'approved'."),
+		),
+		mcp.WithString("groupBy",
+			mcp.Description("Group the totals by 'type' (default), 'employee', 'department' or 'month'"),
+			mcp.Enum(historyGroupings...),
+		),
+		mcp.WithString("format",
+			mcp.Description("Response format: 'compact' (default) for a short line per item, 'full' for the complete JSON and structured content, 'markdown' for a table or 'csv'"),
+			mcp.Enum(formats...),
+		),
+	)
+
+	s.AddTool(queryTimeOffHistoryTool, handleQueryTimeOffHistory(history, dates))
+}
+
+func handleQueryTimeOffHistory(history TimeOffHistory, dates *DateParser) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		format, err := toolFormat(request, FormatCompact)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		groupBy := request.GetString("groupBy", "type")
+		if !slices.Contains(historyGroupings, groupBy) {
+			return mcp.NewToolResultError(fmt.Sprintf("unsupported groupBy %q: expected one of %s", groupBy, strings.Join(historyGroupings, ", "))), nil
+		}
+
+		employeeIDs, err := ParseEmployeeIDs(request.GetString("employeeIds", ""))
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("employeeIds: %s", err.Error())), nil
+		}
+
+		parser := dates.ForEmployees(employeeIDs)
+		period, err := parser.ResolveRange(request.GetString("start", ""), request.GetString("end", ""), parser.CurrentYear())
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		statuses := splitList(request.GetString("status", "approved"))
+
+		syncedAt, err := history.SyncedAt()
+		if errors.Is(err, store.ErrNotFound) {
+			return mcp.NewToolResultError("The local store has not been synced yet. Run 'bamboohr-mcp-server sync' first."), nil
+		}
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Failed to read the local store: %s", err.Error())), nil
+		}
+
+		filter := store.RequestFilter{
+			Start:       period.StartYMD(),
+			End:         period.EndYMD(),
+			EmployeeIDs: employeeIDs,
+			Department:  request.GetString("department", ""),
+			Statuses:    statuses,
+			TimeOffType: request.GetString("timeOffType", ""),
+		}
+
+		requests, err := history.SearchTimeOffRequests(filter)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Failed to query the local store: %s", err.Error())), nil
+		}
+
+		var departments map[string]string
+		if groupBy == "department" {
+			directory, err := history.EmployeeDirectory()
+			if err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("Failed to read the local store: %s", err.Error())), nil
+			}
+			departments = make(map[string]string, len(directory.Employees))
+			for _, employee := range directory.Employees {
+				departments[employee.ID] = employee.Department
+			}
+		}
+
+		result := TimeOffHistoryResult{
+			Start:       filter.Start,
+			End:         filter.End,
+			Department:  filter.Department,
+			TimeOffType: filter.TimeOffType,
+			Statuses:    statuses,
+			GroupBy:     groupBy,
+			SyncedAt:    syncedAt.UTC().Format(time.RFC3339),
+			Groups:      summarizeTimeOffHistory(requests, period, groupBy, departments),
+		}
+
+		return renderToolResult(result, timeOffHistoryView(result, dates.Locale()), format), nil
+	}
+}
+
+// summarizeTimeOffHistory totals the amount of each request that falls within the period by group
+func summarizeTimeOffHistory(requests []bamboohr.TimeOffRequest, period DateRange, groupBy string, departments map[string]string) []TimeOffHistoryGroup {
+	key := func(request bamboohr.TimeOffRequest, day string) string {
+		return usageDimensionValue(groupBy, request, day, departments)
+	}
+
+	result := []TimeOffHistoryGroup{}
+	for _, total := range tallyTimeOff(requests, period, key, bookedAmount) {
+		result = append(result, TimeOffHistoryGroup{
+			Key:      total.key,
+			Requests: len(total.requests),
+			Days:     roundAmount(total.days),
+			Hours:    roundAmount(total.hours),
+		})
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if groupBy == "month" {
+			return result[i].Key < result[j].Key
+		}
+		if result[i].Days+result[i].Hours != result[j].Days+result[j].Hours {
+			return result[i].Days+result[i].Hours > result[j].Days+result[j].Hours
+		}
+		return result[i].Key < result[j].Key
+	})
+
+	return result
+}
+
+// timeOffTotal is the time off of one group, totalled by tallyTimeOff
+type timeOffTotal struct {
+	key       string
+	requests  map[string]bool
+	employees map[string]bool
+	days      float64
+	hours     float64
+}
+
+// tallyTimeOff totals the amount of each request that falls within the period by the key of
+// the request and day, with split dividing each day's amount into days and hours. The totals
+// are unrounded and in the order their keys were first seen.
+func tallyTimeOff(requests []bamboohr.TimeOffRequest, period DateRange, key func(request bamboohr.TimeOffRequest, day string) string, split func(request bamboohr.TimeOffRequest, day string, amount float64) (float64, float64)) []*timeOffTotal {
+	totals := make(map[string]*timeOffTotal)
+	var order []*timeOffTotal
+
+	for _, request := range requests {
+		for day, amount := range requestDailyAmounts(request) {
+			if day < period.StartYMD() || day > period.EndYMD() {
+				continue
+			}
+
+			k := key(request, day)
+			total, ok := totals[k]
+			if !ok {
+				total = &timeOffTotal{key: k, requests: make(map[string]bool), employees: make(map[string]bool)}
+				totals[k] = total
+				order = append(order, total)
+			}
+
+			days, hours := split(request, day, amount)
+			total.requests[request.ID] = true
+			total.employees[request.EmployeeID] = true
+			total.days += days
+			total.hours += hours
+		}
+	}
+
+	return order
+}
+
+// bookedAmount splits an amount of time off in the unit the request was booked in, as hours
+// for requests in hours and days otherwise
+func bookedAmount(request bamboohr.TimeOffRequest, _ string, amount float64) (float64, float64) {
+	if isHours(request.Amount.Unit) {
+		return 0, amount
+	}
+	return amount, 0
+}
+
+// requestDailyAmounts returns the amount of the request taken on each day (YYYY-MM-DD). The
+// request's per-day breakdown is used when present, otherwise the amount is spread evenly over
+// its calendar days.
+func requestDailyAmounts(request bamboohr.TimeOffRequest) map[string]float64 {
+	amounts := make(map[string]float64)
+
+	if len(request.Dates) > 0 {
+		for day, value := range request.Dates {
+			if amount, err := strconv.ParseFloat(value, 64); err == nil {
+				amounts[day] = amount
+			}
+		}
+		return amounts
+	}
+
+	start, err := time.Parse(dateLayout, request.Start)
+	if err != nil {
+		return amounts
+	}
+	end, err := time.Parse(dateLayout, request.End)
+	if err != nil || end.Before(start) {
+		end = start
+	}
+
+	days := DateRange{Start: start, End: end}.Days()
+	for day := start; !day.After(end); day = day.AddDate(0, 0, 1) {
+		amounts[day.Format(dateLayout)] = float64(request.Amount.Amount) / float64(days)
+	}
+
+	return amounts
+}
+
+// timeOffHistoryView shows the time-off history totals as text
+func timeOffHistoryView(result TimeOffHistoryResult, locale Locale) textView {
+	title := fmt.Sprintf("Time off from %s to %s by %s", locale.FormatDate(result.Start), locale.FormatDate(result.End), result.GroupBy)
+	if filters := joinNonEmpty(", ", result.Department, result.TimeOffType); filters != "" {
+		title += " (" + filters + ")"
+	}
+	title += ", as of the sync at " + result.SyncedAt
+
+	view := textView{
+		Title:   title,
+		Columns: []string{strings.ToUpper(result.GroupBy[:1]) + result.GroupBy[1:], "Requests", "Days", "Hours"},
+	}
+
+	for _, group := range result.Groups {
+		var amounts []string
+		if group.Days != 0 {
+			amounts = append(amounts, formatAmount(group.Days, "days"))
+		}
+		if group.Hours != 0 {
+			amounts = append(amounts, formatAmount(group.Hours, "hours"))
+		}
+		if len(amounts) == 0 {
+			amounts = append(amounts, formatAmount(0, "days"))
+		}
+
+		view.Items = append(view.Items, fmt.Sprintf("%s: %s in %s", group.Key,
+			strings.Join(amounts, " and "), pluralize(group.Requests, "request", "requests")))
+		view.Rows = append(view.Rows, []string{
+			group.Key,
+			strconv.Itoa(group.Requests),
+			strconv.FormatFloat(group.Days, 'f', -1, 64),
+			strconv.FormatFloat(group.Hours, 'f', -1, 64),
+		})
+	}
+
+	return view
+}
+
+// roundAmount rounds an amount to two decimal places
+func roundAmount(amount float64) float64 {
+	return math.Round(amount*100) / 100
+}
+
+// splitList splits a comma-separated list, dropping empty entries
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
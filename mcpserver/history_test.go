@@ -0,0 +1,191 @@
+package mcpserver
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/server"
+
+	"bamboohr-mcp-server/bamboohr"
+	"bamboohr-mcp-server/store"
+)
+
+// historyTestRequest creates an approved or denied time-off request
+func historyTestRequest(id, employeeID, name, typeName, status, start, end string, amount float64, dates map[string]string) bamboohr.TimeOffRequest {
+	request := bamboohr.TimeOffRequest{ID: id, EmployeeID: employeeID, Name: name, Start: start, End: end, Dates: dates}
+	request.Type.Name = typeName
+	request.Status.Status = status
+	request.Amount.Unit = "days"
+	request.Amount.Amount = bamboohr.FlexibleFloat(amount)
+	return request
+}
+
+// newHistoryTestStore creates a store synced from a fake with requests in 2024
+func newHistoryTestStore(t *testing.T) (*store.Store, *fakeBambooHR) {
+	t.Helper()
+
+	fake := newFakeBambooHR(
+		bamboohr.Employee{ID: "4", DisplayName: "Charlotte Abbott", Department: "Engineering"},
+		bamboohr.Employee{ID: "5", DisplayName: "Ashley Adams", Department: "Sales"},
+	)
+	fake.requests[4] = []bamboohr.TimeOffRequest{
+		historyTestRequest("1", "4", "Charlotte Abbott", "Sick", "approved", "2024-03-04", "2024-03-05", 2, nil),
+		historyTestRequest("2", "4", "Charlotte Abbott", "Vacation", "approved", "2024-12-30", "2025-01-02", 3,
+			map[string]string{"2024-12-30": "1", "2024-12-31": "1", "2025-01-01": "0", "2025-01-02": "1"}),
+		historyTestRequest("3", "4", "Charlotte Abbott", "Sick", "denied", "2024-10-01", "2024-10-01", 1, nil),
+	}
+	fake.requests[5] = []bamboohr.TimeOffRequest{
+		historyTestRequest("4", "5", "Ashley Adams", "Sick", "approved", "2024-11-20", "2024-11-20", 1, nil),
+	}
+
+	st, err := store.Open(filepath.Join(t.TempDir(), "bamboohr.db"))
+	if err != nil {
+		t.Fatalf("Failed to open store: %v", err)
+	}
+	t.Cleanup(func() { st.Close() })
+
+	if _, err := st.Sync(fake, "2024-01-01", "2025-12-31", time.Date(2025, 1, 2, 3, 0, 0, 0, time.UTC)); err != nil {
+		t.Fatalf("Failed to sync store: %v", err)
+	}
+
+	return st, fake
+}
+
+func TestQueryTimeOffHistory(t *testing.T) {
+	st, fake := newHistoryTestStore(t)
+
+	s := New(fake, newTestDateParser())
+	RegisterStoreTools(s, st, newTestDateParser())
+
+	query := func(arguments map[string]string) TimeOffHistoryResult {
+		arguments["format"] = "full"
+		var result struct {
+			StructuredContent TimeOffHistoryResult `json:"structuredContent"`
+			IsError           bool                 `json:"isError"`
+		}
+		response := sendMessage(t, s, "tools/call", map[string]interface{}{
+			"name":      "query_time_off_history",
+			"arguments": arguments,
+		})
+		if err := json.Unmarshal(response, &result); err != nil {
+			t.Fatalf("Failed to unmarshal result: %v", err)
+		}
+		if result.IsError {
+			t.Fatalf("Unexpected tool error for %v: %s", arguments, response)
+		}
+		return result.StructuredContent
+	}
+
+	// How many sick days did engineering take in 2024?
+	result := query(map[string]string{"start": "2024", "department": "Engineering", "timeOffType": "Sick"})
+	if len(result.Groups) != 1 || result.Groups[0] != (TimeOffHistoryGroup{Key: "Sick", Requests: 1, Days: 2}) {
+		t.Errorf("Expected 2 sick days in 1 request, excluding denied requests, got %+v", result.Groups)
+	}
+	if result.SyncedAt != "2025-01-02T03:00:00Z" {
+		t.Errorf("Expected the sync time, got %s", result.SyncedAt)
+	}
+
+	// Only the days of request 2 that fall in 2024 are counted
+	result = query(map[string]string{"start": "2024", "groupBy": "department"})
+	expected := []TimeOffHistoryGroup{
+		{Key: "Engineering", Requests: 2, Days: 4},
+		{Key: "Sales", Requests: 1, Days: 1},
+	}
+	if len(result.Groups) != len(expected) || result.Groups[0] != expected[0] || result.Groups[1] != expected[1] {
+		t.Errorf("Expected %+v, got %+v", expected, result.Groups)
+	}
+
+	result = query(map[string]string{"start": "2024-12-01", "end": "2025-01-31", "groupBy": "month"})
+	expected = []TimeOffHistoryGroup{
+		{Key: "2024-12", Requests: 1, Days: 2},
+		{Key: "2025-01", Requests: 1, Days: 1},
+	}
+	if len(result.Groups) != len(expected) || result.Groups[0] != expected[0] || result.Groups[1] != expected[1] {
+		t.Errorf("Expected %+v, got %+v", expected, result.Groups)
+	}
+
+	text, _ := callTool(t, s, "query_time_off_history", map[string]string{"start": "2024", "groupBy": "employee", "status": "denied"})
+	if text != "Time off from 2024-01-01 to 2024-12-31 by employee, as of the sync at 2025-01-02T03:00:00Z:\n- Charlotte Abbott (ID 4): 1 day in 1 request" {
+		t.Errorf("Unexpected text: %q", text)
+	}
+
+	if _, isError := callTool(t, s, "query_time_off_history", map[string]string{"groupBy": "week"}); !isError {
+		t.Error("Expected tool error for an unsupported groupBy")
+	}
+}
+
+func TestQueryTimeOffHistory_PolicyAndAudit(t *testing.T) {
+	st, fake := newHistoryTestStore(t)
+
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	history := NewHistoryAuditLog(NewHistoryPolicyEnforcer(st, Policy{EmployeeIDs: []int{4}}), logger)
+
+	s := New(fake, newTestDateParser())
+	RegisterStoreTools(s, history, newTestDateParser())
+
+	text, isError := callTool(t, s, "query_time_off_history", map[string]string{"start": "2024", "groupBy": "department"})
+	if isError {
+		t.Fatalf("Unexpected tool error: %s", text)
+	}
+	if strings.Contains(text, "Sales") || !strings.Contains(text, "Engineering: 4 days in 2 requests") {
+		t.Errorf("Expected only the allowed employee's time off, got %q", text)
+	}
+
+	if text, isError := callTool(t, s, "query_time_off_history", map[string]string{"start": "2024", "employeeIds": "5"}); !isError || !strings.Contains(text, "denied by policy") {
+		t.Errorf("Expected the other employee to be refused, got %q", text)
+	}
+
+	for _, expected := range []string{
+		"level=DEBUG msg=\"bamboohr call\" operation=store.SearchTimeOffRequests write=false",
+		"operation=store.EmployeeDirectory",
+		"level=WARN msg=\"bamboohr call\" operation=store.SearchTimeOffRequests",
+	} {
+		if !strings.Contains(buf.String(), expected) {
+			t.Errorf("Expected %q in the audit log, got %q", expected, buf.String())
+		}
+	}
+}
+
+func TestQueryTimeOffHistory_NotSynced(t *testing.T) {
+	st, err := store.Open(filepath.Join(t.TempDir(), "bamboohr.db"))
+	if err != nil {
+		t.Fatalf("Failed to open store: %v", err)
+	}
+	defer st.Close()
+
+	s := server.NewMCPServer("test", Version, server.WithToolCapabilities(true))
+	RegisterStoreTools(s, st, newTestDateParser())
+
+	if text, isError := callTool(t, s, "query_time_off_history", map[string]string{}); !isError {
+		t.Errorf("Expected tool error before the first sync, got %q", text)
+	}
+}
+
+func TestSummarizeTimeOffHistory_Hours(t *testing.T) {
+	hours := historyTestRequest("1", "4", "Charlotte Abbott", "Sick", "approved", "2024-03-04", "2024-03-04", 4, nil)
+	hours.Amount.Unit = "Hours"
+	days := historyTestRequest("2", "4", "Charlotte Abbott", "Sick", "approved", "2024-03-05", "2024-03-05", 1, nil)
+
+	groups := summarizeTimeOffHistory([]bamboohr.TimeOffRequest{hours, days}, yearRange(2024, time.UTC), "type", nil)
+	if len(groups) != 1 || groups[0] != (TimeOffHistoryGroup{Key: "Sick", Requests: 2, Days: 1, Hours: 4}) {
+		t.Errorf("Expected 1 day and 4 hours of sick leave, got %+v", groups)
+	}
+}
+
+func TestRequestDailyAmounts(t *testing.T) {
+	spread := requestDailyAmounts(historyTestRequest("1", "4", "", "Sick", "approved", "2024-03-04", "2024-03-07", 2, nil))
+	if len(spread) != 4 || spread["2024-03-04"] != 0.5 || spread["2024-03-07"] != 0.5 {
+		t.Errorf("Expected 0.5 days on each of 4 days, got %v", spread)
+	}
+
+	breakdown := requestDailyAmounts(historyTestRequest("2", "4", "", "Sick", "approved", "2024-03-04", "2024-03-05", 1.5, map[string]string{"2024-03-04": "1", "2024-03-05": "0.5"}))
+	if len(breakdown) != 2 || breakdown["2024-03-05"] != 0.5 {
+		t.Errorf("Expected the per-day breakdown, got %v", breakdown)
+	}
+}
@@ -0,0 +1,94 @@
+package mcpserver
+
+import (
+	"crypto/subtle"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// NewHTTPHandler serves the MCP server over streamable HTTP at /mcp. If a token is configured,
+// /mcp requires it as a bearer token in the Authorization header. If a calendar token is
+// configured, iCalendar feeds of time off are also served at /calendar.ics for calendar
+// subscriptions, e.g. /calendar.ics?department=Engineering&token=...
+func NewHTTPHandler(s *server.MCPServer, client BambooHR, dates *DateParser, token, calendarToken string) http.Handler {
+	var mcpHandler http.Handler = server.NewStreamableHTTPServer(s)
+	if token != "" {
+		mcpHandler = requireBearerToken(mcpHandler, token)
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/mcp", mcpHandler)
+
+	if calendarToken != "" {
+		mux.Handle("/calendar.ics", handleCalendarFeed(client, dates, calendarToken))
+	}
+
+	return mux
+}
+
+// requireBearerToken refuses requests without the token in an "Authorization: Bearer" header
+func requireBearerToken(next http.Handler, token string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		bearer, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok || subtle.ConstantTimeCompare([]byte(strings.TrimSpace(bearer)), []byte(token)) != 1 {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="bamboohr-mcp-server"`)
+			http.Error(w, "invalid token", http.StatusUnauthorized)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// handleCalendarFeed serves the time off of employees, a department or a manager's reports as an
+// iCalendar feed
+func handleCalendarFeed(client BambooHR, dates *DateParser, token string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		query := r.URL.Query()
+		if subtle.ConstantTimeCompare([]byte(query.Get("token")), []byte(token)) != 1 {
+			http.Error(w, "invalid token", http.StatusUnauthorized)
+			return
+		}
+
+		var managerID int
+		if value := query.Get("managerId"); value != "" {
+			id, err := strconv.Atoi(value)
+			if err != nil {
+				http.Error(w, "managerId must be a valid integer", http.StatusBadRequest)
+				return
+			}
+			managerID = id
+		}
+
+		employeeIDs, err := teamEmployeeIDs(client, query.Get("employeeIds"), query.Get("department"), managerID)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		parser := dates.ForEmployees(employeeIDs)
+		period, err := parser.ResolveRange(query.Get("start"), query.Get("end"), parser.CurrentYear())
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		result, err := exportTimeOffCalendar(client, employeeIDs, period)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("failed to export time off: %s", err.Error()), http.StatusBadGateway)
+			return
+		}
+
+		w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+		w.Header().Set("Content-Disposition", `inline; filename="time-off.ics"`)
+		w.Write([]byte(result.Calendar))
+	})
+}
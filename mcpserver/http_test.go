@@ -0,0 +1,126 @@
+package mcpserver
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"bamboohr-mcp-server/bamboohr"
+)
+
+func TestHTTPHandler_CalendarFeed(t *testing.T) {
+	_, client := newMockBambooHR(t, map[string]string{
+		"/time_off/requests": `[{"id": "1234", "name": "John Doe", "start": "2025-12-23", "end": "2025-12-24",
+			"type": {"name": "Vacation"}, "status": {"status": "approved"}}]`,
+	})
+	s := New(client, newTestDateParser())
+
+	ts := httptest.NewServer(NewHTTPHandler(s, client, newTestDateParser(), "", "secret"))
+	defer ts.Close()
+
+	tests := []struct {
+		name           string
+		query          string
+		expectedStatus int
+	}{
+		{"Valid token", "?employeeIds=157&token=secret", http.StatusOK},
+		{"Missing token", "?employeeIds=157", http.StatusUnauthorized},
+		{"Wrong token", "?employeeIds=157&token=guess", http.StatusUnauthorized},
+		{"Missing employees", "?token=secret", http.StatusBadRequest},
+		{"Invalid date", "?employeeIds=157&start=someday&token=secret", http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := http.Get(ts.URL + "/calendar.ics" + tt.query)
+			if err != nil {
+				t.Fatalf("Request failed: %v", err)
+			}
+			defer resp.Body.Close()
+
+			if resp.StatusCode != tt.expectedStatus {
+				t.Errorf("Expected status %d, got %d", tt.expectedStatus, resp.StatusCode)
+			}
+
+			if tt.expectedStatus != http.StatusOK {
+				return
+			}
+
+			if contentType := resp.Header.Get("Content-Type"); !strings.HasPrefix(contentType, "text/calendar") {
+				t.Errorf("Expected text/calendar, got %s", contentType)
+			}
+
+			body, _ := io.ReadAll(resp.Body)
+			if !strings.Contains(string(body), "UID:time-off-1234@testcompany.bamboohr.com") {
+				t.Errorf("Expected calendar with the request, got:\n%s", body)
+			}
+		})
+	}
+}
+
+func TestHTTPHandler_CalendarFeedDisabled(t *testing.T) {
+	client := bamboohr.NewClient("testcompany", "testkey")
+	s := New(client, newTestDateParser())
+
+	ts := httptest.NewServer(NewHTTPHandler(s, client, newTestDateParser(), "", ""))
+	defer ts.Close()
+
+	resp, err := http.Get(ts.URL + "/calendar.ics?employeeIds=157&token=")
+	if err != nil {
+		t.Fatalf("Request failed: %v", err)
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusNotFound {
+		t.Errorf("Expected the feed to be disabled without a token, got status %d", resp.StatusCode)
+	}
+}
+
+func TestHTTPHandler_MCPToken(t *testing.T) {
+	client := bamboohr.NewClient("testcompany", "testkey")
+	s := New(client, newTestDateParser())
+
+	ts := httptest.NewServer(NewHTTPHandler(s, client, newTestDateParser(), "mcp-secret", "calendar-secret"))
+	defer ts.Close()
+
+	initialize := `{"jsonrpc": "2.0", "id": 1, "method": "initialize", "params": {"protocolVersion": "2025-03-26",
+		"capabilities": {}, "clientInfo": {"name": "test", "version": "1.0"}}}`
+
+	tests := []struct {
+		name           string
+		authorization  string
+		expectedStatus int
+	}{
+		{"Valid token", "Bearer mcp-secret", http.StatusOK},
+		{"Missing token", "", http.StatusUnauthorized},
+		{"Wrong token", "Bearer guess", http.StatusUnauthorized},
+		{"Calendar token", "Bearer calendar-secret", http.StatusUnauthorized},
+		{"Not a bearer token", "Basic mcp-secret", http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := http.NewRequest(http.MethodPost, ts.URL+"/mcp", strings.NewReader(initialize))
+			if err != nil {
+				t.Fatal(err)
+			}
+			req.Header.Set("Content-Type", "application/json")
+			req.Header.Set("Accept", "application/json, text/event-stream")
+			if tt.authorization != "" {
+				req.Header.Set("Authorization", tt.authorization)
+			}
+
+			resp, err := http.DefaultClient.Do(req)
+			if err != nil {
+				t.Fatalf("Request failed: %v", err)
+			}
+			resp.Body.Close()
+
+			if resp.StatusCode != tt.expectedStatus {
+				t.Errorf("Expected status %d, got %d", tt.expectedStatus, resp.StatusCode)
+			}
+		})
+	}
+}
@@ -0,0 +1,302 @@
+package mcpserver
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+
+	"bamboohr-mcp-server/bamboohr"
+)
+
+// icalProductID identifies this server as the producer of exported calendars
+const icalProductID = "-//bamboohr-mcp-server//Time Off//EN"
+
+// icalMaxLineOctets is the longest content line allowed by RFC 5545 before it must be folded
+const icalMaxLineOctets = 75
+
+// icalStatuses maps BambooHR request statuses to iCalendar event statuses. Denied and cancelled
+// requests are exported as cancelled events so calendars that imported them remove them.
+var icalStatuses = map[string]string{
+	"approved":   "CONFIRMED",
+	"requested":  "TENTATIVE",
+	"denied":     "CANCELLED",
+	"canceled":   "CANCELLED",
+	"cancelled":  "CANCELLED",
+	"superceded": "CANCELLED",
+}
+
+// icalSequenceEpoch is the day SEQUENCE numbers are counted from
+var icalSequenceEpoch = time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// icalStatusRanks orders request statuses by how late they come in a request's life, so that
+// status changes on the same day still raise an event's SEQUENCE. A new request is requested,
+// the lowest rank, as are unknown statuses; approving or denying it on the day it was made still
+// raises the SEQUENCE.
+var icalStatusRanks = map[string]int{
+	"requested":  0,
+	"approved":   1,
+	"denied":     2,
+	"canceled":   2,
+	"cancelled":  2,
+	"superceded": 3,
+}
+
+// TimeOffCalendarResult is the structured output of export_time_off_ical
+type TimeOffCalendarResult struct {
+	EmployeeIDs []string `json:"employeeIds" jsonschema:"description=The employees whose time off was exported"`
+	Start       string   `json:"start" jsonschema:"description=Start of the exported period (YYYY-MM-DD)"`
+	End         string   `json:"end" jsonschema:"description=End of the exported period (YYYY-MM-DD)"`
+	Events      int      `json:"events" jsonschema:"description=Number of events in the calendar"`
+	Calendar    string   `json:"calendar" jsonschema:"description=The iCalendar (RFC 5545) document"`
+}
+
+// renderICalendar renders time-off requests as an iCalendar (RFC 5545) document. Each request
+// becomes an all-day event whose UID is derived from the request ID, so importing an updated
+// calendar updates existing events instead of duplicating them. The number of events written is
+// returned with the document, as requests without valid dates are left out.
+func renderICalendar(name, company string, requests []bamboohr.TimeOffRequest, stamp time.Time) (string, int) {
+	var b strings.Builder
+	writeICalLine(&b, "BEGIN:VCALENDAR")
+	writeICalLine(&b, "VERSION:2.0")
+	writeICalLine(&b, "PRODID:"+icalProductID)
+	writeICalLine(&b, "CALSCALE:GREGORIAN")
+	writeICalLine(&b, "METHOD:PUBLISH")
+	writeICalLine(&b, "X-WR-CALNAME:"+escapeICalText(name))
+
+	events := 0
+	for _, request := range requests {
+		if writeICalEvent(&b, company, request, stamp) {
+			events++
+		}
+	}
+
+	writeICalLine(&b, "END:VCALENDAR")
+	return b.String(), events
+}
+
+// icalSequence returns the SEQUENCE of a request's event, which grows with each change so that
+// calendars apply the change when the calendar is imported again: the days from
+// icalSequenceEpoch to the last change, with the status's rank to order changes on the same day
+func icalSequence(request bamboohr.TimeOffRequest) int {
+	lastChanged, err := time.Parse(dateLayout, request.Status.LastChanged)
+	if err != nil {
+		return 0
+	}
+	days := int(lastChanged.Sub(icalSequenceEpoch).Hours() / 24)
+	return days*4 + icalStatusRanks[strings.ToLower(request.Status.Status)]
+}
+
+// writeICalEvent writes a time-off request as an all-day VEVENT, and reports whether it was
+// written
+func writeICalEvent(b *strings.Builder, company string, request bamboohr.TimeOffRequest, stamp time.Time) bool {
+	start, err := time.Parse(dateLayout, request.Start)
+	if err != nil {
+		return false
+	}
+	end, err := time.Parse(dateLayout, request.End)
+	if err != nil || end.Before(start) {
+		end = start
+	}
+
+	summary := request.Type.Name
+	if request.Name != "" {
+		summary = request.Name + " - " + request.Type.Name
+	}
+
+	description := formatAmount(float64(request.Amount.Amount), request.Amount.Unit)
+	if request.Status.Status != "" {
+		description += " (" + request.Status.Status + ")"
+	}
+	for _, note := range request.Notes.Notes {
+		if note.Note != "" {
+			description += "\n" + note.Note
+		}
+	}
+
+	writeICalLine(b, "BEGIN:VEVENT")
+	writeICalLine(b, fmt.Sprintf("UID:time-off-%s@%s.bamboohr.com", request.ID, company))
+	writeICalLine(b, "DTSTAMP:"+stamp.UTC().Format("20060102T150405Z"))
+	writeICalLine(b, "SEQUENCE:"+strconv.Itoa(icalSequence(request)))
+	writeICalLine(b, "DTSTART;VALUE=DATE:"+start.Format("20060102"))
+	// DTEND is exclusive for all-day events
+	writeICalLine(b, "DTEND;VALUE=DATE:"+end.AddDate(0, 0, 1).Format("20060102"))
+	writeICalLine(b, "SUMMARY:"+escapeICalText(summary))
+	writeICalLine(b, "DESCRIPTION:"+escapeICalText(description))
+	if status, ok := icalStatuses[strings.ToLower(request.Status.Status)]; ok {
+		writeICalLine(b, "STATUS:"+status)
+	}
+	if lastChanged, err := time.Parse(dateLayout, request.Status.LastChanged); err == nil {
+		writeICalLine(b, "LAST-MODIFIED:"+lastChanged.Format("20060102T150405Z"))
+	}
+	writeICalLine(b, "TRANSP:TRANSPARENT")
+	writeICalLine(b, "END:VEVENT")
+	return true
+}
+
+// writeICalLine writes a content line terminated by CRLF, folding it at 75 octets
+// without splitting multi-byte characters
+func writeICalLine(b *strings.Builder, line string) {
+	limit := icalMaxLineOctets
+	for len(line) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(line[cut]) {
+			cut--
+		}
+		b.WriteString(line[:cut])
+		b.WriteString("\r\n ")
+		line = line[cut:]
+		// Continuation lines start with a space, which counts towards the limit
+		limit = icalMaxLineOctets - 1
+	}
+	b.WriteString(line)
+	b.WriteString("\r\n")
+}
+
+// escapeICalText escapes a TEXT property value
+func escapeICalText(value string) string {
+	return strings.NewReplacer(
+		`\`, `\\`,
+		";", `\;`,
+		",", `\,`,
+		"\r\n", `\n`,
+		"\n", `\n`,
+	).Replace(value)
+}
+
+// teamEmployeeIDs resolves a comma-separated list of employee IDs, the employees of a
+// department, or everyone reporting to a manager, to employee IDs
+func teamEmployeeIDs(client BambooHR, employeeIDs, department string, managerID int) ([]int, error) {
+	if employeeIDs != "" {
+		var ids []int
+		for _, value := range strings.Split(employeeIDs, ",") {
+			value = strings.TrimSpace(value)
+			if value == "" {
+				continue
+			}
+			id, err := strconv.Atoi(value)
+			if err != nil {
+				return nil, fmt.Errorf("employeeIds must be comma-separated integers, got %q", value)
+			}
+			ids = append(ids, id)
+		}
+		if len(ids) == 0 {
+			return nil, fmt.Errorf("employeeIds must list at least one employee")
+		}
+		return ids, nil
+	}
+
+	if department == "" && managerID == 0 {
+		return nil, fmt.Errorf("one of employeeIds, department or managerId is required")
+	}
+
+	directory, err := client.GetEmployeeDirectory()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get employee directory: %w", err)
+	}
+
+	if managerID != 0 {
+		ids := newOrgChart(directory).reportIDs(managerID)
+		if len(ids) == 0 {
+			return nil, fmt.Errorf("no employees report to employee %d", managerID)
+		}
+		return ids, nil
+	}
+
+	var ids []int
+	for _, employee := range directory.Employees {
+		if !strings.EqualFold(employee.Department, department) {
+			continue
+		}
+		if id, err := strconv.Atoi(employee.ID); err == nil {
+			ids = append(ids, id)
+		}
+	}
+	if len(ids) == 0 {
+		return nil, fmt.Errorf("no employees found in department %q", department)
+	}
+
+	return ids, nil
+}
+
+// toolManagerID returns the manager given by the optional managerId argument, or zero
+func toolManagerID(request mcp.CallToolRequest, me int) (int, error) {
+	value := request.GetString("managerId", "")
+	if value == "" {
+		return 0, nil
+	}
+
+	id, err := resolveEmployeeID(value, me)
+	if err != nil {
+		return 0, fmt.Errorf("managerId: %w", err)
+	}
+	return id, nil
+}
+
+// exportTimeOffCalendar fetches the time-off requests of the employees in the period and
+// renders them as an iCalendar document
+func exportTimeOffCalendar(client BambooHR, employeeIDs []int, period DateRange) (TimeOffCalendarResult, error) {
+	result := TimeOffCalendarResult{
+		Start: period.StartYMD(),
+		End:   period.EndYMD(),
+	}
+
+	requests, err := teamTimeOffRequests(client, employeeIDs, bamboohr.TimeOffRequestQuery{Start: result.Start, End: result.End})
+	if err != nil {
+		return result, err
+	}
+	for _, employeeID := range employeeIDs {
+		result.EmployeeIDs = append(result.EmployeeIDs, strconv.Itoa(employeeID))
+	}
+
+	result.Calendar, result.Events = renderICalendar("Time off", client.Company(), requests, time.Now())
+	return result, nil
+}
+
+func handleExportTimeOffICal(client BambooHR, dates *DateParser, me int) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		format, err := toolFormat(request, FormatCompact)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		managerID, err := toolManagerID(request, me)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		employeeIDs, err := teamEmployeeIDs(client, request.GetString("employeeIds", ""), request.GetString("department", ""), managerID)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		parser := dates.ForEmployees(employeeIDs)
+		period, err := parser.ResolveRange(request.GetString("start", ""), request.GetString("end", ""), parser.CurrentYear())
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		result, err := exportTimeOffCalendar(client, employeeIDs, period)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Failed to export time off: %s", err.Error())), nil
+		}
+
+		return renderToolResult(result, timeOffCalendarView(result), format), nil
+	}
+}
+
+// timeOffCalendarView shows the calendar itself as text, or in a table row for CSV
+func timeOffCalendarView(result TimeOffCalendarResult) textView {
+	return textView{
+		Title: fmt.Sprintf("%s for employees %s from %s to %s", pluralize(result.Events, "time-off event", "time-off events"),
+			strings.Join(result.EmployeeIDs, ", "), result.Start, result.End),
+		Body:    result.Calendar,
+		Columns: []string{"Employee IDs", "Start", "End", "Events", "Calendar"},
+		Rows:    [][]string{{strings.Join(result.EmployeeIDs, ","), result.Start, result.End, strconv.Itoa(result.Events), result.Calendar}},
+	}
+}
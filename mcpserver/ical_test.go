@@ -0,0 +1,204 @@
+package mcpserver
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"bamboohr-mcp-server/bamboohr"
+)
+
+// icalTestRequests returns an approved and a denied request for the iCalendar tests
+func icalTestRequests() []bamboohr.TimeOffRequest {
+	var approved bamboohr.TimeOffRequest
+	approved.ID = "1234"
+	approved.Name = "John Doe"
+	approved.Start = "2025-12-23"
+	approved.End = "2026-01-02"
+	approved.Type.Name = "Vacation"
+	approved.Amount.Unit = "days"
+	approved.Amount.Amount = 7
+	approved.Status.Status = "approved"
+	approved.Status.LastChanged = "2025-11-01"
+	approved.Notes.Notes = []bamboohr.Note{{From: "employee", Note: "Family trip; back Jan 5, probably"}}
+
+	var denied bamboohr.TimeOffRequest
+	denied.ID = "1235"
+	denied.Name = "John Doe"
+	denied.Start = "2025-10-10"
+	denied.End = "2025-10-10"
+	denied.Type.Name = "Vacation"
+	denied.Amount.Unit = "days"
+	denied.Amount.Amount = 1
+	denied.Status.Status = "denied"
+
+	return []bamboohr.TimeOffRequest{approved, denied}
+}
+
+func TestRenderICalendar(t *testing.T) {
+	stamp := time.Date(2025, 9, 3, 12, 0, 0, 0, time.UTC)
+	requests := append(icalTestRequests(), bamboohr.TimeOffRequest{ID: "1236", Start: "someday"})
+	calendar, events := renderICalendar("Time off", "acme", requests, stamp)
+
+	for _, expected := range []string{
+		"BEGIN:VCALENDAR\r\nVERSION:2.0\r\n",
+		"UID:time-off-1234@acme.bamboohr.com\r\n",
+		"DTSTAMP:20250903T120000Z\r\n",
+		"DTSTART;VALUE=DATE:20251223\r\n",
+		"DTEND;VALUE=DATE:20260103\r\n",
+		"SUMMARY:John Doe - Vacation\r\n",
+		`DESCRIPTION:7 days (approved)\nFamily trip\; back Jan 5\, probably` + "\r\n",
+		"STATUS:CONFIRMED\r\n",
+		"LAST-MODIFIED:20251101T000000Z\r\n",
+		"SEQUENCE:37745\r\n",
+		"UID:time-off-1235@acme.bamboohr.com\r\n",
+		"DTEND;VALUE=DATE:20251011\r\n",
+		"STATUS:CANCELLED\r\n",
+		"END:VCALENDAR\r\n",
+	} {
+		if !strings.Contains(calendar, expected) {
+			t.Errorf("Expected calendar to contain %q, got:\n%s", expected, calendar)
+		}
+	}
+
+	if strings.Count(calendar, "BEGIN:VEVENT") != 2 || events != 2 {
+		t.Errorf("Expected 2 events without the request with invalid dates, got %d:\n%s", events, calendar)
+	}
+}
+
+func TestRenderICalendar_StableUIDs(t *testing.T) {
+	requests := icalTestRequests()
+	first, _ := renderICalendar("Time off", "acme", requests, time.Now())
+
+	requests[0].Status.Status = "requested"
+	second, _ := renderICalendar("Time off", "acme", requests, time.Now())
+
+	if !strings.Contains(second, "UID:time-off-1234@acme.bamboohr.com") || !strings.Contains(second, "STATUS:TENTATIVE") {
+		t.Errorf("Expected the same UID with an updated status, got:\n%s", second)
+	}
+
+	if !strings.Contains(first, "STATUS:CONFIRMED") {
+		t.Errorf("Expected the original status to be confirmed, got:\n%s", first)
+	}
+
+	// A later change, or a cancellation on the same day, raises the sequence
+	approved := icalTestRequests()[0]
+	cancelled := approved
+	cancelled.Status.Status = "canceled"
+	later := approved
+	later.Status.LastChanged = "2025-11-02"
+	if icalSequence(cancelled) <= icalSequence(approved) || icalSequence(later) <= icalSequence(cancelled) {
+		t.Errorf("Expected increasing sequences, got %d, %d and %d", icalSequence(approved), icalSequence(cancelled), icalSequence(later))
+	}
+}
+
+func TestWriteICalLine_Folding(t *testing.T) {
+	var b strings.Builder
+	line := "DESCRIPTION:" + strings.Repeat("é", 100)
+	writeICalLine(&b, line)
+
+	folded := strings.Split(strings.TrimSuffix(b.String(), "\r\n"), "\r\n")
+	if len(folded) < 3 {
+		t.Fatalf("Expected the line to be folded, got %q", b.String())
+	}
+
+	for i, part := range folded {
+		if len(part) > icalMaxLineOctets {
+			t.Errorf("Line %d is %d octets long", i, len(part))
+		}
+		if i > 0 && !strings.HasPrefix(part, " ") {
+			t.Errorf("Expected continuation line %d to start with a space", i)
+		}
+		if !utf8.ValidString(part) {
+			t.Errorf("Expected line %d to contain whole UTF-8 characters", i)
+		}
+	}
+
+	unfolded := strings.ReplaceAll(strings.TrimSuffix(b.String(), "\r\n"), "\r\n ", "")
+	if unfolded != line {
+		t.Errorf("Expected unfolding to restore the line, got %q", unfolded)
+	}
+}
+
+func TestTeamEmployeeIDs(t *testing.T) {
+	_, client := newMockBambooHR(t, map[string]string{"/employees/directory": completionDirectoryJSON})
+
+	ids, err := teamEmployeeIDs(client, "157, 158", "", 0)
+	if err != nil || len(ids) != 2 || ids[0] != 157 || ids[1] != 158 {
+		t.Errorf("Expected IDs 157 and 158, got %v (%v)", ids, err)
+	}
+
+	ids, err = teamEmployeeIDs(client, "", "engineering", 0)
+	if err != nil || len(ids) != 2 || ids[0] != 157 || ids[1] != 201 {
+		t.Errorf("Expected the Engineering department, got %v (%v)", ids, err)
+	}
+
+	for _, tt := range []struct{ ids, department string }{{"157,abc", ""}, {"", ""}, {"", "Marketing"}} {
+		if _, err := teamEmployeeIDs(client, tt.ids, tt.department, 0); err == nil {
+			t.Errorf("Expected error for ids %q and department %q, but got none", tt.ids, tt.department)
+		}
+	}
+}
+
+func TestExportTimeOffICalTool(t *testing.T) {
+	mock := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Query().Get("start") != "2025-12-01" || r.URL.Query().Get("end") != "2025-12-31" {
+			t.Errorf("Expected December 2025, got %s", r.URL.RawQuery)
+		}
+		w.Write([]byte(`[{"id": "1234", "employeeId": "` + r.URL.Query().Get("employeeId") + `", "name": "John Doe",
+			"start": "2025-12-23", "end": "2025-12-24", "type": {"name": "Vacation"}, "status": {"status": "approved"}}]`))
+	}))
+	defer mock.Close()
+
+	client := bamboohr.NewClient("acme", "testkey")
+	client.V1BaseURL = mock.URL
+	s := New(client, newTestDateParser())
+
+	var result struct {
+		StructuredContent TimeOffCalendarResult `json:"structuredContent"`
+	}
+	response := sendMessage(t, s, "tools/call", map[string]interface{}{
+		"name":      "export_time_off_ical",
+		"arguments": map[string]string{"employeeIds": "157,158", "start": "December", "format": "full"},
+	})
+	if err := json.Unmarshal(response, &result); err != nil {
+		t.Fatalf("Failed to unmarshal result: %v", err)
+	}
+
+	export := result.StructuredContent
+	if export.Events != 2 || strings.Join(export.EmployeeIDs, ",") != "157,158" {
+		t.Errorf("Expected 2 events for employees 157 and 158, got %+v", export)
+	}
+
+	if !strings.Contains(export.Calendar, "UID:time-off-1234@acme.bamboohr.com") {
+		t.Errorf("Expected calendar with request UIDs, got:\n%s", export.Calendar)
+	}
+
+	// The compact format is the calendar itself, without structured content
+	var compact struct {
+		Content           []mcp.TextContent      `json:"content"`
+		StructuredContent *TimeOffCalendarResult `json:"structuredContent"`
+	}
+	response = sendMessage(t, s, "tools/call", map[string]interface{}{
+		"name":      "export_time_off_ical",
+		"arguments": map[string]string{"employeeIds": "157", "start": "December"},
+	})
+	if err := json.Unmarshal(response, &compact); err != nil {
+		t.Fatalf("Failed to unmarshal result: %v", err)
+	}
+	if len(compact.Content) != 1 || !strings.HasPrefix(compact.Content[0].Text, "BEGIN:VCALENDAR\r\n") || compact.StructuredContent != nil {
+		t.Errorf("Expected only the calendar, got %s", response)
+	}
+
+	text, _ := callTool(t, s, "export_time_off_ical", map[string]string{"employeeIds": "157", "start": "December", "format": "markdown"})
+	if !strings.HasPrefix(text, "1 time-off event for employees 157 from 2025-12-01 to 2025-12-31:\n\n```\nBEGIN:VCALENDAR") {
+		t.Errorf("Expected the calendar in a code block, got %q", text)
+	}
+}
@@ -0,0 +1,351 @@
+package mcpserver
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+
+	"bamboohr-mcp-server/bamboohr"
+)
+
+// accrualCapMargin is the fraction of the accrual cap from which a balance is approaching it
+const accrualCapMargin = 0.9
+
+// unusedLeaveEmployeeLimit is the most employees report_unused_leave checks in one call, as
+// each one's balance is a separate BambooHR call
+const unusedLeaveEmployeeLimit = 50
+
+// UnusedLeaveBalance is an employee's balance of a vacation type in the unused leave report
+type UnusedLeaveBalance struct {
+	TimeOffTypeID  string  `json:"timeOffTypeId"`
+	Name           string  `json:"name"`
+	Balance        float64 `json:"balance"`
+	Units          string  `json:"units"`
+	UsedYearToDate float64 `json:"usedYearToDate"`
+	Expires        string  `json:"expires" jsonschema:"description=When the balance expires: its end date if in the future, otherwise the next policy year end"`
+	Expiring       bool    `json:"expiring" jsonschema:"description=Whether a positive balance expires within the expiry window"`
+	ApproachingCap bool    `json:"approachingCap" jsonschema:"description=Whether the balance is within 10% of the accrual cap"`
+}
+
+// UnusedLeaveEmployee is an employee flagged by the unused leave report
+type UnusedLeaveEmployee struct {
+	EmployeeID       string               `json:"employeeId"`
+	Name             string               `json:"name"`
+	Department       string               `json:"department,omitempty"`
+	LastVacation     string               `json:"lastVacation,omitempty" jsonschema:"description=Last day of the employee's most recent approved vacation, if any"`
+	NextVacation     string               `json:"nextVacation,omitempty" jsonschema:"description=First day of the employee's next approved vacation, if any"`
+	NoRecentVacation bool                 `json:"noRecentVacation"`
+	Balances         []UnusedLeaveBalance `json:"balances"`
+}
+
+// UnusedLeaveResult is the structured output of report_unused_leave
+type UnusedLeaveResult struct {
+	AsOf       string                `json:"asOf"`
+	Since      string                `json:"since" jsonschema:"description=Employees without approved vacation since this date are flagged"`
+	ExpiringBy string                `json:"expiringBy" jsonschema:"description=Balances expiring on or before this date are flagged"`
+	AccrualCap float64               `json:"accrualCap,omitempty"`
+	Checked    int                   `json:"checked" jsonschema:"description=Number of employees checked"`
+	Employees  []UnusedLeaveEmployee `json:"employees" jsonschema:"description=The flagged employees"`
+}
+
+// unusedLeaveOptions are the thresholds of the unused leave report
+type unusedLeaveOptions struct {
+	today         time.Time
+	since         time.Time
+	expiringBy    time.Time
+	policyYearEnd string
+	accrualCap    float64
+	typeIDs       []string
+}
+
+func handleReportUnusedLeave(client BambooHR, dates *DateParser, me int) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		format, err := toolFormat(request, FormatCompact)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		locale, err := toolLocale(request, dates)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		months := request.GetInt("months", 6)
+		if months < 1 || months > 24 {
+			return mcp.NewToolResultError("months must be between 1 and 24"), nil
+		}
+
+		expiringWithin := request.GetInt("expiringWithinDays", 60)
+		if expiringWithin < 0 || expiringWithin > 366 {
+			return mcp.NewToolResultError("expiringWithinDays must be between 0 and 366"), nil
+		}
+
+		accrualCap := request.GetFloat("accrualCap", 0)
+		if accrualCap < 0 {
+			return mcp.NewToolResultError("accrualCap must not be negative"), nil
+		}
+
+		policyYearEnd := request.GetString("policyYearEnd", "12-31")
+		if _, err := time.Parse("01-02", policyYearEnd); err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("policyYearEnd must be a month and day in MM-DD format, got %q", policyYearEnd)), nil
+		}
+
+		typeIDs, err := resolveTimeOffTypeIDs(client, request.GetString("vacationType", "Vacation"))
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("vacationType: %s", err.Error())), nil
+		}
+		if len(typeIDs) == 0 {
+			return mcp.NewToolResultError("vacationType must name at least one time off type"), nil
+		}
+
+		directory, err := client.GetEmployeeDirectory()
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Failed to get employee directory: %s", err.Error())), nil
+		}
+
+		// Report on the whole company unless a team is given
+		var employeeIDs []int
+		managerID, err := toolManagerID(request, me)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		if ids, department := request.GetString("employeeIds", ""), request.GetString("department", ""); ids != "" || department != "" || managerID != 0 {
+			if employeeIDs, err = teamEmployeeIDs(client, ids, department, managerID); err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+		} else {
+			for _, employee := range directory.Employees {
+				if id, err := strconv.Atoi(employee.ID); err == nil {
+					employeeIDs = append(employeeIDs, id)
+				}
+			}
+		}
+
+		if len(employeeIDs) > unusedLeaveEmployeeLimit {
+			return mcp.NewToolResultError(fmt.Sprintf("checking %d employees needs a balance call for each, and at most %d are checked at once: narrow the report with employeeIds, department or managerId",
+				len(employeeIDs), unusedLeaveEmployeeLimit)), nil
+		}
+
+		today := dates.ForEmployees(employeeIDs).Today()
+		opts := unusedLeaveOptions{
+			today:         today,
+			since:         today.AddDate(0, -months, 0),
+			expiringBy:    today.AddDate(0, 0, expiringWithin),
+			policyYearEnd: policyYearEnd,
+			accrualCap:    accrualCap,
+			typeIDs:       typeIDs,
+		}
+
+		result, err := reportUnusedLeave(client, directory, employeeIDs, opts)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Failed to report unused leave: %s", err.Error())), nil
+		}
+
+		return renderToolResult(result, unusedLeaveView(result, locale), format), nil
+	}
+}
+
+// reportUnusedLeave flags the employees who have taken no approved vacation since opts.since,
+// whose vacation balance expires by opts.expiringBy, or whose balance is approaching the
+// accrual cap
+func reportUnusedLeave(client BambooHR, directory *bamboohr.EmployeeDirectory, employeeIDs []int, opts unusedLeaveOptions) (UnusedLeaveResult, error) {
+	result := UnusedLeaveResult{
+		AsOf:       opts.today.Format(dateLayout),
+		Since:      opts.since.Format(dateLayout),
+		ExpiringBy: opts.expiringBy.Format(dateLayout),
+		AccrualCap: opts.accrualCap,
+		Checked:    len(employeeIDs),
+		Employees:  []UnusedLeaveEmployee{},
+	}
+
+	// Approved vacation from the start of the window to a year ahead, to find each employee's
+	// last and next vacation
+	requests, err := teamTimeOffRequests(client, employeeIDs, bamboohr.TimeOffRequestQuery{
+		Start:    result.Since,
+		End:      opts.today.AddDate(1, 0, 0).Format(dateLayout),
+		Statuses: []string{"approved"},
+		TypeIDs:  opts.typeIDs,
+	})
+	if err != nil {
+		return result, err
+	}
+
+	balances := make([][]bamboohr.TimeOffBalance, len(employeeIDs))
+	err = forEachEmployee(employeeIDs, func(i, employeeID int) error {
+		var err error
+		balances[i], err = client.GetTimeOffBalance(employeeID)
+		if err != nil {
+			return fmt.Errorf("failed to get time-off balance for employee %d: %w", employeeID, err)
+		}
+		return nil
+	})
+	if err != nil {
+		return result, err
+	}
+
+	employees := make(map[string]bamboohr.Employee, len(directory.Employees))
+	for _, employee := range directory.Employees {
+		employees[employee.ID] = employee
+	}
+
+	todayYMD := result.AsOf
+	for i, employeeID := range employeeIDs {
+		id := strconv.Itoa(employeeID)
+		employee := UnusedLeaveEmployee{
+			EmployeeID: id,
+			Name:       employees[id].DisplayName,
+			Department: employees[id].Department,
+			Balances:   []UnusedLeaveBalance{},
+		}
+
+		for _, request := range requests {
+			if request.EmployeeID != id {
+				continue
+			}
+			if employee.Name == "" {
+				employee.Name = request.Name
+			}
+
+			if request.Start <= todayYMD {
+				last := min(request.End, todayYMD)
+				if last > employee.LastVacation {
+					employee.LastVacation = last
+				}
+			} else if employee.NextVacation == "" || request.Start < employee.NextVacation {
+				employee.NextVacation = request.Start
+			}
+		}
+		employee.NoRecentVacation = employee.LastVacation < result.Since
+
+		flagged := employee.NoRecentVacation
+		for _, balance := range balances[i] {
+			// Discretionary (unlimited) policies have no balance to use up
+			if !slices.Contains(opts.typeIDs, balance.TimeOffType) || strings.EqualFold(balance.PolicyType, "discretionary") {
+				continue
+			}
+
+			entry := UnusedLeaveBalance{
+				TimeOffTypeID:  balance.TimeOffType,
+				Name:           balance.Name,
+				Balance:        float64(balance.Balance),
+				Units:          balance.Units,
+				UsedYearToDate: float64(balance.UsedYearToDate),
+				Expires:        balanceExpiry(balance, opts.today, opts.policyYearEnd),
+			}
+			entry.Expiring = entry.Balance > 0 && entry.Expires <= result.ExpiringBy
+			entry.ApproachingCap = opts.accrualCap > 0 && entry.Balance >= opts.accrualCap*accrualCapMargin
+
+			flagged = flagged || entry.Expiring || entry.ApproachingCap
+			employee.Balances = append(employee.Balances, entry)
+		}
+
+		if flagged {
+			result.Employees = append(result.Employees, employee)
+		}
+	}
+
+	// Largest balances first, as they have the most leave to use
+	sort.SliceStable(result.Employees, func(i, j int) bool {
+		a, b := maxBalance(result.Employees[i]), maxBalance(result.Employees[j])
+		if a != b {
+			return a > b
+		}
+		return result.Employees[i].Name < result.Employees[j].Name
+	})
+
+	return result, nil
+}
+
+// balanceExpiry returns the date the balance expires: its end date if that is in the future,
+// otherwise the next policy year end (MM-DD) after today
+func balanceExpiry(balance bamboohr.TimeOffBalance, today time.Time, policyYearEnd string) string {
+	if end, err := time.Parse(dateLayout, balance.End); err == nil && end.After(today) {
+		return balance.End
+	}
+
+	yearEnd := fmt.Sprintf("%d-%s", today.Year(), policyYearEnd)
+	if yearEnd < today.Format(dateLayout) {
+		yearEnd = fmt.Sprintf("%d-%s", today.Year()+1, policyYearEnd)
+	}
+	return yearEnd
+}
+
+// maxBalance returns the employee's largest vacation balance
+func maxBalance(employee UnusedLeaveEmployee) float64 {
+	var largest float64
+	for _, balance := range employee.Balances {
+		largest = max(largest, balance.Balance)
+	}
+	return largest
+}
+
+// unusedLeaveView shows the flagged employees as text, one line per employee with the reasons
+// they were flagged
+func unusedLeaveView(result UnusedLeaveResult, locale Locale) textView {
+	view := textView{
+		Title: fmt.Sprintf("%d of %s flagged for unused leave as of %s", len(result.Employees),
+			pluralize(result.Checked, "employee", "employees"), locale.FormatDate(result.AsOf)),
+		Columns: []string{"Employee ID", "Name", "Department", "Last Vacation", "Next Vacation", "No Recent Vacation", "Balance", "Units", "Expires", "Expiring", "Approaching Cap"},
+	}
+
+	for _, employee := range result.Employees {
+		var reasons []string
+		if employee.NoRecentVacation {
+			reason := "no vacation since " + locale.FormatDate(result.Since)
+			if employee.LastVacation != "" {
+				reason = "last vacation ended " + locale.FormatDate(employee.LastVacation)
+			}
+			if employee.NextVacation != "" {
+				reason += ", next starts " + locale.FormatDate(employee.NextVacation)
+			}
+			reasons = append(reasons, reason)
+		}
+
+		var balance, units, expires string
+		var expiring, approachingCap bool
+		largest := maxBalance(employee)
+		for _, entry := range employee.Balances {
+			if entry.Expiring {
+				reasons = append(reasons, fmt.Sprintf("%s of %s expire %s", formatAmount(entry.Balance, entry.Units), entry.Name, locale.FormatDate(entry.Expires)))
+			}
+			if entry.ApproachingCap {
+				reasons = append(reasons, fmt.Sprintf("%s of %s is near the cap of %s", formatAmount(entry.Balance, entry.Units), entry.Name, strconv.FormatFloat(result.AccrualCap, 'f', -1, 64)))
+			}
+
+			// Tables show the largest balance
+			if balance == "" && entry.Balance == largest {
+				balance, units, expires = strconv.FormatFloat(entry.Balance, 'f', -1, 64), entry.Units, locale.FormatDate(entry.Expires)
+			}
+			expiring = expiring || entry.Expiring
+			approachingCap = approachingCap || entry.ApproachingCap
+		}
+
+		item := employee.Name + " (ID " + employee.EmployeeID + ")"
+		if employee.Department != "" {
+			item += ", " + employee.Department
+		}
+		view.Items = append(view.Items, item+": "+strings.Join(reasons, "; "))
+		view.Rows = append(view.Rows, []string{
+			employee.EmployeeID,
+			employee.Name,
+			employee.Department,
+			locale.FormatDate(employee.LastVacation),
+			locale.FormatDate(employee.NextVacation),
+			strconv.FormatBool(employee.NoRecentVacation),
+			balance,
+			units,
+			expires,
+			strconv.FormatBool(expiring),
+			strconv.FormatBool(approachingCap),
+		})
+	}
+
+	return view
+}
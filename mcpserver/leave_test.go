@@ -0,0 +1,140 @@
+package mcpserver
+
+import (
+	"encoding/json"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+
+	"bamboohr-mcp-server/bamboohr"
+)
+
+func newUnusedLeaveTestFake() *fakeBambooHR {
+	fake := newFakeBambooHR(orgChartTestEmployees()...)
+	fake.types.TimeOffTypes = []bamboohr.TimeOffType{{ID: "78", Name: "Vacation"}, {ID: "83", Name: "Sick"}}
+
+	fake.requests[1] = []bamboohr.TimeOffRequest{
+		approvalsTestRequest("30", "1", "Ava Adams", "2025-08-01", "2025-08-01", "approved", 1, false),
+	}
+	fake.requests[2] = []bamboohr.TimeOffRequest{
+		approvalsTestRequest("31", "2", "Ben Brown", "2025-09-01", "2025-09-05", "approved", 5, false),
+	}
+	fake.requests[3] = []bamboohr.TimeOffRequest{
+		approvalsTestRequest("32", "3", "Cleo Cruz", "2025-10-01", "2025-10-02", "approved", 2, false),
+	}
+	fake.requests[4] = []bamboohr.TimeOffRequest{
+		approvalsTestRequest("33", "4", "Dan Diaz", "2025-01-06", "2025-01-10", "approved", 5, false),
+		approvalsTestRequest("34", "4", "Dan Diaz", "2025-08-11", "2025-08-15", "denied", 5, false),
+	}
+	fake.requests[5] = []bamboohr.TimeOffRequest{
+		approvalsTestRequest("35", "5", "Eve Evans", "2025-07-01", "2025-07-04", "approved", 4, false),
+	}
+
+	vacation := func(balance float64, end, policyType string) []bamboohr.TimeOffBalance {
+		return []bamboohr.TimeOffBalance{
+			{TimeOffType: "78", Name: "Vacation", Units: "days", Balance: bamboohr.FlexibleFloat(balance), End: end, PolicyType: policyType},
+			{TimeOffType: "83", Name: "Sick", Units: "days", Balance: 30, End: end},
+		}
+	}
+	fake.balances[1] = vacation(3, "2025-09-03", "accruing")
+	fake.balances[2] = vacation(0, "2025-09-03", "discretionary")
+	fake.balances[3] = vacation(5, "2025-09-30", "accruing")
+	fake.balances[4] = vacation(18, "2025-09-03", "accruing")
+	fake.balances[5] = vacation(22, "2025-09-03", "accruing")
+
+	return fake
+}
+
+func TestReportUnusedLeave(t *testing.T) {
+	s := New(newUnusedLeaveTestFake(), newTestDateParser())
+
+	text, isError := callTool(t, s, "report_unused_leave", map[string]string{"accrualCap": "24"})
+	expected := "3 of 5 employees flagged for unused leave as of 2025-09-03:\n" +
+		"- Eve Evans (ID 5), Engineering: 22 days of Vacation is near the cap of 24\n" +
+		"- Dan Diaz (ID 4), Engineering: no vacation since 2025-03-03\n" +
+		"- Cleo Cruz (ID 3), Finance: no vacation since 2025-03-03, next starts 2025-10-01; 5 days of Vacation expire 2025-09-30"
+	if isError || text != expected {
+		t.Errorf("Expected %q, got %q", expected, text)
+	}
+
+	// Balances expire at the policy year end, which is within 120 days
+	text, isError = callTool(t, s, "report_unused_leave", map[string]string{"managerId": "2", "expiringWithinDays": "120", "format": "full"})
+	if isError {
+		t.Fatalf("Unexpected tool error: %s", text)
+	}
+
+	var result UnusedLeaveResult
+	if err := json.Unmarshal([]byte(text), &result); err != nil {
+		t.Fatalf("Failed to decode result: %v", err)
+	}
+	if result.Checked != 2 || len(result.Employees) != 2 {
+		t.Fatalf("Expected both of Ben's reports to be flagged, got %+v", result)
+	}
+	eve := result.Employees[0]
+	if eve.Name != "Eve Evans" || eve.NoRecentVacation || eve.LastVacation != "2025-07-04" || len(eve.Balances) != 1 {
+		t.Errorf("Unexpected entry for Eve: %+v", eve)
+	}
+	if balance := eve.Balances[0]; balance.Expires != "2025-12-31" || !balance.Expiring || balance.ApproachingCap {
+		t.Errorf("Unexpected balance for Eve: %+v", balance)
+	}
+}
+
+func TestReportUnusedLeave_Errors(t *testing.T) {
+	s := New(newUnusedLeaveTestFake(), newTestDateParser())
+
+	tests := []map[string]string{
+		{"months": "0"},
+		{"expiringWithinDays": "400"},
+		{"accrualCap": "-1"},
+		{"policyYearEnd": "31-12"},
+		{"vacationType": "Holiday"},
+		{"managerId": "me"},
+	}
+	for _, arguments := range tests {
+		if text, isError := callTool(t, s, "report_unused_leave", arguments); !isError {
+			t.Errorf("Expected tool error for %v, got %q", arguments, text)
+		}
+	}
+}
+
+func TestReportUnusedLeave_EmployeeLimit(t *testing.T) {
+	fake := newUnusedLeaveTestFake()
+	for id := 100; len(fake.employees) <= unusedLeaveEmployeeLimit; id++ {
+		fake.employees = append(fake.employees, bamboohr.Employee{ID: strconv.Itoa(id), DisplayName: "Employee " + strconv.Itoa(id), Department: "Sales"})
+	}
+	s := New(fake, newTestDateParser())
+
+	text, isError := callTool(t, s, "report_unused_leave", map[string]string{})
+	if !isError || !strings.Contains(text, "narrow the report") {
+		t.Errorf("Expected the company to be too large to check at once, got %q", text)
+	}
+	if slices.Contains(fake.calls, "GetTimeOffBalance") {
+		t.Errorf("Expected no balances to be fetched, got calls %v", fake.calls)
+	}
+
+	if text, isError := callTool(t, s, "report_unused_leave", map[string]string{"department": "Finance"}); isError {
+		t.Errorf("Expected a department to be checked, got %q", text)
+	}
+}
+
+func TestBalanceExpiry(t *testing.T) {
+	today := newTestDateParser().Today()
+
+	tests := []struct {
+		end           string
+		policyYearEnd string
+		expected      string
+	}{
+		{"2025-10-31", "12-31", "2025-10-31"},
+		{"2025-09-03", "12-31", "2025-12-31"},
+		{"", "06-30", "2026-06-30"},
+		{"", "09-03", "2025-09-03"},
+	}
+
+	for _, tt := range tests {
+		if got := balanceExpiry(bamboohr.TimeOffBalance{End: tt.end}, today, tt.policyYearEnd); got != tt.expected {
+			t.Errorf("balanceExpiry(%q, %q): expected %s, got %s", tt.end, tt.policyYearEnd, tt.expected, got)
+		}
+	}
+}
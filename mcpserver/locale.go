@@ -0,0 +1,181 @@
+package mcpserver
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Locale formats dates for display in a regional convention
+type Locale struct {
+	Name   string
+	layout string
+}
+
+// ISOLocale formats dates as YYYY-MM-DD, exactly as returned by the BambooHR API
+var ISOLocale = Locale{Name: "iso", layout: dateLayout}
+
+// localeLayouts maps locale names and language codes to their short date layout
+var localeLayouts = map[string]string{
+	"iso":   dateLayout,
+	"en-us": "01/02/2006",
+	"en-ca": dateLayout,
+	"en-gb": "02/01/2006",
+	"en-au": "02/01/2006",
+	"en-nz": "02/01/2006",
+	"en-ie": "02/01/2006",
+	"en-in": "02/01/2006",
+	"en":    "01/02/2006",
+	"de":    "02.01.2006",
+	"fr":    "02/01/2006",
+	"es":    "02/01/2006",
+	"it":    "02/01/2006",
+	"pt":    "02/01/2006",
+	"nl":    "02-01-2006",
+	"da":    "02.01.2006",
+	"nb":    "02.01.2006",
+	"fi":    "2.1.2006",
+	"pl":    "02.01.2006",
+	"sv":    dateLayout,
+	"ja":    "2006/01/02",
+	"zh":    "2006/01/02",
+	"ko":    "2006. 01. 02.",
+}
+
+// ParseLocale returns the locale for a BCP 47 style name such as "en-AU" or "de_DE".
+// Unknown regions fall back to the language, so "de-AT" formats like "de".
+func ParseLocale(name string) (Locale, error) {
+	normalized := strings.ToLower(strings.ReplaceAll(strings.TrimSpace(name), "_", "-"))
+	if normalized == "" {
+		return ISOLocale, nil
+	}
+
+	if layout, ok := localeLayouts[normalized]; ok {
+		return Locale{Name: name, layout: layout}, nil
+	}
+
+	language, _, _ := strings.Cut(normalized, "-")
+	if layout, ok := localeLayouts[language]; ok {
+		return Locale{Name: name, layout: layout}, nil
+	}
+
+	supported := make([]string, 0, len(localeLayouts))
+	for key := range localeLayouts {
+		supported = append(supported, key)
+	}
+	sort.Strings(supported)
+
+	return Locale{}, fmt.Errorf("unsupported locale %q: supported locales are %s", name, strings.Join(supported, ", "))
+}
+
+// FormatDate formats a YYYY-MM-DD date, or a "YYYY-MM-DD HH:MM:SS" timestamp, for the locale.
+// Values that are not dates are returned unchanged.
+func (l Locale) FormatDate(value string) string {
+	if l.layout == "" || l.layout == dateLayout || len(value) < len(dateLayout) {
+		return value
+	}
+
+	date, err := time.Parse(dateLayout, value[:len(dateLayout)])
+	if err != nil {
+		return value
+	}
+
+	return date.Format(l.layout) + value[len(dateLayout):]
+}
+
+// TimezoneResolver resolves the timezone of an employee's work location from their BambooHR profile.
+// The profile's location is looked up in a configured mapping, or used directly if it is an IANA
+// timezone name. Resolved timezones are cached per employee.
+type TimezoneResolver struct {
+	client    BambooHR
+	locations map[string]*time.Location
+
+	mu    sync.Mutex
+	cache map[int]*time.Location
+}
+
+// NewTimezoneResolver creates a resolver that maps work location names to timezones
+func NewTimezoneResolver(client BambooHR, locations map[string]*time.Location) *TimezoneResolver {
+	normalized := make(map[string]*time.Location, len(locations))
+	for name, location := range locations {
+		normalized[strings.ToLower(strings.TrimSpace(name))] = location
+	}
+
+	return &TimezoneResolver{
+		client:    client,
+		locations: normalized,
+		cache:     make(map[int]*time.Location),
+	}
+}
+
+// ParseLocationTimezones parses a mapping of work locations to IANA timezones in the form
+// "Sydney=Australia/Sydney,San Francisco=America/Los_Angeles"
+func ParseLocationTimezones(value string) (map[string]*time.Location, error) {
+	locations := make(map[string]*time.Location)
+	for _, entry := range strings.Split(value, ",") {
+		if strings.TrimSpace(entry) == "" {
+			continue
+		}
+
+		name, zone, ok := strings.Cut(entry, "=")
+		if !ok || strings.TrimSpace(name) == "" {
+			return nil, fmt.Errorf("invalid location timezone %q: expected Location=Area/City", strings.TrimSpace(entry))
+		}
+
+		location, err := time.LoadLocation(strings.TrimSpace(zone))
+		if err != nil {
+			return nil, fmt.Errorf("invalid timezone for location %q: %w", strings.TrimSpace(name), err)
+		}
+
+		locations[strings.TrimSpace(name)] = location
+	}
+
+	return locations, nil
+}
+
+// Resolve returns the timezone of the employee's work location, or nil if it cannot be determined
+func (r *TimezoneResolver) Resolve(employeeID int) *time.Location {
+	r.mu.Lock()
+	location, ok := r.cache[employeeID]
+	r.mu.Unlock()
+	if ok {
+		return location
+	}
+
+	employee, err := r.client.GetEmployee(employeeID)
+	if err != nil {
+		// Don't cache failures so a transient API error doesn't pin the fallback timezone
+		return nil
+	}
+
+	location = r.lookup(employee.Location)
+
+	r.mu.Lock()
+	r.cache[employeeID] = location
+	r.mu.Unlock()
+
+	return location
+}
+
+// lookup maps a work location name to its timezone
+func (r *TimezoneResolver) lookup(name string) *time.Location {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil
+	}
+
+	if location, ok := r.locations[strings.ToLower(name)]; ok {
+		return location
+	}
+
+	// Allow locations to be named after their timezone, e.g. "Europe/Berlin"
+	if strings.Contains(name, "/") {
+		if location, err := time.LoadLocation(name); err == nil {
+			return location
+		}
+	}
+
+	return nil
+}
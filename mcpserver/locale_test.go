@@ -0,0 +1,238 @@
+package mcpserver
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"bamboohr-mcp-server/bamboohr"
+)
+
+func TestParseLocale(t *testing.T) {
+	tests := []struct {
+		name     string
+		date     string
+		expected string
+		hasError bool
+	}{
+		{"", "2025-12-23", "2025-12-23", false},
+		{"iso", "2025-12-23", "2025-12-23", false},
+		{"en-US", "2025-12-23", "12/23/2025", false},
+		{"en_GB", "2025-12-23", "23/12/2025", false},
+		{"en-AU", "2025-12-23", "23/12/2025", false},
+		{"de-AT", "2025-12-23", "23.12.2025", false},
+		{"ja-JP", "2025-12-23", "2025/12/23", false},
+		{"xx-YY", "", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			locale, err := ParseLocale(tt.name)
+			if tt.hasError {
+				if err == nil {
+					t.Error("Expected error, but got none")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			if formatted := locale.FormatDate(tt.date); formatted != tt.expected {
+				t.Errorf("Expected %s, got %s", tt.expected, formatted)
+			}
+		})
+	}
+}
+
+func TestLocale_FormatDate(t *testing.T) {
+	locale, _ := ParseLocale("en-GB")
+
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"2025-12-23", "23/12/2025"},
+		{"2025-09-01 14:23:47", "01/09/2025 14:23:47"},
+		{"", ""},
+		{"not a date", "not a date"},
+	}
+
+	for _, tt := range tests {
+		if formatted := locale.FormatDate(tt.input); formatted != tt.expected {
+			t.Errorf("FormatDate(%q): expected %q, got %q", tt.input, tt.expected, formatted)
+		}
+	}
+}
+
+func TestParseLocationTimezones(t *testing.T) {
+	locations, err := ParseLocationTimezones("Sydney=Australia/Sydney, San Francisco = America/Los_Angeles,")
+	if err != nil {
+		t.Skipf("timezone data not available: %v", err)
+	}
+
+	if len(locations) != 2 {
+		t.Fatalf("Expected 2 locations, got %d", len(locations))
+	}
+
+	if locations["San Francisco"].String() != "America/Los_Angeles" {
+		t.Errorf("Expected San Francisco in America/Los_Angeles, got %v", locations["San Francisco"])
+	}
+
+	for _, input := range []string{"Sydney", "=UTC", "Sydney=Not/AZone"} {
+		if _, err := ParseLocationTimezones(input); err == nil {
+			t.Errorf("Expected error for %q, but got none", input)
+		}
+	}
+}
+
+func TestTimezoneResolver_Resolve(t *testing.T) {
+	sydney, err := time.LoadLocation("Australia/Sydney")
+	if err != nil {
+		t.Skipf("timezone data not available: %v", err)
+	}
+
+	requests := 0
+	mock := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/employees/157":
+			w.Write([]byte(`{"id": "157", "location": "sydney"}`))
+		case "/employees/158":
+			w.Write([]byte(`{"id": "158", "location": "Europe/Berlin"}`))
+		case "/employees/159":
+			w.Write([]byte(`{"id": "159", "location": "Remote"}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer mock.Close()
+
+	client := bamboohr.NewClient("testcompany", "testkey")
+	client.BaseURL = mock.URL
+	resolver := NewTimezoneResolver(client, map[string]*time.Location{"Sydney": sydney})
+
+	if location := resolver.Resolve(157); location != sydney {
+		t.Errorf("Expected Sydney, got %v", location)
+	}
+
+	if location := resolver.Resolve(158); location == nil || location.String() != "Europe/Berlin" {
+		t.Errorf("Expected Europe/Berlin, got %v", location)
+	}
+
+	if location := resolver.Resolve(159); location != nil {
+		t.Errorf("Expected no timezone for an unmapped location, got %v", location)
+	}
+
+	if location := resolver.Resolve(404); location != nil {
+		t.Errorf("Expected no timezone for an unknown employee, got %v", location)
+	}
+
+	before := requests
+	resolver.Resolve(157)
+	if requests != before {
+		t.Error("Expected the resolved timezone to be cached")
+	}
+}
+
+func TestHandleGetTimeOffRequests_EmployeeTimezoneAndLocale(t *testing.T) {
+	sydney, err := time.LoadLocation("Australia/Sydney")
+	if err != nil {
+		t.Skipf("timezone data not available: %v", err)
+	}
+
+	var query string
+	mock := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/employees/157":
+			w.Write([]byte(`{"id": "157", "location": "Sydney"}`))
+		case "/time_off/requests":
+			query = r.URL.RawQuery
+			w.Write([]byte(`[{"id": "1", "employeeId": "157", "start": "2026-01-05", "end": "2026-01-09"}]`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer mock.Close()
+
+	client := bamboohr.NewClient("testcompany", "testkey")
+	client.BaseURL = mock.URL
+	client.V1BaseURL = mock.URL
+
+	// 20:00 UTC on New Year's Eve is already 2026 for an employee in Sydney
+	dates := NewDateParser(time.UTC, time.Date(2025, 12, 31, 20, 0, 0, 0, time.UTC)).
+		WithTimezones(NewTimezoneResolver(client, map[string]*time.Location{"Sydney": sydney}))
+	handler := handleGetTimeOffRequests(client, dates, 0)
+
+	request := mcp.CallToolRequest{}
+	request.Params.Arguments = map[string]any{"employeeId": "157", "locale": "en-AU"}
+
+	result, err := handler(t.Context(), request)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if result.IsError {
+		t.Fatalf("Unexpected tool error: %v", result.Content)
+	}
+
+	if query != "employeeId=157&end=2026-12-31&start=2026-01-01" {
+		t.Errorf("Expected the current year in Sydney, got %s", query)
+	}
+
+	text := result.Content[0].(mcp.TextContent).Text
+	if !strings.Contains(text, "05/01/2026 to 09/01/2026") {
+		t.Errorf("Expected dates formatted for en-AU, got %s", text)
+	}
+
+	request.Params.Arguments = map[string]any{"employeeId": "157", "locale": "klingon"}
+	result, err = handler(t.Context(), request)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if !result.IsError {
+		t.Error("Expected tool error for an unsupported locale")
+	}
+}
+
+func TestForEmployees_ToolDefaults(t *testing.T) {
+	sydney, err := time.LoadLocation("Australia/Sydney")
+	if err != nil {
+		t.Skipf("timezone data not available: %v", err)
+	}
+
+	fake := newFakeBambooHR(
+		bamboohr.Employee{ID: "4", DisplayName: "Charlotte Abbott", Location: "Sydney"},
+		bamboohr.Employee{ID: "5", DisplayName: "Ashley Adams", Location: "London"},
+	)
+	fake.types.TimeOffTypes = []bamboohr.TimeOffType{{ID: "78", Name: "Vacation"}}
+	fake.balances[4] = []bamboohr.TimeOffBalance{{TimeOffType: "78", Name: "Vacation", Units: "days", Balance: 5}}
+
+	// 20:00 UTC on New Year's Eve is already 2026 for the employee in Sydney
+	dates := NewDateParser(time.UTC, time.Date(2025, 12, 31, 20, 0, 0, 0, time.UTC)).
+		WithTimezones(NewTimezoneResolver(fake, map[string]*time.Location{"Sydney": sydney}))
+	s := New(fake, dates)
+
+	tests := []struct {
+		tool      string
+		arguments map[string]string
+		expected  string
+	}{
+		{"export_time_off_ical", map[string]string{"employeeIds": "4", "format": "markdown"}, "from 2026-01-01 to 2026-12-31"},
+		{"export_time_off_ical", map[string]string{"employeeIds": "4,5", "format": "markdown"}, "from 2025-01-01 to 2025-12-31"},
+		{"simulate_time_off_balance", map[string]string{"employeeId": "4", "timeOffType": "78", "months": "1", "format": "full"}, `"month": "2026-01"`},
+	}
+	for _, tt := range tests {
+		text, isError := callTool(t, s, tt.tool, tt.arguments)
+		if isError || !strings.Contains(text, tt.expected) {
+			t.Errorf("%s %v: expected %q, got %q", tt.tool, tt.arguments, tt.expected, text)
+		}
+	}
+}
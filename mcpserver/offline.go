@@ -0,0 +1,210 @@
+package mcpserver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync/atomic"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+
+	"bamboohr-mcp-server/bamboohr"
+	"bamboohr-mcp-server/store"
+)
+
+// ErrOffline is returned for writes while BambooHR is unreachable
+var ErrOffline = errors.New("BambooHR is unreachable, the server is in read-only mode")
+
+// offlineClient is a BambooHR decorator that serves reads from the local store while BambooHR
+// is unreachable
+type offlineClient struct {
+	next  BambooHR
+	store *store.Store
+	// location is the timezone of the current year that searches without dates default to
+	location *time.Location
+	// served counts the reads served from the store, so that tool results can be marked stale
+	served atomic.Uint64
+}
+
+// NewOfflineFallback wraps the client so that the directory, employees, time off types,
+// time-off requests and balances are read from the store when BambooHR is unreachable or
+// failing. Requests filtered to those the API key may approve are not served from the store.
+// Searches without dates cover the current year in the location, as they do in BambooHR.
+// Writes fail with ErrOffline until BambooHR is back. Pass the returned client to
+// WithOfflineNotice to mark tool results read from the store.
+func NewOfflineFallback(client BambooHR, st *store.Store, location *time.Location) BambooHR {
+	return &offlineClient{next: client, store: st, location: location}
+}
+
+// WithOfflineNotice adds a note to tool results that were read from the local store because
+// BambooHR was unreachable, saying when the store was last synced. The client must be the one
+// returned by NewOfflineFallback.
+func WithOfflineNotice(client BambooHR) Option {
+	return func(o *options) {
+		if offline, ok := client.(*offlineClient); ok {
+			o.offline = offline
+		}
+	}
+}
+
+// fallback returns the stored value if err means BambooHR is unavailable and the store has it,
+// otherwise the original error. Stored values are counted in served.
+func fallback[T any](served *atomic.Uint64, err error, stored func() (T, error)) (T, error) {
+	var zero T
+	if !bamboohr.IsUnavailable(err) {
+		return zero, err
+	}
+
+	value, storeErr := stored()
+	if storeErr != nil {
+		return zero, err
+	}
+
+	served.Add(1)
+	return value, nil
+}
+
+// notice is a tool handler middleware that appends the offline note to results of calls that
+// read from the store. Calls running at the same time share the count, so a call may be marked
+// when another was served from the store, which only happens while BambooHR is unreachable.
+func (o *offlineClient) notice(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		before := o.served.Load()
+		result, err := next(ctx, request)
+		if err != nil || result == nil || o.served.Load() == before {
+			return result, err
+		}
+
+		note := "Note: BambooHR is unreachable, so this was read from the local store"
+		if syncedAt, err := o.store.SyncedAt(); err == nil {
+			note += " as of the sync at " + syncedAt.UTC().Format(time.RFC3339)
+		}
+		result.Content = append(result.Content, mcp.NewTextContent(note+" and may be out of date."))
+		return result, nil
+	}
+}
+
+// offline returns ErrOffline for a write that failed because BambooHR is unavailable
+func offline(err error) error {
+	if bamboohr.IsUnavailable(err) {
+		return fmt.Errorf("%w: %v", ErrOffline, err)
+	}
+	return err
+}
+
+func (o *offlineClient) Company() string {
+	return o.next.Company()
+}
+
+func (o *offlineClient) GetTimeOffRequests(employeeID int, start, end string) ([]bamboohr.TimeOffRequest, error) {
+	requests, err := o.next.GetTimeOffRequests(employeeID, start, end)
+	if err == nil {
+		return requests, nil
+	}
+
+	return fallback(&o.served, err, func() ([]bamboohr.TimeOffRequest, error) {
+		if start == "" || end == "" {
+			start, end = bamboohr.CurrentYearRange(o.location)
+		}
+		return o.store.TimeOffRequests(employeeID, start, end)
+	})
+}
+
+func (o *offlineClient) SearchTimeOffRequests(query bamboohr.TimeOffRequestQuery) ([]bamboohr.TimeOffRequest, error) {
+	requests, err := o.next.SearchTimeOffRequests(query)
+	if err == nil {
+		return requests, nil
+	}
+
+	// The store doesn't know which requests the API key may approve
+	if query.Action == bamboohr.TimeOffActionApprove {
+		return nil, err
+	}
+
+	return fallback(&o.served, err, func() ([]bamboohr.TimeOffRequest, error) {
+		filter := store.RequestFilter{Start: query.Start, End: query.End, Statuses: query.Statuses}
+		if filter.Start == "" || filter.End == "" {
+			filter.Start, filter.End = bamboohr.CurrentYearRange(o.location)
+		}
+		if query.EmployeeID != 0 {
+			filter.EmployeeIDs = []int{query.EmployeeID}
+		}
+
+		requests, err := o.store.SearchTimeOffRequests(filter)
+		if err != nil || len(query.TypeIDs) == 0 {
+			return requests, err
+		}
+
+		var filtered []bamboohr.TimeOffRequest
+		for _, request := range requests {
+			if slices.Contains(query.TypeIDs, request.Type.ID) {
+				filtered = append(filtered, request)
+			}
+		}
+		return filtered, nil
+	})
+}
+
+func (o *offlineClient) GetTimeOffBalance(employeeID int) ([]bamboohr.TimeOffBalance, error) {
+	balances, err := o.next.GetTimeOffBalance(employeeID)
+	if err == nil {
+		return balances, nil
+	}
+
+	return fallback(&o.served, err, func() ([]bamboohr.TimeOffBalance, error) {
+		return o.store.TimeOffBalance(employeeID)
+	})
+}
+
+func (o *offlineClient) CreateTimeOffRequest(employeeID int, request bamboohr.TimeOffRequestCreate) (*bamboohr.TimeOffRequest, error) {
+	created, err := o.next.CreateTimeOffRequest(employeeID, request)
+	return created, offline(err)
+}
+
+func (o *offlineClient) GetEmployeeDirectory() (*bamboohr.EmployeeDirectory, error) {
+	directory, err := o.next.GetEmployeeDirectory()
+	if err == nil {
+		return directory, nil
+	}
+
+	return fallback(&o.served, err, o.store.EmployeeDirectory)
+}
+
+func (o *offlineClient) GetEmployee(employeeID int) (*bamboohr.Employee, error) {
+	employee, err := o.next.GetEmployee(employeeID)
+	if err == nil {
+		return employee, nil
+	}
+
+	return fallback(&o.served, err, func() (*bamboohr.Employee, error) {
+		return o.store.Employee(employeeID)
+	})
+}
+
+func (o *offlineClient) GetChangedEmployees(since time.Time) (*bamboohr.EmployeeChanges, error) {
+	return o.next.GetChangedEmployees(since)
+}
+
+func (o *offlineClient) GetTimeOffTypes() (*bamboohr.TimeOffTypes, error) {
+	types, err := o.next.GetTimeOffTypes()
+	if err == nil {
+		return types, nil
+	}
+
+	return fallback(&o.served, err, o.store.TimeOffTypes)
+}
+
+func (o *offlineClient) GetTimeOffPolicies() ([]bamboohr.TimeOffPolicy, error) {
+	return o.next.GetTimeOffPolicies()
+}
+
+func (o *offlineClient) GetEmployeeTimeOffPolicies(employeeID int) ([]bamboohr.EmployeeTimeOffPolicy, error) {
+	return o.next.GetEmployeeTimeOffPolicies(employeeID)
+}
+
+func (o *offlineClient) GetWhosOut(start, end string) ([]bamboohr.WhosOutEntry, error) {
+	return o.next.GetWhosOut(start, end)
+}
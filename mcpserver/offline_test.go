@@ -0,0 +1,104 @@
+package mcpserver
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"bamboohr-mcp-server/bamboohr"
+)
+
+func TestOfflineFallback(t *testing.T) {
+	st, fake := newHistoryTestStore(t)
+	client := NewOfflineFallback(fake, st, time.UTC)
+
+	fake.err = errNotReachable
+
+	directory, err := client.GetEmployeeDirectory()
+	if err != nil || len(directory.Employees) != 2 {
+		t.Errorf("Expected the stored directory, got %+v (%v)", directory, err)
+	}
+
+	employee, err := client.GetEmployee(5)
+	if err != nil || employee.DisplayName != "Ashley Adams" {
+		t.Errorf("Expected the stored employee, got %+v (%v)", employee, err)
+	}
+
+	requests, err := client.GetTimeOffRequests(4, "2024-01-01", "2024-06-30")
+	if err != nil || len(requests) != 1 || requests[0].ID != "1" {
+		t.Errorf("Expected the stored request, got %+v (%v)", requests, err)
+	}
+
+	requests, err = client.SearchTimeOffRequests(bamboohr.TimeOffRequestQuery{Start: "2024-01-01", End: "2024-06-30"})
+	if err != nil || len(requests) == 0 {
+		t.Errorf("Expected the stored requests, got %+v (%v)", requests, err)
+	}
+
+	// Without dates only the current year is searched, as in BambooHR, and the store has 2024
+	if requests, err := client.SearchTimeOffRequests(bamboohr.TimeOffRequestQuery{EmployeeID: 4}); err != nil || len(requests) != 0 {
+		t.Errorf("Expected no stored requests in the current year, got %+v (%v)", requests, err)
+	}
+
+	// The store doesn't know which requests may be approved
+	query := bamboohr.TimeOffRequestQuery{Start: "2024-01-01", End: "2024-06-30", Action: bamboohr.TimeOffActionApprove}
+	if _, err := client.SearchTimeOffRequests(query); !errors.Is(err, errNotReachable) {
+		t.Errorf("Expected the original error for requests to approve, got %v", err)
+	}
+
+	// Balances were never stored for employee 5, so the original error is returned
+	if _, err := client.GetTimeOffBalance(5); !errors.Is(err, errNotReachable) {
+		t.Errorf("Expected the original error, got %v", err)
+	}
+
+	_, err = client.CreateTimeOffRequest(4, bamboohr.TimeOffRequestCreate{})
+	if !errors.Is(err, ErrOffline) {
+		t.Errorf("Expected ErrOffline for a write, got %v", err)
+	}
+}
+
+func TestOfflineFallback_RejectedRequests(t *testing.T) {
+	st, fake := newHistoryTestStore(t)
+	client := NewOfflineFallback(fake, st, time.UTC)
+
+	// A request BambooHR rejects is not served from the store
+	fake.err = &bamboohr.APIError{StatusCode: 403, Body: "Forbidden"}
+
+	if _, err := client.GetEmployeeDirectory(); err == nil || !strings.Contains(err.Error(), "403") {
+		t.Errorf("Expected the API error, got %v", err)
+	}
+
+	if _, err := client.CreateTimeOffRequest(4, bamboohr.TimeOffRequestCreate{}); errors.Is(err, ErrOffline) {
+		t.Errorf("Expected the API error rather than ErrOffline, got %v", err)
+	}
+}
+
+func TestOfflineFallback_Notice(t *testing.T) {
+	st, fake := newHistoryTestStore(t)
+	client := NewOfflineFallback(fake, st, time.UTC)
+	s := New(client, newTestDateParser(), WithOfflineNotice(client))
+
+	text, _ := callTool(t, s, "list_employees", map[string]string{})
+	if strings.Contains(text, "local store") {
+		t.Errorf("Expected no note while BambooHR is reachable, got %q", text)
+	}
+
+	fake.err = errNotReachable
+	var result struct {
+		Content []mcp.TextContent `json:"content"`
+	}
+	response := sendMessage(t, s, "tools/call", map[string]interface{}{
+		"name":      "list_employees",
+		"arguments": map[string]string{},
+	})
+	if err := json.Unmarshal(response, &result); err != nil {
+		t.Fatalf("Failed to unmarshal result: %v", err)
+	}
+	expected := "Note: BambooHR is unreachable, so this was read from the local store as of the sync at 2025-01-02T03:00:00Z and may be out of date."
+	if len(result.Content) != 2 || !strings.Contains(result.Content[0].Text, "Ashley Adams") || result.Content[1].Text != expected {
+		t.Errorf("Expected the stored employees with a note, got %s", response)
+	}
+}
@@ -0,0 +1,385 @@
+package mcpserver
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+
+	"bamboohr-mcp-server/bamboohr"
+)
+
+// maxOrgDepth caps the levels walked up or down the org chart
+const maxOrgDepth = 20
+
+// OrgChartEmployee is an employee's place in the org chart. Level is the distance from the
+// employee the chart was walked from: managers count up from 1, reports count down from 1.
+type OrgChartEmployee struct {
+	ID          string `json:"id"`
+	DisplayName string `json:"displayName"`
+	JobTitle    string `json:"jobTitle,omitempty"`
+	Department  string `json:"department,omitempty"`
+	ManagerID   string `json:"managerId,omitempty"`
+	Level       int    `json:"level"`
+}
+
+// OrgChartResult is the structured result of the org chart tools
+type OrgChartResult struct {
+	Employee  OrgChartEmployee   `json:"employee"`
+	MaxDepth  int                `json:"maxDepth"`
+	Employees []OrgChartEmployee `json:"employees"`
+}
+
+// orgChart is the reporting lines of the employee directory
+type orgChart struct {
+	employees map[string]bamboohr.Employee
+	managers  map[string]string
+	reports   map[string][]string
+}
+
+// newOrgChart builds the org chart from the directory. An employee's manager is identified by
+// supervisorEId, or else by supervisorId matched against employee numbers, or else by the
+// supervisor's name if exactly one employee has that name.
+func newOrgChart(directory *bamboohr.EmployeeDirectory) *orgChart {
+	chart := &orgChart{
+		employees: make(map[string]bamboohr.Employee, len(directory.Employees)),
+		managers:  make(map[string]string),
+		reports:   make(map[string][]string),
+	}
+
+	byNumber := make(map[string]string)
+	byName := make(map[string][]string)
+	for _, employee := range directory.Employees {
+		chart.employees[employee.ID] = employee
+
+		if employee.EmployeeNumber != "" {
+			byNumber[employee.EmployeeNumber] = employee.ID
+		}
+		for _, name := range employeeNames(employee) {
+			byName[name] = append(byName[name], employee.ID)
+		}
+	}
+
+	for _, employee := range directory.Employees {
+		var managerID string
+		switch {
+		case employee.SupervisorEID != "":
+			managerID = employee.SupervisorEID
+		case employee.SupervisorID != "" && byNumber[employee.SupervisorID] != "":
+			managerID = byNumber[employee.SupervisorID]
+		case employee.Supervisor != "":
+			if ids := uniqueIDs(byName[strings.ToLower(strings.TrimSpace(employee.Supervisor))]); len(ids) == 1 {
+				managerID = ids[0]
+			}
+		}
+
+		if managerID == "" || managerID == employee.ID {
+			continue
+		}
+		if _, ok := chart.employees[managerID]; !ok {
+			continue
+		}
+
+		chart.managers[employee.ID] = managerID
+		chart.reports[managerID] = append(chart.reports[managerID], employee.ID)
+	}
+
+	return chart
+}
+
+// employeeNames returns the lower-case names a supervisor may be listed by
+func employeeNames(employee bamboohr.Employee) []string {
+	var names []string
+	if employee.DisplayName != "" {
+		names = append(names, strings.ToLower(employee.DisplayName))
+	}
+	if employee.FirstName != "" && employee.LastName != "" {
+		names = append(names,
+			strings.ToLower(employee.FirstName+" "+employee.LastName),
+			strings.ToLower(employee.LastName+", "+employee.FirstName))
+	}
+	return names
+}
+
+// uniqueIDs removes duplicate IDs, which occur when an employee has several matching names
+func uniqueIDs(ids []string) []string {
+	seen := make(map[string]bool)
+	var unique []string
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			unique = append(unique, id)
+		}
+	}
+	return unique
+}
+
+// employee returns the org chart entry of the employee at the level
+func (c *orgChart) employee(id string, level int) OrgChartEmployee {
+	employee := c.employees[id]
+	return OrgChartEmployee{
+		ID:          id,
+		DisplayName: employee.DisplayName,
+		JobTitle:    employee.JobTitle,
+		Department:  employee.Department,
+		ManagerID:   c.managers[id],
+		Level:       level,
+	}
+}
+
+// managerChain returns the employee's manager, their manager and so on, up to depth levels
+func (c *orgChart) managerChain(id string, depth int) []OrgChartEmployee {
+	var chain []OrgChartEmployee
+	seen := map[string]bool{id: true}
+
+	for level := 1; level <= depth; level++ {
+		managerID, ok := c.managers[id]
+		if !ok || seen[managerID] {
+			break
+		}
+		seen[managerID] = true
+		chain = append(chain, c.employee(managerID, level))
+		id = managerID
+	}
+
+	return chain
+}
+
+// subtree returns everyone reporting to the employee, directly or indirectly, up to depth
+// levels down, depth first so each employee is followed by their own reports
+func (c *orgChart) subtree(id string, depth int) []OrgChartEmployee {
+	var employees []OrgChartEmployee
+	seen := map[string]bool{id: true}
+
+	var walk func(managerID string, level int)
+	walk = func(managerID string, level int) {
+		if level > depth {
+			return
+		}
+		for _, reportID := range c.reports[managerID] {
+			if seen[reportID] {
+				continue
+			}
+			seen[reportID] = true
+			employees = append(employees, c.employee(reportID, level))
+			walk(reportID, level+1)
+		}
+	}
+	walk(id, 1)
+
+	return employees
+}
+
+// reportIDs returns the IDs of everyone reporting to the manager, directly or indirectly
+func (c *orgChart) reportIDs(managerID int) []int {
+	var ids []int
+	for _, employee := range c.subtree(strconv.Itoa(managerID), maxOrgDepth) {
+		if id, err := strconv.Atoi(employee.ID); err == nil {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+// sameTeam reports whether two employees share a manager, or a department if either has no
+// known manager
+func (c *orgChart) sameTeam(a, b string) bool {
+	managerA, okA := c.managers[a]
+	managerB, okB := c.managers[b]
+	if okA && okB {
+		return managerA == managerB
+	}
+
+	departmentA, departmentB := c.employees[a].Department, c.employees[b].Department
+	return departmentA != "" && strings.EqualFold(departmentA, departmentB)
+}
+
+// resolveEmployeeID parses an employee ID argument. "me" is the current employee, if configured.
+func resolveEmployeeID(value string, me int) (int, error) {
+	value = strings.TrimSpace(value)
+	if strings.EqualFold(value, "me") {
+		if me == 0 {
+			return 0, fmt.Errorf("'me' can't be used because the current employee is not configured (BAMBOOHR_EMPLOYEE_ID)")
+		}
+		return me, nil
+	}
+
+	id, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, fmt.Errorf("employeeId must be a valid integer or 'me'")
+	}
+	return id, nil
+}
+
+// registerOrgChartTools adds the org chart tools to the server
+func registerOrgChartTools(s *server.MCPServer, client BambooHR, me int) {
+	getManagerChainTool := mcp.NewTool(
+		"get_manager_chain",
+		mcp.WithDescription("Get an employee's manager, their manager and so on up to the top of the org chart"),
+		mcp.WithOutputSchema[OrgChartResult](),
+		mcp.WithTitleAnnotation("Get Manager Chain"),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithIdempotentHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(true),
+		mcp.WithString("employeeId",
+			mcp.Required(),
+			mcp.Description("The ID of the employee, or 'me' for the current employee"),
+		),
+		mcp.WithNumber("maxDepth",
+			mcp.Description(fmt.Sprintf("Maximum number of managers to return. Optional, defaults to %d.", maxOrgDepth)),
+			mcp.Min(1),
+			mcp.Max(maxOrgDepth),
+		),
+		mcp.WithString("format",
+			mcp.Description("Response format: 'compact' (default) for a short line per item, 'full' for the complete JSON and structured content, 'markdown' for a table or 'csv'"),
+			mcp.Enum(formats...),
+		),
+	)
+
+	getDirectReportsTool := mcp.NewTool(
+		"get_direct_reports",
+		mcp.WithDescription("Get the employees who report directly to a manager"),
+		mcp.WithOutputSchema[OrgChartResult](),
+		mcp.WithTitleAnnotation("Get Direct Reports"),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithIdempotentHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(true),
+		mcp.WithString("employeeId",
+			mcp.Required(),
+			mcp.Description("The ID of the manager, or 'me' for the current employee"),
+		),
+		mcp.WithString("format",
+			mcp.Description("Response format: 'compact' (default) for a short line per item, 'full' for the complete JSON and structured content, 'markdown' for a table or 'csv'"),
+			mcp.Enum(formats...),
+		),
+	)
+
+	getOrgSubtreeTool := mcp.NewTool(
+		"get_org_subtree",
+		mcp.WithDescription("Get everyone reporting to a manager, directly or through other managers, as an org chart"),
+		mcp.WithOutputSchema[OrgChartResult](),
+		mcp.WithTitleAnnotation("Get Org Subtree"),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithIdempotentHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(true),
+		mcp.WithString("employeeId",
+			mcp.Required(),
+			mcp.Description("The ID of the manager at the top of the subtree, or 'me' for the current employee"),
+		),
+		mcp.WithNumber("maxDepth",
+			mcp.Description(fmt.Sprintf("Number of reporting levels to include. Optional, defaults to 3, at most %d.", maxOrgDepth)),
+			mcp.Min(1),
+			mcp.Max(maxOrgDepth),
+		),
+		mcp.WithString("format",
+			mcp.Description("Response format: 'compact' (default) for a short line per item, 'full' for the complete JSON and structured content, 'markdown' for a table or 'csv'"),
+			mcp.Enum(formats...),
+		),
+	)
+
+	s.AddTool(getManagerChainTool, handleOrgChart(client, me, maxOrgDepth, (*orgChart).managerChain))
+	s.AddTool(getDirectReportsTool, handleOrgChart(client, me, 1, (*orgChart).subtree))
+	s.AddTool(getOrgSubtreeTool, handleOrgChart(client, me, 3, (*orgChart).subtree))
+}
+
+// handleOrgChart returns a handler that walks the org chart from the employee with walk, up to
+// the requested depth or the default depth
+func handleOrgChart(client BambooHR, me int, defaultDepth int, walk func(*orgChart, string, int) []OrgChartEmployee) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		employeeIDStr, err := request.RequireString("employeeId")
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("employeeId is required: %s", err.Error())), nil
+		}
+
+		employeeID, err := resolveEmployeeID(employeeIDStr, me)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		depth := request.GetInt("maxDepth", defaultDepth)
+		if depth < 1 || depth > maxOrgDepth {
+			return mcp.NewToolResultError(fmt.Sprintf("maxDepth must be between 1 and %d", maxOrgDepth)), nil
+		}
+
+		format, err := toolFormat(request, FormatCompact)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		directory, err := client.GetEmployeeDirectory()
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Failed to get employee directory: %s", err.Error())), nil
+		}
+
+		chart := newOrgChart(directory)
+		id := strconv.Itoa(employeeID)
+		if _, ok := chart.employees[id]; !ok {
+			return mcp.NewToolResultError(fmt.Sprintf("Employee %d is not in the employee directory", employeeID)), nil
+		}
+
+		result := OrgChartResult{
+			Employee:  chart.employee(id, 0),
+			MaxDepth:  depth,
+			Employees: walk(chart, id, depth),
+		}
+		if result.Employees == nil {
+			result.Employees = []OrgChartEmployee{}
+		}
+
+		return renderToolResult(result, orgChartView(request.Params.Name, result), format), nil
+	}
+}
+
+// orgChartView shows the result of an org chart tool as text, indenting reports by level
+func orgChartView(tool string, result OrgChartResult) textView {
+	name := fmt.Sprintf("%s (ID %s)", result.Employee.DisplayName, result.Employee.ID)
+
+	var title string
+	switch tool {
+	case "get_manager_chain":
+		title = fmt.Sprintf("%s has %s above them", name, pluralize(len(result.Employees), "manager", "managers"))
+	case "get_direct_reports":
+		title = fmt.Sprintf("%s has %s", name, pluralize(len(result.Employees), "direct report", "direct reports"))
+	default:
+		title = fmt.Sprintf("%s has %s within %s", name,
+			pluralize(len(result.Employees), "report", "reports"), pluralize(result.MaxDepth, "level", "levels"))
+	}
+
+	view := textView{
+		Title:   title,
+		Columns: []string{"Level", "ID", "Name", "Job Title", "Department", "Manager ID"},
+	}
+
+	names := map[string]string{result.Employee.ID: result.Employee.DisplayName}
+	for _, employee := range result.Employees {
+		names[employee.ID] = employee.DisplayName
+	}
+
+	for _, employee := range result.Employees {
+		item := fmt.Sprintf("%s (ID %s)", employee.DisplayName, employee.ID)
+		if details := joinNonEmpty(", ", employee.JobTitle, employee.Department); details != "" {
+			item += ", " + details
+		}
+		if tool == "get_org_subtree" && employee.Level > 1 {
+			item += ", reports to " + names[employee.ManagerID]
+		}
+
+		view.Items = append(view.Items, item)
+		view.Rows = append(view.Rows, []string{
+			strconv.Itoa(employee.Level),
+			employee.ID,
+			employee.DisplayName,
+			employee.JobTitle,
+			employee.Department,
+			employee.ManagerID,
+		})
+	}
+
+	return view
+}
@@ -0,0 +1,140 @@
+package mcpserver
+
+import (
+	"encoding/json"
+	"testing"
+
+	"bamboohr-mcp-server/bamboohr"
+)
+
+// orgChartTestEmployees is a small company: Ava runs it, Ben and Cleo report to her, and Dan
+// and Eve report to Ben. Managers are identified in each of the ways BambooHR may list them.
+func orgChartTestEmployees() []bamboohr.Employee {
+	return []bamboohr.Employee{
+		{ID: "1", EmployeeNumber: "E1", DisplayName: "Ava Adams", FirstName: "Ava", LastName: "Adams", JobTitle: "CEO"},
+		{ID: "2", EmployeeNumber: "E2", DisplayName: "Ben Brown", FirstName: "Ben", LastName: "Brown", JobTitle: "CTO", Department: "Engineering", SupervisorEID: "1"},
+		{ID: "3", EmployeeNumber: "E3", DisplayName: "Cleo Cruz", JobTitle: "CFO", Department: "Finance", SupervisorID: "E1"},
+		{ID: "4", EmployeeNumber: "E4", DisplayName: "Dan Diaz", JobTitle: "Engineer", Department: "Engineering", Supervisor: "Ben Brown"},
+		{ID: "5", EmployeeNumber: "E5", DisplayName: "Eve Evans", JobTitle: "Engineer", Department: "Engineering", Supervisor: "Brown, Ben"},
+	}
+}
+
+func TestOrgChart(t *testing.T) {
+	chart := newOrgChart(&bamboohr.EmployeeDirectory{Employees: orgChartTestEmployees()})
+
+	ids := func(employees []OrgChartEmployee) []string {
+		var ids []string
+		for _, employee := range employees {
+			ids = append(ids, employee.ID)
+		}
+		return ids
+	}
+
+	tests := []struct {
+		name     string
+		got      []OrgChartEmployee
+		expected []string
+	}{
+		{"manager chain", chart.managerChain("5", maxOrgDepth), []string{"2", "1"}},
+		{"manager chain depth", chart.managerChain("5", 1), []string{"2"}},
+		{"top of chain", chart.managerChain("1", maxOrgDepth), nil},
+		{"direct reports", chart.subtree("1", 1), []string{"2", "3"}},
+		{"subtree", chart.subtree("1", maxOrgDepth), []string{"2", "4", "5", "3"}},
+		{"no reports", chart.subtree("4", maxOrgDepth), nil},
+	}
+
+	for _, tt := range tests {
+		if got := ids(tt.got); len(got) != len(tt.expected) || (len(got) > 0 && fmtIDs(got) != fmtIDs(tt.expected)) {
+			t.Errorf("%s: expected %v, got %v", tt.name, tt.expected, got)
+		}
+	}
+
+	subtree := chart.subtree("1", maxOrgDepth)
+	if subtree[1].Level != 2 || subtree[1].ManagerID != "2" {
+		t.Errorf("Expected Dan at level 2 under Ben, got %+v", subtree[1])
+	}
+}
+
+// fmtIDs joins IDs for comparison
+func fmtIDs(ids []string) string {
+	data, _ := json.Marshal(ids)
+	return string(data)
+}
+
+func TestOrgChart_AmbiguousAndCyclicSupervisors(t *testing.T) {
+	chart := newOrgChart(&bamboohr.EmployeeDirectory{Employees: []bamboohr.Employee{
+		{ID: "1", DisplayName: "Sam Smith"},
+		{ID: "2", DisplayName: "Sam Smith"},
+		{ID: "3", DisplayName: "Ray Reed", Supervisor: "Sam Smith"},
+		{ID: "4", DisplayName: "Kim Kent", SupervisorEID: "5"},
+		{ID: "5", DisplayName: "Lee Lane", SupervisorEID: "4"},
+	}})
+
+	if _, ok := chart.managers["3"]; ok {
+		t.Error("Expected an ambiguous supervisor name not to be resolved")
+	}
+
+	if chain := chart.managerChain("4", maxOrgDepth); len(chain) != 1 || chain[0].ID != "5" {
+		t.Errorf("Expected the cycle to stop after Lee, got %+v", chain)
+	}
+
+	if subtree := chart.subtree("4", maxOrgDepth); len(subtree) != 1 {
+		t.Errorf("Expected the cycle to stop after Lee, got %+v", subtree)
+	}
+}
+
+func TestOrgChartTools(t *testing.T) {
+	fake := newFakeBambooHR(orgChartTestEmployees()...)
+	s := New(fake, newTestDateParser(), WithCurrentEmployee(2))
+
+	text, isError := callTool(t, s, "get_manager_chain", map[string]string{"employeeId": "4"})
+	if isError || text != "Dan Diaz (ID 4) has 2 managers above them:\n- Ben Brown (ID 2), CTO, Engineering\n- Ava Adams (ID 1), CEO" {
+		t.Errorf("Unexpected manager chain: %q", text)
+	}
+
+	text, isError = callTool(t, s, "get_direct_reports", map[string]string{"employeeId": "me"})
+	if isError || text != "Ben Brown (ID 2) has 2 direct reports:\n- Dan Diaz (ID 4), Engineer, Engineering\n- Eve Evans (ID 5), Engineer, Engineering" {
+		t.Errorf("Unexpected direct reports: %q", text)
+	}
+
+	text, isError = callTool(t, s, "get_org_subtree", map[string]string{"employeeId": "1", "format": "csv"})
+	expected := "Level,ID,Name,Job Title,Department,Manager ID\n1,2,Ben Brown,CTO,Engineering,1\n2,4,Dan Diaz,Engineer,Engineering,2\n2,5,Eve Evans,Engineer,Engineering,2\n1,3,Cleo Cruz,CFO,Finance,1\n"
+	if isError || text != expected {
+		t.Errorf("Expected %q, got %q", expected, text)
+	}
+
+	text, _ = callTool(t, s, "get_org_subtree", map[string]string{"employeeId": "1"})
+	if text != "Ava Adams (ID 1) has 4 reports within 3 levels:\n- Ben Brown (ID 2), CTO, Engineering\n- Dan Diaz (ID 4), Engineer, Engineering, reports to Ben Brown\n- Eve Evans (ID 5), Engineer, Engineering, reports to Ben Brown\n- Cleo Cruz (ID 3), CFO, Finance" {
+		t.Errorf("Unexpected subtree: %q", text)
+	}
+
+	errorCases := []map[string]string{
+		{"employeeId": "99"},
+		{"employeeId": "abc"},
+		{"employeeId": "1", "maxDepth": "0"},
+	}
+	for _, arguments := range errorCases {
+		if _, isError := callTool(t, s, "get_org_subtree", arguments); !isError {
+			t.Errorf("Expected tool error for %v", arguments)
+		}
+	}
+
+	// Without a current employee, "me" can't be resolved
+	s = New(fake, newTestDateParser())
+	if text, isError := callTool(t, s, "get_direct_reports", map[string]string{"employeeId": "me"}); !isError {
+		t.Errorf("Expected tool error for 'me' without a current employee, got %q", text)
+	}
+}
+
+func TestTeamEmployeeIDs_Manager(t *testing.T) {
+	fake := newFakeBambooHR(orgChartTestEmployees()...)
+
+	ids, err := teamEmployeeIDs(fake, "", "", 2)
+	if err != nil || len(ids) != 2 || ids[0] != 4 || ids[1] != 5 {
+		t.Errorf("Expected Ben's reports 4 and 5, got %v (%v)", ids, err)
+	}
+
+	if _, err := teamEmployeeIDs(fake, "", "", 4); err == nil {
+		t.Error("Expected error for a manager without reports")
+	}
+}
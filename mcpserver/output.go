@@ -0,0 +1,183 @@
+package mcpserver
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"bamboohr-mcp-server/bamboohr"
+)
+
+// TimeOffRequestsResult is the structured output of get_time_off_requests
+type TimeOffRequestsResult struct {
+	EmployeeID string                    `json:"employeeId" jsonschema:"description=The employee the requests belong to"`
+	Start      string                    `json:"start" jsonschema:"description=Start of the period searched (YYYY-MM-DD)"`
+	End        string                    `json:"end" jsonschema:"description=End of the period searched (YYYY-MM-DD)"`
+	Requests   []bamboohr.TimeOffRequest `json:"requests"`
+	WorkDay    WorkDay                   `json:"workDay" jsonschema:"description=The working day used to show amounts in both days and hours"`
+}
+
+// TimeOffBalanceResult is the structured output of get_time_off_balance
+type TimeOffBalanceResult struct {
+	EmployeeID string                    `json:"employeeId" jsonschema:"description=The employee the balances belong to"`
+	Balances   []bamboohr.TimeOffBalance `json:"balances"`
+	WorkDay    WorkDay                   `json:"workDay" jsonschema:"description=The working day used to show balances in both days and hours"`
+}
+
+// CreatedTimeOffRequest is the structured output of create_time_off_request, and of
+// update_time_off_request with the request it supersedes
+type CreatedTimeOffRequest struct {
+	bamboohr.TimeOffRequest
+	Duration TimeOffAmount `json:"duration" jsonschema:"description=The amount of the request in both days and hours"`
+	WorkDay  WorkDay       `json:"workDay"`
+}
+
+// timeOffRequestsView shows an employee's time-off requests as text
+func timeOffRequestsView(result TimeOffRequestsResult, locale Locale) textView {
+	view := timeOffRequestTable(result.Requests, locale, result.WorkDay)
+	view.Title = fmt.Sprintf("Employee %s has %s between %s and %s", result.EmployeeID,
+		pluralize(len(result.Requests), "time-off request", "time-off requests"),
+		locale.FormatDate(result.Start), locale.FormatDate(result.End))
+	return view
+}
+
+// createdTimeOffRequestView shows a newly created time-off request as text
+func createdTimeOffRequestView(employeeID int, result CreatedTimeOffRequest, locale Locale) textView {
+	view := timeOffRequestTable([]bamboohr.TimeOffRequest{result.TimeOffRequest}, locale, result.WorkDay)
+	view.Title = fmt.Sprintf("Created time-off request for employee %d", employeeID)
+	return view
+}
+
+// timeOffRequestTable lists time-off requests as compact lines and table rows, with their amounts
+// in both days and hours
+func timeOffRequestTable(requests []bamboohr.TimeOffRequest, locale Locale, workDay WorkDay) textView {
+	view := textView{Columns: []string{"ID", "Start", "End", "Type", "Amount", "Unit", "Days", "Hours", "Status"}}
+	for _, request := range requests {
+		duration := workDay.amount(float64(request.Amount.Amount), request.Amount.Unit)
+		view.Items = append(view.Items, summarizeTimeOffRequest(request, locale, workDay))
+		view.Rows = append(view.Rows, []string{
+			request.ID,
+			locale.FormatDate(request.Start),
+			locale.FormatDate(request.End),
+			request.Type.Name,
+			strconv.FormatFloat(float64(request.Amount.Amount), 'f', -1, 64),
+			request.Amount.Unit,
+			strconv.FormatFloat(duration.Days, 'f', -1, 64),
+			strconv.FormatFloat(duration.Hours, 'f', -1, 64),
+			request.Status.Status,
+		})
+	}
+	return view
+}
+
+// summarizeTimeOffRequest returns a one-line summary of a time-off request
+func summarizeTimeOffRequest(request bamboohr.TimeOffRequest, locale Locale, workDay WorkDay) string {
+	dates := locale.FormatDate(request.Start)
+	if request.End != "" && request.End != request.Start {
+		dates += " to " + locale.FormatDate(request.End)
+	}
+
+	summary := fmt.Sprintf("%s: %s, %s", dates, request.Type.Name,
+		workDay.format(float64(request.Amount.Amount), request.Amount.Unit))
+	if request.Status.Status != "" {
+		summary += fmt.Sprintf(" (%s)", request.Status.Status)
+	}
+	if request.ID != "" {
+		summary += fmt.Sprintf(" [request %s]", request.ID)
+	}
+
+	return summary
+}
+
+// timeOffBalancesView shows an employee's time-off balances as text
+func timeOffBalancesView(result TimeOffBalanceResult, locale Locale) textView {
+	view := textView{
+		Title:   fmt.Sprintf("Employee %s has %s", result.EmployeeID, pluralize(len(result.Balances), "time-off balance", "time-off balances")),
+		Columns: []string{"Type ID", "Type", "Balance", "Used This Year", "Units", "Balance Days", "Balance Hours", "As Of", "Policy Type"},
+	}
+
+	for _, balance := range result.Balances {
+		available := result.WorkDay.amount(float64(balance.Balance), balance.Units)
+		item := fmt.Sprintf("%s: %s available, %s used this year", balance.Name,
+			result.WorkDay.format(float64(balance.Balance), balance.Units),
+			result.WorkDay.format(float64(balance.UsedYearToDate), balance.Units))
+		if balance.End != "" {
+			item += fmt.Sprintf(" (as of %s)", locale.FormatDate(balance.End))
+		}
+
+		view.Items = append(view.Items, item)
+		view.Rows = append(view.Rows, []string{
+			balance.TimeOffType,
+			balance.Name,
+			strconv.FormatFloat(float64(balance.Balance), 'f', -1, 64),
+			strconv.FormatFloat(float64(balance.UsedYearToDate), 'f', -1, 64),
+			balance.Units,
+			strconv.FormatFloat(available.Days, 'f', -1, 64),
+			strconv.FormatFloat(available.Hours, 'f', -1, 64),
+			locale.FormatDate(balance.End),
+			balance.PolicyType,
+		})
+	}
+
+	return view
+}
+
+// employeeDirectoryView shows the employee directory as text
+func employeeDirectoryView(directory *bamboohr.EmployeeDirectory) textView {
+	view := textView{
+		Title:   fmt.Sprintf("The directory lists %s", pluralize(len(directory.Employees), "employee", "employees")),
+		Columns: []string{"ID", "Name", "Job Title", "Department", "Location", "Work Email"},
+	}
+
+	for _, employee := range directory.Employees {
+		item := fmt.Sprintf("%s (ID %s)", employee.DisplayName, employee.ID)
+		if details := joinNonEmpty(", ", employee.JobTitle, employee.Department); details != "" {
+			item += ", " + details
+		}
+
+		view.Items = append(view.Items, item)
+		view.Rows = append(view.Rows, []string{
+			employee.ID,
+			employee.DisplayName,
+			employee.JobTitle,
+			employee.Department,
+			employee.Location,
+			employee.WorkEmail,
+		})
+	}
+
+	return view
+}
+
+// formatAmount formats an amount of time off with its unit, e.g. "1 day" or "2.5 days"
+func formatAmount(amount float64, unit string) string {
+	value := strconv.FormatFloat(amount, 'f', -1, 64)
+	if unit == "" {
+		return value
+	}
+
+	if amount == 1 {
+		unit = strings.TrimSuffix(unit, "s")
+	}
+
+	return value + " " + unit
+}
+
+// pluralize returns the count with the singular or plural noun
+func pluralize(count int, singular, plural string) string {
+	if count == 1 {
+		return "1 " + singular
+	}
+	return fmt.Sprintf("%d %s", count, plural)
+}
+
+// joinNonEmpty joins the non-empty values with the separator
+func joinNonEmpty(separator string, values ...string) string {
+	var parts []string
+	for _, value := range values {
+		if value != "" {
+			parts = append(parts, value)
+		}
+	}
+	return strings.Join(parts, separator)
+}
@@ -0,0 +1,140 @@
+package mcpserver
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"bamboohr-mcp-server/bamboohr"
+)
+
+func TestTools_OutputSchemas(t *testing.T) {
+	s := New(bamboohr.NewClient("testcompany", "testkey"), newTestDateParser())
+
+	var list struct {
+		Tools []struct {
+			Name         string                     `json:"name"`
+			OutputSchema map[string]json.RawMessage `json:"outputSchema"`
+		} `json:"tools"`
+	}
+	if err := json.Unmarshal(sendMessage(t, s, "tools/list", map[string]string{}), &list); err != nil {
+		t.Fatalf("Failed to unmarshal tools: %v", err)
+	}
+
+	if len(list.Tools) == 0 {
+		t.Fatal("Expected tools to be registered")
+	}
+
+	for _, tool := range list.Tools {
+		if string(tool.OutputSchema["type"]) != `"object"` {
+			t.Errorf("Expected tool %s to declare an object output schema, got %v", tool.Name, tool.OutputSchema)
+		}
+	}
+}
+
+func TestFlexibleNotes_JSONSchema(t *testing.T) {
+	tool := mcp.NewTool("test", mcp.WithOutputSchema[bamboohr.TimeOffRequest]())
+
+	data, err := json.Marshal(tool.OutputSchema.Properties["notes"])
+	if err != nil {
+		t.Fatalf("Failed to marshal schema: %v", err)
+	}
+
+	if !strings.Contains(string(data), `"type":"array"`) || !strings.Contains(string(data), `"from"`) {
+		t.Errorf("Expected notes to be described as an array of notes, got %s", data)
+	}
+
+	empty, err := json.Marshal(bamboohr.FlexibleNotes{})
+	if err != nil {
+		t.Fatalf("Failed to marshal: %v", err)
+	}
+
+	if string(empty) != "[]" {
+		t.Errorf("Expected empty notes to marshal as [], got %s", empty)
+	}
+}
+
+func TestHandleGetTimeOffBalance_StructuredContent(t *testing.T) {
+	_, client := newMockBambooHR(t, map[string]string{
+		"/employees/157/time_off/calculator": `[
+			{"timeOffType": "1", "name": "Vacation", "units": "days", "balance": "12.5", "end": "2025-12-31", "policyType": "accruing", "usedYearToDate": "1"}
+		]`,
+	})
+	s := New(client, newTestDateParser())
+
+	call := func(format string) (content []mcp.TextContent, structured *TimeOffBalanceResult) {
+		var result struct {
+			Content           []mcp.TextContent     `json:"content"`
+			StructuredContent *TimeOffBalanceResult `json:"structuredContent"`
+		}
+		response := sendMessage(t, s, "tools/call", map[string]interface{}{
+			"name":      "get_time_off_balance",
+			"arguments": map[string]string{"employeeId": "157", "format": format},
+		})
+		if err := json.Unmarshal(response, &result); err != nil {
+			t.Fatalf("Failed to unmarshal result: %v", err)
+		}
+		return result.Content, result.StructuredContent
+	}
+
+	_, structured := call("full")
+	if structured == nil || structured.EmployeeID != "157" || len(structured.Balances) != 1 || float64(structured.Balances[0].Balance) != 12.5 {
+		t.Errorf("Expected structured balance of 12.5 days for employee 157, got %+v", structured)
+	}
+
+	// The compact format is only the human-readable summary
+	content, structured := call("compact")
+	if len(content) != 1 || !strings.Contains(content[0].Text, "Vacation: 12.5 days / 100 hours available, 1 day / 8 hours used this year") {
+		t.Errorf("Expected a human-readable summary, got %+v", content)
+	}
+	if structured != nil {
+		t.Errorf("Expected no structured content in the compact format, got %+v", structured)
+	}
+}
+
+func TestTimeOffRequestsView(t *testing.T) {
+	var request bamboohr.TimeOffRequest
+	request.ID = "1"
+	request.Start = "2025-12-23"
+	request.End = "2026-01-02"
+	request.Type.Name = "Vacation"
+	request.Amount.Unit = "days"
+	request.Amount.Amount = 5
+	request.Status.Status = "approved"
+
+	result := TimeOffRequestsResult{EmployeeID: "157", Start: "2025-01-01", End: "2025-12-31", Requests: []bamboohr.TimeOffRequest{request}}
+	locale, _ := ParseLocale("en-US")
+
+	expected := "Employee 157 has 1 time-off request between 01/01/2025 and 12/31/2025:\n" +
+		"- 12/23/2025 to 01/02/2026: Vacation, 5 days (approved) [request 1]"
+	if summary := renderCompact(timeOffRequestsView(result, locale)); summary != expected {
+		t.Errorf("Expected:\n%s\ngot:\n%s", expected, summary)
+	}
+
+	result.Requests = nil
+	if summary := renderCompact(timeOffRequestsView(result, ISOLocale)); summary != "Employee 157 has 0 time-off requests between 2025-01-01 and 2025-12-31." {
+		t.Errorf("Unexpected summary for no requests: %s", summary)
+	}
+}
+
+func TestFormatAmount(t *testing.T) {
+	tests := []struct {
+		amount   float64
+		unit     string
+		expected string
+	}{
+		{1, "days", "1 day"},
+		{2.5, "days", "2.5 days"},
+		{8, "hours", "8 hours"},
+		{0, "days", "0 days"},
+		{3, "", "3"},
+	}
+
+	for _, tt := range tests {
+		if formatted := formatAmount(tt.amount, tt.unit); formatted != tt.expected {
+			t.Errorf("formatAmount(%v, %q): expected %q, got %q", tt.amount, tt.unit, tt.expected, formatted)
+		}
+	}
+}
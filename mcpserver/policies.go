@@ -0,0 +1,740 @@
+package mcpserver
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+
+	"bamboohr-mcp-server/bamboohr"
+)
+
+// maxSimulationMonths caps the months simulate_time_off_balance projects
+const maxSimulationMonths = 24
+
+// policyTypeDescriptions explains the BambooHR policy types
+var policyTypeDescriptions = map[string]string{
+	"accruing":      "Time off accrues over time on the policy's accrual schedule",
+	"manual":        "The balance is only changed by adjustments made in BambooHR",
+	"discretionary": "No balance is tracked, time off is granted at the approver's discretion",
+}
+
+// TimeOffPolicyInfo is a time-off policy with its time off type
+type TimeOffPolicyInfo struct {
+	ID              string `json:"id"`
+	Name            string `json:"name"`
+	Type            string `json:"type" jsonschema:"description=accruing, manual or discretionary"`
+	Description     string `json:"description,omitempty" jsonschema:"description=What the policy type means for the balance"`
+	TimeOffTypeID   string `json:"timeOffTypeId"`
+	TimeOffTypeName string `json:"timeOffTypeName,omitempty"`
+	Units           string `json:"units,omitempty"`
+	EffectiveDate   string `json:"effectiveDate,omitempty"`
+}
+
+// TimeOffPoliciesResult is the structured output of list_time_off_policies
+type TimeOffPoliciesResult struct {
+	Policies []TimeOffPolicyInfo `json:"policies"`
+}
+
+// EmployeePolicyInfo is a time-off policy assigned to an employee, with their balance of its type
+type EmployeePolicyInfo struct {
+	TimeOffPolicyInfo
+	AccrualStartDate string   `json:"accrualStartDate,omitempty"`
+	Balance          *float64 `json:"balance,omitempty" jsonschema:"description=The employee's current balance of the policy's time off type"`
+}
+
+// EmployeePoliciesResult is the structured output of get_employee_time_off_policies
+type EmployeePoliciesResult struct {
+	EmployeeID string               `json:"employeeId"`
+	Policies   []EmployeePolicyInfo `json:"policies"`
+}
+
+// SimulatedMonth is one month of a balance simulation
+type SimulatedMonth struct {
+	Month        string  `json:"month" jsonschema:"description=The month (YYYY-MM)"`
+	StartBalance float64 `json:"startBalance"`
+	Scheduled    float64 `json:"scheduled" jsonschema:"description=Time off already booked in BambooHR taken this month"`
+	Planned      float64 `json:"planned" jsonschema:"description=Planned time off taken this month"`
+	Accrued      float64 `json:"accrued"`
+	CappedLoss   float64 `json:"cappedLoss" jsonschema:"description=Accrual lost because the balance reached the accrual cap"`
+	Forfeited    float64 `json:"forfeited" jsonschema:"description=Balance above the carry-over limit forfeited at the policy year end"`
+	EndBalance   float64 `json:"endBalance"`
+}
+
+// BalanceSimulationResult is the structured output of simulate_time_off_balance
+type BalanceSimulationResult struct {
+	EmployeeID       string           `json:"employeeId"`
+	TimeOffTypeID    string           `json:"timeOffTypeId"`
+	TimeOffTypeName  string           `json:"timeOffTypeName"`
+	Units            string           `json:"units"`
+	AsOf             string           `json:"asOf" jsonschema:"description=The date of the starting balance"`
+	StartBalance     float64          `json:"startBalance"`
+	AccrualPerMonth  float64          `json:"accrualPerMonth"`
+	AccrualCap       float64          `json:"accrualCap,omitempty"`
+	CarryOverLimit   *float64         `json:"carryOverLimit,omitempty" jsonschema:"description=Most of the balance carried into the next policy year, absent for no limit"`
+	PolicyYearEnd    string           `json:"policyYearEnd" jsonschema:"description=Month and day (MM-DD) the policy year ends"`
+	IncludeRequested bool             `json:"includeRequested" jsonschema:"description=Whether time off waiting for approval was deducted"`
+	Months           []SimulatedMonth `json:"months"`
+	TotalAccrued     float64          `json:"totalAccrued"`
+	TotalUsed        float64          `json:"totalUsed"`
+	TotalCappedLoss  float64          `json:"totalCappedLoss"`
+	TotalForfeited   float64          `json:"totalForfeited"`
+	EndBalance       float64          `json:"endBalance"`
+	LowestBalance    float64          `json:"lowestBalance" jsonschema:"description=The lowest month-end balance, negative if the plan overdraws the balance"`
+}
+
+// balanceSimulation are the rules and plans a balance is projected with
+type balanceSimulation struct {
+	today           time.Time
+	months          int
+	accrualPerMonth float64
+	accrualCap      float64
+	carryOverLimit  *float64
+	policyYearEnd   string
+	planned         map[string]float64
+}
+
+func registerPolicyTools(s *server.MCPServer, client BambooHR, dates *DateParser, me int) {
+	listTimeOffPoliciesTool := mcp.NewTool(
+		"list_time_off_policies",
+		mcp.WithDescription("List the company's time-off policies with their time off type and what the policy type means for balances"),
+		mcp.WithOutputSchema[TimeOffPoliciesResult](),
+		mcp.WithTitleAnnotation("List Time-Off Policies"),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithIdempotentHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(true),
+		mcp.WithString("format",
+			mcp.Description("Response format: 'compact' (default) for a short line per policy, 'full' for the complete JSON and structured content, 'markdown' for a table or 'csv'"),
+			mcp.Enum(formats...),
+		),
+	)
+
+	getEmployeeTimeOffPoliciesTool := mcp.NewTool(
+		"get_employee_time_off_policies",
+		mcp.WithDescription("Get the time-off policies assigned to an employee, with their accrual start date and current balance"),
+		mcp.WithOutputSchema[EmployeePoliciesResult](),
+		mcp.WithTitleAnnotation("Get Employee Time-Off Policies"),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithIdempotentHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(true),
+		mcp.WithString("employeeId",
+			mcp.Required(),
+			mcp.Description("The ID of the employee, or 'me' for the current employee"),
+		),
+		mcp.WithString("format",
+			mcp.Description("Response format: 'compact' (default) for a short line per policy, 'full' for the complete JSON and structured content, 'markdown' for a table or 'csv'"),
+			mcp.Enum(formats...),
+		),
+	)
+
+	simulateTimeOffBalanceTool := mcp.NewTool(
+		"simulate_time_off_balance",
+		mcp.WithDescription("Project an employee's balance of a time off type month by month from their current balance, given an accrual rate, accrual cap, carry-over limit and planned time off. Time off already booked in BambooHR is deducted in the month it is taken."),
+		mcp.WithOutputSchema[BalanceSimulationResult](),
+		mcp.WithTitleAnnotation("Simulate Time-Off Balance"),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithIdempotentHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(true),
+		mcp.WithString("employeeId",
+			mcp.Required(),
+			mcp.Description("The ID of the employee, or 'me' for the current employee"),
+		),
+		mcp.WithString("timeOffType",
+			mcp.Required(),
+			mcp.Description("The time off type ID or name, for example 'Vacation'"),
+		),
+		mcp.WithNumber("accrualPerMonth",
+			mcp.Description("Time off accrued at the end of each month, in the balance's units. Optional, defaults to 0."),
+			mcp.Min(0),
+		),
+		mcp.WithNumber("accrualCap",
+			mcp.Description("Most time off the balance can hold. Accrual above it is lost. Optional, defaults to no cap."),
+			mcp.Min(0),
+		),
+		mcp.WithNumber("carryOverLimit",
+			mcp.Description("Most of the balance carried into the next policy year. The rest is forfeited at the policy year end. Optional, defaults to no limit."),
+			mcp.Min(0),
+		),
+		mcp.WithString("policyYearEnd",
+			mcp.Description("Month and day (MM-DD) the policy year ends. Optional, defaults to '12-31'."),
+		),
+		mcp.WithString("planned",
+			mcp.Description("Planned time off as semicolon-separated date:amount pairs in the balance's units, for example '2025-12-22:3; 2026-04-10:1'. Optional."),
+		),
+		mcp.WithNumber("months",
+			mcp.Description(fmt.Sprintf("Number of months to project, starting with the current month. Optional, defaults to 12, at most %d.", maxSimulationMonths)),
+			mcp.Min(1),
+			mcp.Max(maxSimulationMonths),
+		),
+		mcp.WithBoolean("includeRequested",
+			mcp.Description("Also deduct time off waiting for approval. Optional, defaults to false."),
+		),
+		mcp.WithString("locale",
+			mcp.Description(localeArgumentDescription),
+		),
+		mcp.WithString("format",
+			mcp.Description("Response format: 'compact' (default) for a short line per month, 'full' for the complete JSON and structured content, 'markdown' for a table or 'csv'"),
+			mcp.Enum(formats...),
+		),
+	)
+
+	s.AddTool(listTimeOffPoliciesTool, handleListTimeOffPolicies(client))
+	s.AddTool(getEmployeeTimeOffPoliciesTool, handleGetEmployeeTimeOffPolicies(client, me))
+	s.AddTool(simulateTimeOffBalanceTool, handleSimulateTimeOffBalance(client, dates, me))
+}
+
+func handleListTimeOffPolicies(client BambooHR) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		format, err := toolFormat(request, FormatCompact)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		policies, types, err := timeOffPolicies(client)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Failed to list time-off policies: %s", err.Error())), nil
+		}
+
+		result := TimeOffPoliciesResult{Policies: []TimeOffPolicyInfo{}}
+		for _, policy := range policies {
+			result.Policies = append(result.Policies, policyInfo(policy, types))
+		}
+		sort.SliceStable(result.Policies, func(i, j int) bool {
+			return result.Policies[i].Name < result.Policies[j].Name
+		})
+
+		return renderToolResult(result, timeOffPoliciesView(result), format), nil
+	}
+}
+
+func handleGetEmployeeTimeOffPolicies(client BambooHR, me int) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		employeeIDStr, err := request.RequireString("employeeId")
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("employeeId is required: %s", err.Error())), nil
+		}
+
+		employeeID, err := resolveEmployeeID(employeeIDStr, me)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		format, err := toolFormat(request, FormatCompact)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		result, err := employeeTimeOffPolicies(client, employeeID)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Failed to get employee time-off policies: %s", err.Error())), nil
+		}
+
+		return renderToolResult(result, employeePoliciesView(result), format), nil
+	}
+}
+
+// timeOffPolicies returns the company's policies by ID and time off types by ID
+func timeOffPolicies(client BambooHR) (map[string]bamboohr.TimeOffPolicy, map[string]bamboohr.TimeOffType, error) {
+	policies, err := client.GetTimeOffPolicies()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get time-off policies: %w", err)
+	}
+
+	types, err := client.GetTimeOffTypes()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get time off types: %w", err)
+	}
+
+	policiesByID := make(map[string]bamboohr.TimeOffPolicy, len(policies))
+	for _, policy := range policies {
+		policiesByID[policy.ID] = policy
+	}
+
+	typesByID := make(map[string]bamboohr.TimeOffType, len(types.TimeOffTypes))
+	for _, timeOffType := range types.TimeOffTypes {
+		typesByID[timeOffType.ID] = timeOffType
+	}
+
+	return policiesByID, typesByID, nil
+}
+
+// policyInfo describes the policy with its time off type
+func policyInfo(policy bamboohr.TimeOffPolicy, types map[string]bamboohr.TimeOffType) TimeOffPolicyInfo {
+	info := TimeOffPolicyInfo{
+		ID:            policy.ID,
+		Name:          policy.Name,
+		Type:          policy.Type,
+		Description:   policyTypeDescriptions[strings.ToLower(policy.Type)],
+		TimeOffTypeID: policy.TimeOffTypeID,
+		EffectiveDate: policy.EffectiveDate,
+	}
+	if timeOffType, ok := types[policy.TimeOffTypeID]; ok {
+		info.TimeOffTypeName = timeOffType.Name
+		info.Units = timeOffType.Units
+	}
+	return info
+}
+
+// employeeTimeOffPolicies returns the policies assigned to the employee, joined with the
+// company's policies, time off types and the employee's balances
+func employeeTimeOffPolicies(client BambooHR, employeeID int) (EmployeePoliciesResult, error) {
+	result := EmployeePoliciesResult{
+		EmployeeID: strconv.Itoa(employeeID),
+		Policies:   []EmployeePolicyInfo{},
+	}
+
+	assigned, err := client.GetEmployeeTimeOffPolicies(employeeID)
+	if err != nil {
+		return result, fmt.Errorf("failed to get the employee's time-off policies: %w", err)
+	}
+
+	policies, types, err := timeOffPolicies(client)
+	if err != nil {
+		return result, err
+	}
+
+	balances, err := client.GetTimeOffBalance(employeeID)
+	if err != nil {
+		return result, fmt.Errorf("failed to get time-off balance: %w", err)
+	}
+
+	for _, assignment := range assigned {
+		policy, ok := policies[assignment.TimeOffPolicyID.String()]
+		if !ok {
+			policy = bamboohr.TimeOffPolicy{ID: assignment.TimeOffPolicyID.String()}
+		}
+		if policy.TimeOffTypeID == "" {
+			policy.TimeOffTypeID = assignment.TimeOffTypeID.String()
+		}
+
+		info := EmployeePolicyInfo{
+			TimeOffPolicyInfo: policyInfo(policy, types),
+			AccrualStartDate:  assignment.AccrualStartDate,
+		}
+		for _, balance := range balances {
+			if balance.TimeOffType != info.TimeOffTypeID {
+				continue
+			}
+			amount := float64(balance.Balance)
+			info.Balance = &amount
+			if balance.Units != "" {
+				info.Units = balance.Units
+			}
+			if info.Type == "" {
+				info.Type = balance.PolicyType
+				info.Description = policyTypeDescriptions[strings.ToLower(balance.PolicyType)]
+			}
+			break
+		}
+
+		result.Policies = append(result.Policies, info)
+	}
+
+	return result, nil
+}
+
+func handleSimulateTimeOffBalance(client BambooHR, dates *DateParser, me int) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		employeeIDStr, err := request.RequireString("employeeId")
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("employeeId is required: %s", err.Error())), nil
+		}
+
+		employeeID, err := resolveEmployeeID(employeeIDStr, me)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		timeOffType, err := request.RequireString("timeOffType")
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("timeOffType is required: %s", err.Error())), nil
+		}
+
+		format, err := toolFormat(request, FormatCompact)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		locale, err := toolLocale(request, dates)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		parser := dates.ForEmployee(employeeID)
+		simulation := balanceSimulation{
+			today:           parser.Today(),
+			months:          request.GetInt("months", 12),
+			accrualPerMonth: request.GetFloat("accrualPerMonth", 0),
+			accrualCap:      request.GetFloat("accrualCap", 0),
+			policyYearEnd:   request.GetString("policyYearEnd", "12-31"),
+		}
+		if simulation.months < 1 || simulation.months > maxSimulationMonths {
+			return mcp.NewToolResultError(fmt.Sprintf("months must be between 1 and %d", maxSimulationMonths)), nil
+		}
+		if simulation.accrualPerMonth < 0 || simulation.accrualCap < 0 {
+			return mcp.NewToolResultError("accrualPerMonth and accrualCap must not be negative"), nil
+		}
+		if _, err := time.Parse("01-02", simulation.policyYearEnd); err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("policyYearEnd must be a month and day in MM-DD format, got %q", simulation.policyYearEnd)), nil
+		}
+		if limit := request.GetFloat("carryOverLimit", -1); limit >= 0 {
+			simulation.carryOverLimit = &limit
+		}
+
+		simulation.planned, err = parsePlannedTimeOff(parser, request.GetString("planned", ""))
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		typeIDs, err := resolveTimeOffTypeIDs(client, timeOffType)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		if len(typeIDs) != 1 {
+			return mcp.NewToolResultError("timeOffType must name a single time off type"), nil
+		}
+
+		result, err := simulateTimeOffBalance(client, employeeID, typeIDs[0], request.GetBool("includeRequested", false), simulation)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Failed to simulate time-off balance: %s", err.Error())), nil
+		}
+
+		return renderToolResult(result, balanceSimulationView(result, locale), format), nil
+	}
+}
+
+// parsePlannedTimeOff parses semicolon-separated date:amount pairs into amounts by day
+// (YYYY-MM-DD)
+func parsePlannedTimeOff(dates *DateParser, value string) (map[string]float64, error) {
+	planned := make(map[string]float64)
+
+	for _, entry := range strings.Split(value, ";") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		separator := strings.LastIndex(entry, ":")
+		if separator < 0 {
+			return nil, fmt.Errorf("planned time off %q must be a date and amount, like '2025-12-22:3'", entry)
+		}
+
+		date, err := dates.ParseDate(entry[:separator])
+		if err != nil {
+			return nil, fmt.Errorf("planned time off %q: %w", entry, err)
+		}
+
+		amount, err := strconv.ParseFloat(strings.TrimSpace(entry[separator+1:]), 64)
+		if err != nil || amount <= 0 {
+			return nil, fmt.Errorf("planned time off %q must have a positive amount", entry)
+		}
+
+		planned[date.Format(dateLayout)] += amount
+	}
+
+	return planned, nil
+}
+
+// amountBetween sums the amounts of the days (YYYY-MM-DD) after from, up to and including to
+func amountBetween(amounts map[string]float64, from, to string) float64 {
+	total := 0.0
+	for day, amount := range amounts {
+		if day > from && day <= to {
+			total += amount
+		}
+	}
+	return total
+}
+
+// simulateTimeOffBalance projects the employee's balance of the time off type month by month
+// from today. Each month the booked and planned time off is deducted, the accrual is added at
+// the end of the month up to the cap, and on the day the policy year ends any balance above the
+// carry-over limit is forfeited. A policy year ending mid-month forfeits the balance after the
+// time off up to that day and before the rest of the month's time off and accrual.
+func simulateTimeOffBalance(client BambooHR, employeeID int, typeID string, includeRequested bool, simulation balanceSimulation) (BalanceSimulationResult, error) {
+	today := simulation.today
+	first := time.Date(today.Year(), today.Month(), 1, 0, 0, 0, 0, today.Location())
+	last := first.AddDate(0, simulation.months, -1)
+
+	result := BalanceSimulationResult{
+		EmployeeID:       strconv.Itoa(employeeID),
+		TimeOffTypeID:    typeID,
+		AsOf:             today.Format(dateLayout),
+		AccrualPerMonth:  simulation.accrualPerMonth,
+		AccrualCap:       simulation.accrualCap,
+		CarryOverLimit:   simulation.carryOverLimit,
+		PolicyYearEnd:    simulation.policyYearEnd,
+		IncludeRequested: includeRequested,
+		Months:           []SimulatedMonth{},
+	}
+
+	for day := range simulation.planned {
+		if day <= result.AsOf || day > last.Format(dateLayout) {
+			return result, fmt.Errorf("planned time off on %s is outside the simulated months after today", day)
+		}
+	}
+
+	balances, err := client.GetTimeOffBalance(employeeID)
+	if err != nil {
+		return result, fmt.Errorf("failed to get time-off balance: %w", err)
+	}
+
+	found := false
+	for _, balance := range balances {
+		if balance.TimeOffType != typeID {
+			continue
+		}
+		if strings.EqualFold(balance.PolicyType, "discretionary") {
+			return result, fmt.Errorf("%s is a discretionary policy without a balance to simulate", balance.Name)
+		}
+		result.TimeOffTypeName = balance.Name
+		result.Units = balance.Units
+		result.StartBalance = float64(balance.Balance)
+		found = true
+		break
+	}
+	if !found {
+		return result, fmt.Errorf("employee %d has no balance of time off type %s", employeeID, typeID)
+	}
+
+	// Time off already booked after today, by day
+	statuses := []string{"approved"}
+	if includeRequested {
+		statuses = append(statuses, "requested")
+	}
+	requests, err := client.SearchTimeOffRequests(bamboohr.TimeOffRequestQuery{
+		Start:      today.AddDate(0, 0, 1).Format(dateLayout),
+		End:        last.Format(dateLayout),
+		EmployeeID: employeeID,
+		Statuses:   statuses,
+		TypeIDs:    []string{typeID},
+	})
+	if err != nil {
+		return result, fmt.Errorf("failed to get time-off requests: %w", err)
+	}
+
+	// Time off booked and planned after today, by day
+	scheduled := make(map[string]float64)
+	used := make(map[string]float64)
+	for _, request := range requests {
+		for day, amount := range requestDailyAmounts(request) {
+			if day > result.AsOf && day <= last.Format(dateLayout) {
+				scheduled[day] += amount
+				used[day] += amount
+			}
+		}
+	}
+	for day, amount := range simulation.planned {
+		used[day] += amount
+	}
+
+	yearEnd, _ := time.Parse("01-02", simulation.policyYearEnd)
+
+	balance := result.StartBalance
+	result.LowestBalance = balance
+	for month := first; !month.After(last); month = month.AddDate(0, 1, 0) {
+		before := month.AddDate(0, 0, -1).Format(dateLayout)
+		end := month.AddDate(0, 1, -1)
+		simulated := SimulatedMonth{
+			Month:        month.Format("2006-01"),
+			StartBalance: roundAmount(balance),
+			Scheduled:    roundAmount(amountBetween(scheduled, before, end.Format(dateLayout))),
+			Planned:      roundAmount(amountBetween(simulation.planned, before, end.Format(dateLayout))),
+		}
+
+		// The day the policy year ends in this month, or the month end. A year ending on
+		// February 29th ends on the 28th in other years.
+		forfeitDay := end
+		forfeits := simulation.carryOverLimit != nil && month.Month() == yearEnd.Month()
+		if forfeits {
+			forfeitDay = month.AddDate(0, 0, min(yearEnd.Day(), end.Day())-1)
+		}
+
+		accrue := func() {
+			accrued := simulation.accrualPerMonth
+			if simulation.accrualCap > 0 {
+				accrued = max(0, min(accrued, simulation.accrualCap-balance))
+				simulated.CappedLoss = roundAmount(simulation.accrualPerMonth - accrued)
+			}
+			balance += accrued
+			simulated.Accrued = roundAmount(accrued)
+		}
+
+		// The month end accrual counts towards a policy year that ends on the last day
+		balance -= amountBetween(used, before, forfeitDay.Format(dateLayout))
+		if forfeitDay.Equal(end) {
+			accrue()
+		}
+		if forfeits && balance > *simulation.carryOverLimit {
+			simulated.Forfeited = roundAmount(balance - *simulation.carryOverLimit)
+			balance = *simulation.carryOverLimit
+		}
+		if !forfeitDay.Equal(end) {
+			balance -= amountBetween(used, forfeitDay.Format(dateLayout), end.Format(dateLayout))
+			accrue()
+		}
+
+		simulated.EndBalance = roundAmount(balance)
+		result.LowestBalance = min(result.LowestBalance, simulated.EndBalance)
+		result.TotalAccrued += simulated.Accrued
+		result.TotalUsed += simulated.Scheduled + simulated.Planned
+		result.TotalCappedLoss += simulated.CappedLoss
+		result.TotalForfeited += simulated.Forfeited
+		result.Months = append(result.Months, simulated)
+	}
+
+	result.TotalAccrued = roundAmount(result.TotalAccrued)
+	result.TotalUsed = roundAmount(result.TotalUsed)
+	result.TotalCappedLoss = roundAmount(result.TotalCappedLoss)
+	result.TotalForfeited = roundAmount(result.TotalForfeited)
+	result.EndBalance = roundAmount(balance)
+	result.LowestBalance = roundAmount(result.LowestBalance)
+
+	return result, nil
+}
+
+// timeOffPoliciesView shows the company's policies as text
+func timeOffPoliciesView(result TimeOffPoliciesResult) textView {
+	view := textView{
+		Title:   pluralize(len(result.Policies), "time-off policy", "time-off policies"),
+		Columns: []string{"ID", "Name", "Type", "Time Off Type ID", "Time Off Type", "Units", "Effective Date", "Description"},
+	}
+
+	for _, policy := range result.Policies {
+		view.Items = append(view.Items, policyItem(policy))
+		view.Rows = append(view.Rows, []string{
+			policy.ID,
+			policy.Name,
+			policy.Type,
+			policy.TimeOffTypeID,
+			policy.TimeOffTypeName,
+			policy.Units,
+			policy.EffectiveDate,
+			policy.Description,
+		})
+	}
+
+	return view
+}
+
+// employeePoliciesView shows an employee's policies and balances as text
+func employeePoliciesView(result EmployeePoliciesResult) textView {
+	view := textView{
+		Title:   fmt.Sprintf("Employee %s has %s", result.EmployeeID, pluralize(len(result.Policies), "time-off policy", "time-off policies")),
+		Columns: []string{"ID", "Name", "Type", "Time Off Type ID", "Time Off Type", "Accrual Start Date", "Balance", "Units", "Description"},
+	}
+
+	for _, policy := range result.Policies {
+		item := policyItem(policy.TimeOffPolicyInfo)
+		var balance string
+		if policy.Balance != nil {
+			balance = strconv.FormatFloat(*policy.Balance, 'f', -1, 64)
+			item += fmt.Sprintf("; %s left", formatAmount(*policy.Balance, policy.Units))
+		}
+		if policy.AccrualStartDate != "" {
+			item += "; accrual started " + policy.AccrualStartDate
+		}
+
+		view.Items = append(view.Items, item)
+		view.Rows = append(view.Rows, []string{
+			policy.ID,
+			policy.Name,
+			policy.Type,
+			policy.TimeOffTypeID,
+			policy.TimeOffTypeName,
+			policy.AccrualStartDate,
+			balance,
+			policy.Units,
+			policy.Description,
+		})
+	}
+
+	return view
+}
+
+// policyItem describes a policy in one line
+func policyItem(policy TimeOffPolicyInfo) string {
+	name := policy.Name
+	if name == "" {
+		name = "Policy " + policy.ID
+	}
+	item := fmt.Sprintf("%s (ID %s)", name, policy.ID)
+	if details := joinNonEmpty(", ", policy.TimeOffTypeName, policy.Type); details != "" {
+		item += ": " + details
+	}
+	if policy.Description != "" {
+		item += ". " + policy.Description
+	}
+	return item
+}
+
+// balanceSimulationView shows the projected balance as text, one line per month
+func balanceSimulationView(result BalanceSimulationResult, locale Locale) textView {
+	title := fmt.Sprintf("%s balance of employee %s from %s as of %s to %s at the end of %s",
+		result.TimeOffTypeName, result.EmployeeID, formatAmount(result.StartBalance, result.Units),
+		locale.FormatDate(result.AsOf), formatAmount(result.EndBalance, result.Units), result.Months[len(result.Months)-1].Month)
+	if details := joinNonEmpty(", ",
+		lossSummary(result.TotalCappedLoss, result.Units, "lost to the accrual cap"),
+		lossSummary(result.TotalForfeited, result.Units, "forfeited"),
+	); details != "" {
+		title += " (" + details + ")"
+	}
+	if result.LowestBalance < 0 {
+		title += fmt.Sprintf(". The balance goes negative, down to %s", formatAmount(result.LowestBalance, result.Units))
+	}
+
+	view := textView{
+		Title:   title,
+		Columns: []string{"Month", "Start Balance", "Scheduled", "Planned", "Accrued", "Capped Loss", "Forfeited", "End Balance"},
+	}
+
+	for _, month := range result.Months {
+		item := fmt.Sprintf("%s: %s", month.Month, formatAmount(month.EndBalance, result.Units))
+		var changes []string
+		if used := roundAmount(month.Scheduled + month.Planned); used > 0 {
+			changes = append(changes, fmt.Sprintf("-%s taken", strconv.FormatFloat(used, 'f', -1, 64)))
+		}
+		if month.Accrued > 0 {
+			changes = append(changes, fmt.Sprintf("+%s accrued", strconv.FormatFloat(month.Accrued, 'f', -1, 64)))
+		}
+		if month.CappedLoss > 0 {
+			changes = append(changes, fmt.Sprintf("%s lost to the cap", strconv.FormatFloat(month.CappedLoss, 'f', -1, 64)))
+		}
+		if month.Forfeited > 0 {
+			changes = append(changes, fmt.Sprintf("%s forfeited", strconv.FormatFloat(month.Forfeited, 'f', -1, 64)))
+		}
+		if len(changes) > 0 {
+			item += " (" + strings.Join(changes, ", ") + ")"
+		}
+
+		view.Items = append(view.Items, item)
+		view.Rows = append(view.Rows, []string{
+			month.Month,
+			strconv.FormatFloat(month.StartBalance, 'f', -1, 64),
+			strconv.FormatFloat(month.Scheduled, 'f', -1, 64),
+			strconv.FormatFloat(month.Planned, 'f', -1, 64),
+			strconv.FormatFloat(month.Accrued, 'f', -1, 64),
+			strconv.FormatFloat(month.CappedLoss, 'f', -1, 64),
+			strconv.FormatFloat(month.Forfeited, 'f', -1, 64),
+			strconv.FormatFloat(month.EndBalance, 'f', -1, 64),
+		})
+	}
+
+	return view
+}
+
+// lossSummary describes an amount lost, or returns "" if nothing was lost
+func lossSummary(amount float64, units, how string) string {
+	if amount <= 0 {
+		return ""
+	}
+	return formatAmount(amount, units) + " " + how
+}
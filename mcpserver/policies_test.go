@@ -0,0 +1,163 @@
+package mcpserver
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"bamboohr-mcp-server/bamboohr"
+)
+
+func newPoliciesTestFake() *fakeBambooHR {
+	fake := newApprovalsTestFake()
+	fake.types.TimeOffTypes = []bamboohr.TimeOffType{{ID: "78", Name: "Vacation"}, {ID: "83", Name: "Sick"}}
+	fake.policies = []bamboohr.TimeOffPolicy{
+		{ID: "1", TimeOffTypeID: "78", Name: "Vacation PTO", Type: "accruing"},
+		{ID: "2", TimeOffTypeID: "83", Name: "Sick Leave", Type: "discretionary"},
+	}
+	fake.assigned[4] = []bamboohr.EmployeeTimeOffPolicy{
+		{TimeOffPolicyID: "1", TimeOffTypeID: "78", AccrualStartDate: "2020-01-01"},
+	}
+	fake.balances[5] = []bamboohr.TimeOffBalance{{TimeOffType: "78", Name: "Vacation", Units: "days", PolicyType: "discretionary"}}
+	return fake
+}
+
+func TestTimeOffPolicyTools(t *testing.T) {
+	s := New(newPoliciesTestFake(), newTestDateParser())
+
+	text, isError := callTool(t, s, "list_time_off_policies", nil)
+	expected := "2 time-off policies:\n" +
+		"- Sick Leave (ID 2): Sick, discretionary. No balance is tracked, time off is granted at the approver's discretion\n" +
+		"- Vacation PTO (ID 1): Vacation, accruing. Time off accrues over time on the policy's accrual schedule"
+	if isError || text != expected {
+		t.Errorf("Expected %q, got %q", expected, text)
+	}
+
+	text, isError = callTool(t, s, "get_employee_time_off_policies", map[string]string{"employeeId": "4"})
+	expected = "Employee 4 has 1 time-off policy:\n" +
+		"- Vacation PTO (ID 1): Vacation, accruing. Time off accrues over time on the policy's accrual schedule; 10 days left; accrual started 2020-01-01"
+	if isError || text != expected {
+		t.Errorf("Expected %q, got %q", expected, text)
+	}
+
+	text, isError = callTool(t, s, "get_employee_time_off_policies", map[string]string{"employeeId": "4", "format": "full"})
+	if isError {
+		t.Fatalf("Unexpected tool error: %s", text)
+	}
+	if !strings.Contains(text, `"timeOffTypeName": "Vacation"`) {
+		t.Errorf("Expected the policy fields to be inlined, got %s", text)
+	}
+
+	var result EmployeePoliciesResult
+	if err := json.Unmarshal([]byte(text), &result); err != nil {
+		t.Fatalf("Failed to decode result: %v", err)
+	}
+	if len(result.Policies) != 1 || result.Policies[0].Balance == nil || *result.Policies[0].Balance != 10 || result.Policies[0].Units != "days" {
+		t.Errorf("Unexpected policies: %+v", result.Policies)
+	}
+}
+
+func TestSimulateTimeOffBalance(t *testing.T) {
+	s := New(newPoliciesTestFake(), newTestDateParser())
+
+	arguments := map[string]string{
+		"employeeId":      "4",
+		"timeOffType":     "Vacation",
+		"accrualPerMonth": "2",
+		"accrualCap":      "12",
+		"carryOverLimit":  "5",
+		"months":          "6",
+		"planned":         "2025-12-22:3",
+	}
+	text, isError := callTool(t, s, "simulate_time_off_balance", arguments)
+	expected := "Vacation balance of employee 4 from 10 days as of 2025-09-03 to 9 days at the end of 2026-02 (3 days lost to the accrual cap, 6 days forfeited):\n" +
+		"- 2025-09: 12 days (+2 accrued)\n" +
+		"- 2025-10: 12 days (2 lost to the cap)\n" +
+		"- 2025-11: 12 days (-1 taken, +1 accrued, 1 lost to the cap)\n" +
+		"- 2025-12: 5 days (-3 taken, +2 accrued, 6 forfeited)\n" +
+		"- 2026-01: 7 days (+2 accrued)\n" +
+		"- 2026-02: 9 days (+2 accrued)"
+	if isError || text != expected {
+		t.Errorf("Expected %q, got %q", expected, text)
+	}
+
+	// Requested time off is deducted too, and without rules the balance only goes down
+	text, isError = callTool(t, s, "simulate_time_off_balance", map[string]string{
+		"employeeId":       "4",
+		"timeOffType":      "78",
+		"months":           "3",
+		"planned":          "2025-09-15:8",
+		"includeRequested": "true",
+		"format":           "full",
+	})
+	if isError {
+		t.Fatalf("Unexpected tool error: %s", text)
+	}
+
+	var result BalanceSimulationResult
+	if err := json.Unmarshal([]byte(text), &result); err != nil {
+		t.Fatalf("Failed to decode result: %v", err)
+	}
+	if len(result.Months) != 3 || result.Months[1].Scheduled != 3 || result.TotalUsed != 12 {
+		t.Errorf("Unexpected months: %+v", result.Months)
+	}
+	if result.EndBalance != -2 || result.LowestBalance != -2 || result.CarryOverLimit != nil {
+		t.Errorf("Expected the balance to end at -2 days without a carry-over limit, got %+v", result)
+	}
+}
+
+func TestSimulateTimeOffBalance_MidMonthYearEnd(t *testing.T) {
+	s := New(newPoliciesTestFake(), newTestDateParser())
+
+	// The balance above 5 days is forfeited on October 15th, before the time off on the 20th
+	// and the accrual at the end of the month
+	text, isError := callTool(t, s, "simulate_time_off_balance", map[string]string{
+		"employeeId":      "4",
+		"timeOffType":     "Vacation",
+		"accrualPerMonth": "2",
+		"carryOverLimit":  "5",
+		"policyYearEnd":   "10-15",
+		"months":          "2",
+		"planned":         "2025-10-10:1; 2025-10-20:4",
+		"format":          "full",
+	})
+	if isError {
+		t.Fatalf("Unexpected tool error: %s", text)
+	}
+
+	var result BalanceSimulationResult
+	if err := json.Unmarshal([]byte(text), &result); err != nil {
+		t.Fatalf("Failed to decode result: %v", err)
+	}
+	october := result.Months[1]
+	if october.StartBalance != 12 || october.Planned != 5 || october.Forfeited != 6 || october.EndBalance != 3 {
+		t.Errorf("Expected 6 days forfeited on October 15th and 3 days left, got %+v", october)
+	}
+}
+
+func TestSimulateTimeOffBalance_Errors(t *testing.T) {
+	s := New(newPoliciesTestFake(), newTestDateParser())
+
+	tests := []struct {
+		name      string
+		arguments map[string]string
+		expected  string
+	}{
+		{"planned in the past", map[string]string{"employeeId": "4", "timeOffType": "Vacation", "planned": "2025-08-01:1"}, "outside the simulated months"},
+		{"planned without amount", map[string]string{"employeeId": "4", "timeOffType": "Vacation", "planned": "2025-12-22"}, "must be a date and amount"},
+		{"several types", map[string]string{"employeeId": "4", "timeOffType": "Vacation,Sick"}, "single time off type"},
+		{"unknown type", map[string]string{"employeeId": "4", "timeOffType": "Sabbatical"}, "unknown time off type"},
+		{"no balance", map[string]string{"employeeId": "4", "timeOffType": "Sick"}, "has no balance"},
+		{"discretionary", map[string]string{"employeeId": "5", "timeOffType": "Vacation"}, "discretionary"},
+		{"policy year end", map[string]string{"employeeId": "4", "timeOffType": "Vacation", "policyYearEnd": "31-12"}, "MM-DD"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			text, isError := callTool(t, s, "simulate_time_off_balance", tt.arguments)
+			if !isError || !strings.Contains(text, tt.expected) {
+				t.Errorf("Expected an error containing %q, got %q", tt.expected, text)
+			}
+		})
+	}
+}
@@ -0,0 +1,277 @@
+package mcpserver
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"bamboohr-mcp-server/bamboohr"
+	"bamboohr-mcp-server/store"
+)
+
+// ErrPolicyDenied is returned for calls refused by the configured Policy
+var ErrPolicyDenied = errors.New("denied by policy")
+
+// Policy restricts what the MCP server may do with the BambooHR API
+type Policy struct {
+	// ReadOnly refuses every write, such as creating time-off requests
+	ReadOnly bool
+	// EmployeeIDs limits access to these employees. Empty allows every employee.
+	EmployeeIDs []int
+}
+
+// policyClient is a BambooHR decorator that enforces a Policy
+type policyClient struct {
+	next      BambooHR
+	readOnly  bool
+	employees map[int]bool
+}
+
+// NewPolicyEnforcer wraps the client so that calls outside the policy fail with ErrPolicyDenied.
+// Calls for employees outside the policy are refused, and the directory and who's out calendar
+// are filtered to the allowed employees.
+func NewPolicyEnforcer(client BambooHR, policy Policy) BambooHR {
+	return &policyClient{next: client, readOnly: policy.ReadOnly, employees: policyEmployees(policy)}
+}
+
+// policyEmployees returns the set of employees the policy allows, or nil if it allows every one
+func policyEmployees(policy Policy) map[int]bool {
+	if len(policy.EmployeeIDs) == 0 {
+		return nil
+	}
+
+	employees := make(map[int]bool, len(policy.EmployeeIDs))
+	for _, id := range policy.EmployeeIDs {
+		employees[id] = true
+	}
+	return employees
+}
+
+// ParseEmployeeIDs parses a comma-separated list of employee IDs such as "4,12,40"
+func ParseEmployeeIDs(value string) ([]int, error) {
+	var ids []int
+	for _, part := range strings.Split(value, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		id, err := strconv.Atoi(part)
+		if err != nil {
+			return nil, fmt.Errorf("invalid employee ID %q: must be an integer", part)
+		}
+		ids = append(ids, id)
+	}
+
+	return ids, nil
+}
+
+// allows reports whether the policy allows access to the employee
+func (p *policyClient) allows(employeeID int) bool {
+	return p.employees == nil || p.employees[employeeID]
+}
+
+// checkEmployee returns ErrPolicyDenied if the policy does not allow access to the employee
+func (p *policyClient) checkEmployee(employeeID int) error {
+	if !p.allows(employeeID) {
+		return fmt.Errorf("employee %d: %w", employeeID, ErrPolicyDenied)
+	}
+	return nil
+}
+
+// allowsID reports whether the policy allows access to the employee with the string ID
+func (p *policyClient) allowsID(employeeID string) bool {
+	return allowsEmployeeID(p.employees, employeeID)
+}
+
+// allowsEmployeeID reports whether the employee with the string ID is in the set of allowed
+// employees, where a nil set allows every employee
+func allowsEmployeeID(employees map[int]bool, employeeID string) bool {
+	if employees == nil {
+		return true
+	}
+
+	id, err := strconv.Atoi(employeeID)
+	return err == nil && employees[id]
+}
+
+func (p *policyClient) Company() string {
+	return p.next.Company()
+}
+
+func (p *policyClient) GetTimeOffRequests(employeeID int, start, end string) ([]bamboohr.TimeOffRequest, error) {
+	if err := p.checkEmployee(employeeID); err != nil {
+		return nil, err
+	}
+	return p.next.GetTimeOffRequests(employeeID, start, end)
+}
+
+func (p *policyClient) SearchTimeOffRequests(query bamboohr.TimeOffRequestQuery) ([]bamboohr.TimeOffRequest, error) {
+	if query.EmployeeID != 0 {
+		if err := p.checkEmployee(query.EmployeeID); err != nil {
+			return nil, err
+		}
+	}
+
+	requests, err := p.next.SearchTimeOffRequests(query)
+	if err != nil || p.employees == nil {
+		return requests, err
+	}
+
+	var filtered []bamboohr.TimeOffRequest
+	for _, request := range requests {
+		if p.allowsID(request.EmployeeID) {
+			filtered = append(filtered, request)
+		}
+	}
+
+	return filtered, nil
+}
+
+func (p *policyClient) GetTimeOffBalance(employeeID int) ([]bamboohr.TimeOffBalance, error) {
+	if err := p.checkEmployee(employeeID); err != nil {
+		return nil, err
+	}
+	return p.next.GetTimeOffBalance(employeeID)
+}
+
+func (p *policyClient) CreateTimeOffRequest(employeeID int, request bamboohr.TimeOffRequestCreate) (*bamboohr.TimeOffRequest, error) {
+	if p.readOnly {
+		return nil, fmt.Errorf("creating time-off requests: server is read-only: %w", ErrPolicyDenied)
+	}
+	if err := p.checkEmployee(employeeID); err != nil {
+		return nil, err
+	}
+	return p.next.CreateTimeOffRequest(employeeID, request)
+}
+
+func (p *policyClient) GetEmployeeDirectory() (*bamboohr.EmployeeDirectory, error) {
+	directory, err := p.next.GetEmployeeDirectory()
+	if err != nil || p.employees == nil {
+		return directory, err
+	}
+
+	filtered := &bamboohr.EmployeeDirectory{Fields: directory.Fields}
+	for _, employee := range directory.Employees {
+		if p.allowsID(employee.ID) {
+			filtered.Employees = append(filtered.Employees, employee)
+		}
+	}
+
+	return filtered, nil
+}
+
+func (p *policyClient) GetEmployee(employeeID int) (*bamboohr.Employee, error) {
+	if err := p.checkEmployee(employeeID); err != nil {
+		return nil, err
+	}
+	return p.next.GetEmployee(employeeID)
+}
+
+func (p *policyClient) GetChangedEmployees(since time.Time) (*bamboohr.EmployeeChanges, error) {
+	changes, err := p.next.GetChangedEmployees(since)
+	if err != nil || p.employees == nil {
+		return changes, err
+	}
+
+	filtered := &bamboohr.EmployeeChanges{Latest: changes.Latest, Employees: make(map[string]bamboohr.EmployeeChange)}
+	for id, change := range changes.Employees {
+		if p.allowsID(id) {
+			filtered.Employees[id] = change
+		}
+	}
+
+	return filtered, nil
+}
+
+func (p *policyClient) GetTimeOffTypes() (*bamboohr.TimeOffTypes, error) {
+	return p.next.GetTimeOffTypes()
+}
+
+func (p *policyClient) GetTimeOffPolicies() ([]bamboohr.TimeOffPolicy, error) {
+	return p.next.GetTimeOffPolicies()
+}
+
+func (p *policyClient) GetEmployeeTimeOffPolicies(employeeID int) ([]bamboohr.EmployeeTimeOffPolicy, error) {
+	if err := p.checkEmployee(employeeID); err != nil {
+		return nil, err
+	}
+	return p.next.GetEmployeeTimeOffPolicies(employeeID)
+}
+
+func (p *policyClient) GetWhosOut(start, end string) ([]bamboohr.WhosOutEntry, error) {
+	entries, err := p.next.GetWhosOut(start, end)
+	if err != nil || p.employees == nil {
+		return entries, err
+	}
+
+	// Holidays have no employee and are always kept
+	var filtered []bamboohr.WhosOutEntry
+	for _, entry := range entries {
+		if entry.EmployeeID == "" || p.allowsID(entry.EmployeeID.String()) {
+			filtered = append(filtered, entry)
+		}
+	}
+
+	return filtered, nil
+}
+
+// policyHistory is a TimeOffHistory decorator that limits the local store to a Policy's employees
+type policyHistory struct {
+	next      TimeOffHistory
+	employees map[int]bool
+}
+
+// NewHistoryPolicyEnforcer wraps the local store so that the history tools only see the
+// employees the policy allows. Queries naming other employees fail with ErrPolicyDenied.
+func NewHistoryPolicyEnforcer(history TimeOffHistory, policy Policy) TimeOffHistory {
+	return &policyHistory{next: history, employees: policyEmployees(policy)}
+}
+
+func (p *policyHistory) SyncedAt() (time.Time, error) {
+	return p.next.SyncedAt()
+}
+
+func (p *policyHistory) EmployeeDirectory() (*bamboohr.EmployeeDirectory, error) {
+	directory, err := p.next.EmployeeDirectory()
+	if err != nil || p.employees == nil {
+		return directory, err
+	}
+
+	filtered := &bamboohr.EmployeeDirectory{Fields: directory.Fields}
+	for _, employee := range directory.Employees {
+		if allowsEmployeeID(p.employees, employee.ID) {
+			filtered.Employees = append(filtered.Employees, employee)
+		}
+	}
+
+	return filtered, nil
+}
+
+func (p *policyHistory) SearchTimeOffRequests(filter store.RequestFilter) ([]bamboohr.TimeOffRequest, error) {
+	if p.employees == nil {
+		return p.next.SearchTimeOffRequests(filter)
+	}
+
+	for _, id := range filter.EmployeeIDs {
+		if !p.employees[id] {
+			return nil, fmt.Errorf("employee %d: %w", id, ErrPolicyDenied)
+		}
+	}
+
+	requests, err := p.next.SearchTimeOffRequests(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	var filtered []bamboohr.TimeOffRequest
+	for _, request := range requests {
+		if allowsEmployeeID(p.employees, request.EmployeeID) {
+			filtered = append(filtered, request)
+		}
+	}
+
+	return filtered, nil
+}
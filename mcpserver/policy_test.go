@@ -0,0 +1,103 @@
+package mcpserver
+
+import (
+	"errors"
+	"testing"
+
+	"bamboohr-mcp-server/bamboohr"
+)
+
+func TestPolicyEnforcer_ReadOnly(t *testing.T) {
+	fake := newFakeBambooHR(bamboohr.Employee{ID: "4"})
+	client := NewPolicyEnforcer(fake, Policy{ReadOnly: true})
+
+	_, err := client.CreateTimeOffRequest(4, bamboohr.TimeOffRequestCreate{})
+	if !errors.Is(err, ErrPolicyDenied) {
+		t.Errorf("Expected ErrPolicyDenied, got %v", err)
+	}
+
+	if len(fake.created) != 0 {
+		t.Errorf("Expected the write not to reach the client, got %d created", len(fake.created))
+	}
+
+	if _, err := client.GetTimeOffBalance(4); err != nil {
+		t.Errorf("Expected reads to be allowed, got %v", err)
+	}
+}
+
+func TestPolicyEnforcer_EmployeeIDs(t *testing.T) {
+	fake := newFakeBambooHR(
+		bamboohr.Employee{ID: "4", DisplayName: "Charlotte Abbott"},
+		bamboohr.Employee{ID: "5", DisplayName: "Ashley Adams"},
+	)
+	fake.whosOut = []bamboohr.WhosOutEntry{
+		{ID: "1", Type: "timeOff", EmployeeID: "4", Name: "Charlotte Abbott"},
+		{ID: "2", Type: "timeOff", EmployeeID: "5", Name: "Ashley Adams"},
+		{ID: "3", Type: "holiday", Name: "Christmas Day"},
+	}
+	client := NewPolicyEnforcer(fake, Policy{EmployeeIDs: []int{4}})
+
+	if _, err := client.GetEmployee(4); err != nil {
+		t.Errorf("Expected allowed employee to be readable, got %v", err)
+	}
+
+	denied := map[string]func() error{
+		"GetEmployee": func() error { _, err := client.GetEmployee(5); return err },
+		"GetTimeOffRequests": func() error {
+			_, err := client.GetTimeOffRequests(5, "2025-01-01", "2025-12-31")
+			return err
+		},
+		"SearchTimeOffRequests": func() error {
+			_, err := client.SearchTimeOffRequests(bamboohr.TimeOffRequestQuery{EmployeeID: 5})
+			return err
+		},
+		"GetTimeOffBalance":          func() error { _, err := client.GetTimeOffBalance(5); return err },
+		"GetEmployeeTimeOffPolicies": func() error { _, err := client.GetEmployeeTimeOffPolicies(5); return err },
+		"CreateTimeOffRequest":       func() error { _, err := client.CreateTimeOffRequest(5, bamboohr.TimeOffRequestCreate{}); return err },
+	}
+	for name, call := range denied {
+		if err := call(); !errors.Is(err, ErrPolicyDenied) {
+			t.Errorf("%s: expected ErrPolicyDenied, got %v", name, err)
+		}
+	}
+
+	directory, err := client.GetEmployeeDirectory()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(directory.Employees) != 1 || directory.Employees[0].ID != "4" {
+		t.Errorf("Expected directory filtered to employee 4, got %+v", directory.Employees)
+	}
+
+	fake.requests[4] = []bamboohr.TimeOffRequest{{ID: "10", EmployeeID: "4"}}
+	fake.requests[5] = []bamboohr.TimeOffRequest{{ID: "11", EmployeeID: "5"}}
+	requests, err := client.SearchTimeOffRequests(bamboohr.TimeOffRequestQuery{})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(requests) != 1 || requests[0].ID != "10" {
+		t.Errorf("Expected requests filtered to employee 4, got %+v", requests)
+	}
+
+	entries, err := client.GetWhosOut("2025-12-01", "2025-12-31")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(entries) != 2 || entries[0].ID != "1" || entries[1].ID != "3" {
+		t.Errorf("Expected the allowed employee and the holiday, got %+v", entries)
+	}
+}
+
+func TestParseEmployeeIDs(t *testing.T) {
+	ids, err := ParseEmployeeIDs(" 4, 12,,40 ")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(ids) != 3 || ids[0] != 4 || ids[1] != 12 || ids[2] != 40 {
+		t.Errorf("Expected [4 12 40], got %v", ids)
+	}
+
+	if _, err := ParseEmployeeIDs("4,abc"); err == nil {
+		t.Error("Expected error for a non-integer ID")
+	}
+}
@@ -0,0 +1,216 @@
+package mcpserver
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// registerPrompts adds prompts for common HR workflows to the server
+func registerPrompts(s *server.MCPServer, dates *DateParser) {
+	s.AddPrompt(mcp.NewPrompt(
+		"plan_vacation",
+		mcp.WithPromptDescription("Plan a vacation: check balances, existing time off and holidays, then propose and book dates"),
+		mcp.WithArgument("employeeId",
+			mcp.RequiredArgument(),
+			mcp.ArgumentDescription("The ID of the employee planning the vacation"),
+		),
+		mcp.WithArgument("start",
+			mcp.ArgumentDescription("Earliest date the vacation could start, or a whole range such as 'Dec 23 - Jan 2' (YYYY-MM-DD or an expression like 'next friday')"),
+		),
+		mcp.WithArgument("end",
+			mcp.ArgumentDescription("Latest date the vacation could end (YYYY-MM-DD or an expression like 'end of next month')"),
+		),
+		mcp.WithArgument("days",
+			mcp.ArgumentDescription("Number of days off wanted"),
+		),
+		mcp.WithArgument("timeOffTypeId",
+			mcp.ArgumentDescription("The ID of the time-off type to book (defaults to '1' for Vacation)"),
+		),
+	), handlePlanVacationPrompt(dates))
+
+	s.AddPrompt(mcp.NewPrompt(
+		"team_absence_summary",
+		mcp.WithPromptDescription("Summarise who on a team is absent during a week or date range"),
+		mcp.WithArgument("team",
+			mcp.RequiredArgument(),
+			mcp.ArgumentDescription("A department name or a comma-separated list of employee IDs"),
+		),
+		mcp.WithArgument("start",
+			mcp.ArgumentDescription("Start of the period, or a period such as 'next week' (defaults to the current week)"),
+		),
+		mcp.WithArgument("end",
+			mcp.ArgumentDescription("End of the period (YYYY-MM-DD or an expression like 'friday')"),
+		),
+	), handleTeamAbsenceSummaryPrompt(dates))
+
+	s.AddPrompt(mcp.NewPrompt(
+		"review_pending_requests",
+		mcp.WithPromptDescription("Review pending time-off requests for a manager's direct reports"),
+		mcp.WithArgument("employeeIds",
+			mcp.RequiredArgument(),
+			mcp.ArgumentDescription("Comma-separated list of the employee IDs of the manager's reports"),
+		),
+		mcp.WithArgument("start",
+			mcp.ArgumentDescription("Start of the period to review, or a period such as 'this quarter' (defaults to today)"),
+		),
+		mcp.WithArgument("end",
+			mcp.ArgumentDescription("End of the period to review (defaults to the end of the year)"),
+		),
+	), handleReviewPendingRequestsPrompt(dates))
+}
+
+// Prompt handlers
+
+func handlePlanVacationPrompt(dates *DateParser) server.PromptHandlerFunc {
+	return func(ctx context.Context, request mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+		args := request.Params.Arguments
+
+		employeeID := args["employeeId"]
+		if employeeID == "" {
+			return nil, fmt.Errorf("employeeId is required")
+		}
+
+		// Resolve the period in the employee's timezone when the ID is numeric
+		parser := dates
+		if id, err := strconv.Atoi(employeeID); err == nil {
+			parser = dates.ForEmployee(id)
+		}
+
+		period, err := parser.ResolveRange(args["start"], args["end"], DateRange{Start: parser.Today(), End: parser.CurrentYear().End})
+		if err != nil {
+			return nil, err
+		}
+		start, end := period.StartYMD(), period.EndYMD()
+		timeOffTypeID := promptArgument(args, "timeOffTypeId", "1")
+
+		days := "as many days as make sense"
+		if args["days"] != "" {
+			days = args["days"] + " days"
+		}
+
+		var b strings.Builder
+		fmt.Fprintf(&b, "Help employee %s plan a vacation of %s between %s and %s.\n\n", employeeID, days, start, end)
+		b.WriteString("Follow these steps:\n")
+		fmt.Fprintf(&b, "1. Call `get_time_off_balance` with employeeId %q and report the remaining balance for time-off type %s.\n", employeeID, timeOffTypeID)
+		fmt.Fprintf(&b, "2. Call `get_time_off_requests` with employeeId %q, start %q and end %q to find time off that is already booked.\n", employeeID, start, end)
+		fmt.Fprintf(&b, "3. Read the `bamboohr://holidays/{year}` resource for each year in the period so public holidays are not booked as time off.\n")
+		b.WriteString("4. Propose up to three date ranges that fit within the balance, avoid existing requests, and make good use of weekends and holidays.\n")
+		fmt.Fprintf(&b, "5. Only after I confirm a range, call `create_time_off_request` with employeeId %q and timeOffTypeId %q for it.\n", employeeID, timeOffTypeID)
+
+		return mcp.NewGetPromptResult(
+			"Plan a vacation",
+			[]mcp.PromptMessage{
+				mcp.NewPromptMessage(mcp.RoleUser, mcp.NewTextContent(b.String())),
+			},
+		), nil
+	}
+}
+
+func handleTeamAbsenceSummaryPrompt(dates *DateParser) server.PromptHandlerFunc {
+	return func(ctx context.Context, request mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+		args := request.Params.Arguments
+
+		team := args["team"]
+		if team == "" {
+			return nil, fmt.Errorf("team is required")
+		}
+
+		parser := dates.ForEmployees(promptEmployeeIDs(team))
+		monday, sunday := weekRange(parser.Today())
+		period, err := parser.ResolveRange(args["start"], args["end"], DateRange{Start: monday, End: sunday})
+		if err != nil {
+			return nil, err
+		}
+		start, end := period.StartYMD(), period.EndYMD()
+
+		var b strings.Builder
+		fmt.Fprintf(&b, "Summarise absences for the team %q between %s and %s.\n\n", team, start, end)
+		b.WriteString("Follow these steps:\n")
+		if ids := splitIDs(team); len(ids) > 0 {
+			fmt.Fprintf(&b, "1. The team members are the employees with IDs %s.\n", strings.Join(ids, ", "))
+		} else {
+			fmt.Fprintf(&b, "1. Call `list_employees` and select the employees whose department is %q.\n", team)
+		}
+		fmt.Fprintf(&b, "2. For each team member, call `get_time_off_requests` with start %q and end %q.\n", start, end)
+		b.WriteString("3. Ignore requests with status `denied` or `canceled`, and mark requests with status `requested` as pending.\n")
+		b.WriteString("4. Produce a day-by-day table of who is out, followed by a short list of days where more than half of the team is absent.\n")
+
+		return mcp.NewGetPromptResult(
+			"Weekly team absence summary",
+			[]mcp.PromptMessage{
+				mcp.NewPromptMessage(mcp.RoleUser, mcp.NewTextContent(b.String())),
+			},
+		), nil
+	}
+}
+
+func handleReviewPendingRequestsPrompt(dates *DateParser) server.PromptHandlerFunc {
+	return func(ctx context.Context, request mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+		args := request.Params.Arguments
+
+		ids := splitIDs(args["employeeIds"])
+		if len(ids) == 0 {
+			return nil, fmt.Errorf("employeeIds is required")
+		}
+
+		parser := dates.ForEmployees(promptEmployeeIDs(args["employeeIds"]))
+		period, err := parser.ResolveRange(args["start"], args["end"], DateRange{Start: parser.Today(), End: parser.CurrentYear().End})
+		if err != nil {
+			return nil, err
+		}
+		start, end := period.StartYMD(), period.EndYMD()
+
+		var b strings.Builder
+		fmt.Fprintf(&b, "Review the pending time-off requests of my reports (employee IDs %s) between %s and %s.\n\n", strings.Join(ids, ", "), start, end)
+		b.WriteString("Follow these steps:\n")
+		fmt.Fprintf(&b, "1. For each employee, call `get_time_off_requests` with start %q and end %q and keep only requests with status `requested`.\n", start, end)
+		b.WriteString("2. For each employee with a pending request, call `get_time_off_balance` and check the request fits within the remaining balance of its type.\n")
+		b.WriteString("3. Check whether pending requests overlap with each other or with approved time off of other reports.\n")
+		b.WriteString("4. List each pending request with the employee name, dates, amount, type, remaining balance and any overlaps, and recommend approving or discussing it.\n")
+
+		return mcp.NewGetPromptResult(
+			"Review pending requests for my reports",
+			[]mcp.PromptMessage{
+				mcp.NewPromptMessage(mcp.RoleUser, mcp.NewTextContent(b.String())),
+			},
+		), nil
+	}
+}
+
+// promptArgument returns the named prompt argument, or fallback if it is empty
+func promptArgument(args map[string]string, name, fallback string) string {
+	if value := strings.TrimSpace(args[name]); value != "" {
+		return value
+	}
+	return fallback
+}
+
+// splitIDs splits a comma-separated list of numeric IDs, returning nil if any entry is not numeric
+func splitIDs(value string) []string {
+	var ids []string
+	for _, part := range strings.Split(value, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		for _, r := range part {
+			if r < '0' || r > '9' {
+				return nil
+			}
+		}
+		ids = append(ids, part)
+	}
+	return ids
+}
+
+// promptEmployeeIDs returns the employee IDs of a comma-separated list of IDs, or nil if it is
+// a department name
+func promptEmployeeIDs(value string) []int {
+	ids, _ := ParseEmployeeIDs(strings.Join(splitIDs(value), ","))
+	return ids
+}
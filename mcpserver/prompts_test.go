@@ -0,0 +1,150 @@
+package mcpserver
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"bamboohr-mcp-server/bamboohr"
+)
+
+// getPromptText gets a prompt through the MCP server and returns the text of its single message
+func getPromptText(t *testing.T, name string, args map[string]string) string {
+	t.Helper()
+
+	s := New(bamboohr.NewClient("testcompany", "testkey"), newTestDateParser())
+	result := sendMessage(t, s, "prompts/get", map[string]interface{}{
+		"name":      name,
+		"arguments": args,
+	})
+
+	var prompt struct {
+		Messages []struct {
+			Role    string          `json:"role"`
+			Content mcp.TextContent `json:"content"`
+		} `json:"messages"`
+	}
+	if err := json.Unmarshal(result, &prompt); err != nil {
+		t.Fatalf("Failed to unmarshal prompt: %v", err)
+	}
+
+	if len(prompt.Messages) != 1 {
+		t.Fatalf("Expected 1 message, got %d", len(prompt.Messages))
+	}
+
+	if prompt.Messages[0].Role != string(mcp.RoleUser) {
+		t.Errorf("Expected user role, got %s", prompt.Messages[0].Role)
+	}
+
+	return prompt.Messages[0].Content.Text
+}
+
+func TestPrompts_List(t *testing.T) {
+	s := New(bamboohr.NewClient("testcompany", "testkey"), newTestDateParser())
+
+	var list struct {
+		Prompts []mcp.Prompt `json:"prompts"`
+	}
+	if err := json.Unmarshal(sendMessage(t, s, "prompts/list", map[string]string{}), &list); err != nil {
+		t.Fatalf("Failed to unmarshal prompts: %v", err)
+	}
+
+	expected := map[string]bool{
+		"plan_vacation":           false,
+		"team_absence_summary":    false,
+		"review_pending_requests": false,
+	}
+	for _, prompt := range list.Prompts {
+		expected[prompt.Name] = true
+	}
+	for name, found := range expected {
+		if !found {
+			t.Errorf("Expected prompt %s to be registered", name)
+		}
+	}
+}
+
+func TestPrompts_PlanVacation(t *testing.T) {
+	text := getPromptText(t, "plan_vacation", map[string]string{
+		"employeeId": "157",
+		"start":      "2025-12-20",
+		"end":        "2026-01-05",
+		"days":       "5",
+	})
+
+	for _, expected := range []string{
+		"5 days between 2025-12-20 and 2026-01-05",
+		"`get_time_off_balance`",
+		`start "2025-12-20" and end "2026-01-05"`,
+		"`create_time_off_request`",
+		`timeOffTypeId "1"`,
+	} {
+		if !strings.Contains(text, expected) {
+			t.Errorf("Expected prompt to contain %q, got:\n%s", expected, text)
+		}
+	}
+}
+
+func TestPrompts_TeamAbsenceSummary(t *testing.T) {
+	byIDs := getPromptText(t, "team_absence_summary", map[string]string{
+		"team":  "157, 158",
+		"start": "2025-09-01",
+		"end":   "2025-09-07",
+	})
+	if !strings.Contains(byIDs, "IDs 157, 158") {
+		t.Errorf("Expected prompt to list employee IDs, got:\n%s", byIDs)
+	}
+
+	byDepartment := getPromptText(t, "team_absence_summary", map[string]string{"team": "Engineering"})
+	if !strings.Contains(byDepartment, "`list_employees`") || !strings.Contains(byDepartment, `department is "Engineering"`) {
+		t.Errorf("Expected prompt to resolve the department, got:\n%s", byDepartment)
+	}
+
+	if !strings.Contains(byDepartment, "between 2025-09-01 and 2025-09-07") {
+		t.Errorf("Expected prompt to default to the current week, got:\n%s", byDepartment)
+	}
+}
+
+func TestPrompts_ReviewPendingRequests(t *testing.T) {
+	text := getPromptText(t, "review_pending_requests", map[string]string{"employeeIds": "157,158"})
+
+	if !strings.Contains(text, "employee IDs 157, 158") || !strings.Contains(text, "status `requested`") {
+		t.Errorf("Expected prompt to review requested items for both reports, got:\n%s", text)
+	}
+
+	if !strings.Contains(text, "between 2025-09-03 and 2025-12-31") {
+		t.Errorf("Expected prompt to default to the rest of the year, got:\n%s", text)
+	}
+}
+
+func TestPrompts_RelativeDates(t *testing.T) {
+	text := getPromptText(t, "plan_vacation", map[string]string{
+		"employeeId": "157",
+		"start":      "Dec 23 - Jan 2",
+	})
+
+	if !strings.Contains(text, "between 2025-12-23 and 2026-01-02") {
+		t.Errorf("Expected the range to be resolved across the year boundary, got:\n%s", text)
+	}
+}
+
+func TestSplitIDs(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected []string
+	}{
+		{"157", []string{"157"}},
+		{"157, 158,,159", []string{"157", "158", "159"}},
+		{"Engineering", nil},
+		{"", nil},
+	}
+
+	for _, tt := range tests {
+		ids := splitIDs(tt.input)
+		if strings.Join(ids, ",") != strings.Join(tt.expected, ",") {
+			t.Errorf("splitIDs(%q): expected %v, got %v", tt.input, tt.expected, ids)
+		}
+	}
+}
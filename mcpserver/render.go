@@ -0,0 +1,135 @@
+package mcpserver
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Format is the text format tool results are rendered in
+type Format string
+
+const (
+	// FormatFull renders the complete result as indented JSON
+	FormatFull Format = "full"
+	// FormatCompact renders a short title and one line per item
+	FormatCompact Format = "compact"
+	// FormatMarkdown renders the title and a markdown table
+	FormatMarkdown Format = "markdown"
+	// FormatCSV renders a CSV table with a header row
+	FormatCSV Format = "csv"
+)
+
+// formats lists the supported formats in the order they are documented
+var formats = []string{string(FormatFull), string(FormatCompact), string(FormatMarkdown), string(FormatCSV)}
+
+// ParseFormat returns the format with the given name, or the fallback if the name is empty
+func ParseFormat(name string, fallback Format) (Format, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if name == "" {
+		return fallback, nil
+	}
+
+	for _, format := range formats {
+		if name == format {
+			return Format(name), nil
+		}
+	}
+
+	return "", fmt.Errorf("unsupported format %q: expected one of %s", name, strings.Join(formats, ", "))
+}
+
+// textView describes how a tool result is shown as text: a title, one compact line per item,
+// and a table for the markdown and CSV formats. A view of a document, such as a calendar, has
+// a body instead of items, shown as is in the compact format and as a code block in markdown.
+type textView struct {
+	Title   string
+	Items   []string
+	Body    string
+	Columns []string
+	Rows    [][]string
+}
+
+// renderText renders a tool result in the given format. The full format renders the structured
+// result itself; the others render the view.
+func renderText(result interface{}, view textView, format Format) (string, error) {
+	switch format {
+	case FormatFull:
+		data, err := json.MarshalIndent(result, "", "  ")
+		if err != nil {
+			return "", fmt.Errorf("marshaling result: %w", err)
+		}
+		return string(data), nil
+	case FormatMarkdown:
+		return renderMarkdown(view), nil
+	case FormatCSV:
+		return renderCSV(view)
+	default:
+		return renderCompact(view), nil
+	}
+}
+
+// renderCompact renders the title followed by a bulleted line per item
+func renderCompact(view textView) string {
+	if view.Body != "" {
+		return view.Body
+	}
+	if len(view.Items) == 0 {
+		return view.Title + "."
+	}
+
+	return view.Title + ":\n- " + strings.Join(view.Items, "\n- ")
+}
+
+// renderMarkdown renders the title followed by a markdown table of the rows
+func renderMarkdown(view textView) string {
+	if view.Body != "" {
+		return fmt.Sprintf("%s:\n\n```\n%s\n```", view.Title, strings.TrimRight(view.Body, "\r\n"))
+	}
+	if len(view.Rows) == 0 {
+		return view.Title + "."
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s:\n\n", view.Title)
+	writeMarkdownRow(&b, view.Columns)
+
+	separators := make([]string, len(view.Columns))
+	for i := range separators {
+		separators[i] = "---"
+	}
+	writeMarkdownRow(&b, separators)
+
+	for _, row := range view.Rows {
+		writeMarkdownRow(&b, row)
+	}
+
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+// writeMarkdownRow writes a table row, escaping characters that would break the table
+func writeMarkdownRow(b *strings.Builder, cells []string) {
+	escaped := make([]string, len(cells))
+	for i, cell := range cells {
+		cell = strings.ReplaceAll(cell, "|", `\|`)
+		escaped[i] = strings.ReplaceAll(cell, "\n", " ")
+	}
+
+	fmt.Fprintf(b, "| %s |\n", strings.Join(escaped, " | "))
+}
+
+// renderCSV renders the rows as CSV with a header row
+func renderCSV(view textView) (string, error) {
+	var b strings.Builder
+	w := csv.NewWriter(&b)
+
+	if err := w.Write(view.Columns); err != nil {
+		return "", fmt.Errorf("writing CSV: %w", err)
+	}
+	if err := w.WriteAll(view.Rows); err != nil {
+		return "", fmt.Errorf("writing CSV: %w", err)
+	}
+
+	return b.String(), nil
+}
@@ -0,0 +1,136 @@
+package mcpserver
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// testView is a small view with a cell that needs escaping in markdown and CSV
+var testView = textView{
+	Title:   "Employee 157 has 2 time-off requests",
+	Items:   []string{"first", "second"},
+	Columns: []string{"ID", "Type"},
+	Rows:    [][]string{{"1", "Vacation"}, {"2", "Home | Office, remote"}},
+}
+
+func TestParseFormat(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected Format
+		hasError bool
+	}{
+		{"", FormatCompact, false},
+		{"full", FormatFull, false},
+		{" Markdown ", FormatMarkdown, false},
+		{"csv", FormatCSV, false},
+		{"xml", "", true},
+	}
+
+	for _, tt := range tests {
+		format, err := ParseFormat(tt.input, FormatCompact)
+		if tt.hasError {
+			if err == nil {
+				t.Errorf("ParseFormat(%q): expected error, but got none", tt.input)
+			}
+			continue
+		}
+
+		if err != nil {
+			t.Errorf("ParseFormat(%q): unexpected error: %v", tt.input, err)
+		}
+
+		if format != tt.expected {
+			t.Errorf("ParseFormat(%q): expected %s, got %s", tt.input, tt.expected, format)
+		}
+	}
+}
+
+func TestRenderText(t *testing.T) {
+	result := map[string]string{"id": "1"}
+
+	tests := []struct {
+		format   Format
+		expected string
+	}{
+		{FormatCompact, "Employee 157 has 2 time-off requests:\n- first\n- second"},
+		{FormatFull, "{\n  \"id\": \"1\"\n}"},
+		{FormatMarkdown, "Employee 157 has 2 time-off requests:\n\n| ID | Type |\n| --- | --- |\n| 1 | Vacation |\n| 2 | Home \\| Office, remote |"},
+		{FormatCSV, "ID,Type\n1,Vacation\n2,\"Home | Office, remote\"\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.format), func(t *testing.T) {
+			text, err := renderText(result, testView, tt.format)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			if text != tt.expected {
+				t.Errorf("Expected:\n%s\ngot:\n%s", tt.expected, text)
+			}
+		})
+	}
+}
+
+func TestRenderText_Empty(t *testing.T) {
+	view := textView{Title: "The directory lists 0 employees", Columns: []string{"ID"}}
+
+	for format, expected := range map[Format]string{
+		FormatCompact:  "The directory lists 0 employees.",
+		FormatMarkdown: "The directory lists 0 employees.",
+		FormatCSV:      "ID\n",
+	} {
+		text, err := renderText(nil, view, format)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		if text != expected {
+			t.Errorf("%s: expected %q, got %q", format, expected, text)
+		}
+	}
+}
+
+func TestListEmployees_Formats(t *testing.T) {
+	_, client := newMockBambooHR(t, map[string]string{
+		"/employees/directory": `{
+			"fields": [],
+			"employees": [{"id": "157", "displayName": "John Doe", "jobTitle": "Engineer", "department": "Engineering"}]
+		}`,
+	})
+	s := New(client, newTestDateParser())
+
+	callText := func(arguments map[string]string) (string, bool) {
+		var result struct {
+			Content []mcp.TextContent `json:"content"`
+			IsError bool              `json:"isError"`
+		}
+		response := sendMessage(t, s, "tools/call", map[string]interface{}{
+			"name":      "list_employees",
+			"arguments": arguments,
+		})
+		if err := json.Unmarshal(response, &result); err != nil {
+			t.Fatalf("Failed to unmarshal result: %v", err)
+		}
+		return result.Content[0].Text, result.IsError
+	}
+
+	if text, _ := callText(map[string]string{}); text != "The directory lists 1 employee:\n- John Doe (ID 157), Engineer, Engineering" {
+		t.Errorf("Expected compact output by default, got %q", text)
+	}
+
+	if text, _ := callText(map[string]string{"format": "full"}); !strings.Contains(text, `"displayName": "John Doe"`) {
+		t.Errorf("Expected full JSON output, got %q", text)
+	}
+
+	if text, _ := callText(map[string]string{"format": "csv"}); !strings.HasPrefix(text, "ID,Name,Job Title") {
+		t.Errorf("Expected CSV output, got %q", text)
+	}
+
+	if _, isError := callText(map[string]string{"format": "yaml"}); !isError {
+		t.Error("Expected tool error for an unsupported format")
+	}
+}
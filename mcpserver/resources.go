@@ -0,0 +1,215 @@
+package mcpserver
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+
+	"bamboohr-mcp-server/bamboohr"
+)
+
+const jsonMIMEType = "application/json"
+
+// registerResources adds the read-only BambooHR resources and resource templates to the server
+func registerResources(s *server.MCPServer, client BambooHR, dates *DateParser) {
+	s.AddResource(mcp.NewResource(
+		"bamboohr://employees",
+		"Employee directory",
+		mcp.WithResourceDescription("All employees in the company directory"),
+		mcp.WithMIMEType(jsonMIMEType),
+	), handleEmployeeDirectoryResource(client))
+
+	s.AddResource(mcp.NewResource(
+		"bamboohr://time-off/types",
+		"Time-off types",
+		mcp.WithResourceDescription("Time-off types configured for the company, including their IDs and units"),
+		mcp.WithMIMEType(jsonMIMEType),
+	), handleTimeOffTypesResource(client))
+
+	s.AddResource(mcp.NewResource(
+		"bamboohr://time-off/policies",
+		"Time-off policies",
+		mcp.WithResourceDescription("Time-off policies configured for the company"),
+		mcp.WithMIMEType(jsonMIMEType),
+	), handleTimeOffPoliciesResource(client))
+
+	s.AddResourceTemplate(mcp.NewResourceTemplate(
+		"bamboohr://employees/{id}",
+		"Employee",
+		mcp.WithTemplateDescription("Profile of a single employee"),
+		mcp.WithTemplateMIMEType(jsonMIMEType),
+	), handleEmployeeResource(client))
+
+	s.AddResourceTemplate(mcp.NewResourceTemplate(
+		"bamboohr://employees/{id}/time-off/balance",
+		"Employee time-off balance",
+		mcp.WithTemplateDescription("Time-off balances of a single employee"),
+		mcp.WithTemplateMIMEType(jsonMIMEType),
+	), handleTimeOffBalanceResource(client))
+
+	s.AddResourceTemplate(mcp.NewResourceTemplate(
+		"bamboohr://employees/{id}/time-off/requests",
+		"Employee time-off requests",
+		mcp.WithTemplateDescription("Time-off requests of a single employee for the current year"),
+		mcp.WithTemplateMIMEType(jsonMIMEType),
+	), handleTimeOffRequestsResource(client, dates))
+
+	s.AddResourceTemplate(mcp.NewResourceTemplate(
+		"bamboohr://holidays/{year}",
+		"Company holidays",
+		mcp.WithTemplateDescription("Company holidays for a calendar year"),
+		mcp.WithTemplateMIMEType(jsonMIMEType),
+	), handleHolidaysResource(client))
+}
+
+// Resource handlers
+
+func handleEmployeeDirectoryResource(client BambooHR) server.ResourceHandlerFunc {
+	return func(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+		directory, err := client.GetEmployeeDirectory()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get employee directory: %w", err)
+		}
+
+		return jsonResourceContents(request.Params.URI, directory)
+	}
+}
+
+func handleTimeOffTypesResource(client BambooHR) server.ResourceHandlerFunc {
+	return func(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+		types, err := client.GetTimeOffTypes()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get time-off types: %w", err)
+		}
+
+		return jsonResourceContents(request.Params.URI, types)
+	}
+}
+
+func handleTimeOffPoliciesResource(client BambooHR) server.ResourceHandlerFunc {
+	return func(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+		policies, err := client.GetTimeOffPolicies()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get time-off policies: %w", err)
+		}
+
+		return jsonResourceContents(request.Params.URI, policies)
+	}
+}
+
+func handleEmployeeResource(client BambooHR) server.ResourceTemplateHandlerFunc {
+	return func(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+		employeeID, err := resourceIntArgument(request, "id")
+		if err != nil {
+			return nil, err
+		}
+
+		employee, err := client.GetEmployee(employeeID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get employee: %w", err)
+		}
+
+		return jsonResourceContents(request.Params.URI, employee)
+	}
+}
+
+func handleTimeOffBalanceResource(client BambooHR) server.ResourceTemplateHandlerFunc {
+	return func(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+		employeeID, err := resourceIntArgument(request, "id")
+		if err != nil {
+			return nil, err
+		}
+
+		balances, err := client.GetTimeOffBalance(employeeID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get time-off balance: %w", err)
+		}
+
+		return jsonResourceContents(request.Params.URI, balances)
+	}
+}
+
+func handleTimeOffRequestsResource(client BambooHR, dates *DateParser) server.ResourceTemplateHandlerFunc {
+	return func(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+		employeeID, err := resourceIntArgument(request, "id")
+		if err != nil {
+			return nil, err
+		}
+
+		// The current year is determined in the employee's timezone
+		year := dates.ForEmployee(employeeID).CurrentYear()
+		requests, err := client.GetTimeOffRequests(employeeID, year.StartYMD(), year.EndYMD())
+		if err != nil {
+			return nil, fmt.Errorf("failed to get time-off requests: %w", err)
+		}
+
+		return jsonResourceContents(request.Params.URI, requests)
+	}
+}
+
+func handleHolidaysResource(client BambooHR) server.ResourceTemplateHandlerFunc {
+	return func(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+		year, err := resourceIntArgument(request, "year")
+		if err != nil {
+			return nil, err
+		}
+
+		entries, err := client.GetWhosOut(fmt.Sprintf("%d-01-01", year), fmt.Sprintf("%d-12-31", year))
+		if err != nil {
+			return nil, fmt.Errorf("failed to get holidays: %w", err)
+		}
+
+		holidays := []bamboohr.WhosOutEntry{}
+		for _, entry := range entries {
+			if entry.Type == "holiday" {
+				holidays = append(holidays, entry)
+			}
+		}
+
+		return jsonResourceContents(request.Params.URI, holidays)
+	}
+}
+
+// resourceIntArgument returns a URI template variable from the request as an integer
+func resourceIntArgument(request mcp.ReadResourceRequest, name string) (int, error) {
+	var value string
+	switch v := request.Params.Arguments[name].(type) {
+	case string:
+		value = v
+	case []string:
+		if len(v) > 0 {
+			value = v[0]
+		}
+	}
+
+	if value == "" {
+		return 0, fmt.Errorf("%s is required in resource URI %s", name, request.Params.URI)
+	}
+
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, fmt.Errorf("%s must be a valid integer", name)
+	}
+
+	return n, nil
+}
+
+// jsonResourceContents marshals v as indented JSON resource contents for uri
+func jsonResourceContents(uri string, v interface{}) ([]mcp.ResourceContents, error) {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal resource: %w", err)
+	}
+
+	return []mcp.ResourceContents{
+		mcp.TextResourceContents{
+			URI:      uri,
+			MIMEType: jsonMIMEType,
+			Text:     string(data),
+		},
+	}, nil
+}
@@ -0,0 +1,293 @@
+package mcpserver
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+
+	"bamboohr-mcp-server/bamboohr"
+)
+
+// newMockBambooHR starts a mock BambooHR API serving fixed JSON responses keyed by request path
+func newMockBambooHR(t *testing.T, responses map[string]string) (*httptest.Server, *bamboohr.Client) {
+	t.Helper()
+
+	mock := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		response, ok := responses[r.URL.Path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte("Not Found"))
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(response))
+	}))
+	t.Cleanup(mock.Close)
+
+	client := bamboohr.NewClient("testcompany", "testkey")
+	client.BaseURL = mock.URL
+	client.V1BaseURL = mock.URL
+
+	return mock, client
+}
+
+// sendMessage sends a JSON-RPC request to the MCP server and returns the raw result
+func sendMessage(t *testing.T, s *server.MCPServer, method string, params interface{}) json.RawMessage {
+	t.Helper()
+
+	message, err := json.Marshal(map[string]interface{}{
+		"jsonrpc": "2.0",
+		"id":      1,
+		"method":  method,
+		"params":  params,
+	})
+	if err != nil {
+		t.Fatalf("Failed to marshal message: %v", err)
+	}
+
+	response := s.HandleMessage(context.Background(), message)
+	data, err := json.Marshal(response)
+	if err != nil {
+		t.Fatalf("Failed to marshal response: %v", err)
+	}
+
+	var envelope struct {
+		Result json.RawMessage `json:"result"`
+		Error  *struct {
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	if err := json.Unmarshal(data, &envelope); err != nil {
+		t.Fatalf("Failed to unmarshal response: %v", err)
+	}
+	if envelope.Error != nil {
+		t.Fatalf("Unexpected error for %s: %s", method, envelope.Error.Message)
+	}
+
+	return envelope.Result
+}
+
+// readResourceText reads a resource through the MCP server and returns its text contents
+func readResourceText(t *testing.T, s *server.MCPServer, uri string) string {
+	t.Helper()
+
+	result := sendMessage(t, s, "resources/read", map[string]string{"uri": uri})
+
+	var read struct {
+		Contents []mcp.TextResourceContents `json:"contents"`
+	}
+	if err := json.Unmarshal(result, &read); err != nil {
+		t.Fatalf("Failed to unmarshal resource contents: %v", err)
+	}
+
+	if len(read.Contents) != 1 {
+		t.Fatalf("Expected 1 content item, got %d", len(read.Contents))
+	}
+
+	if read.Contents[0].URI != uri {
+		t.Errorf("Expected content URI %s, got %s", uri, read.Contents[0].URI)
+	}
+
+	if read.Contents[0].MIMEType != "application/json" {
+		t.Errorf("Expected MIME type application/json, got %s", read.Contents[0].MIMEType)
+	}
+
+	return read.Contents[0].Text
+}
+
+func TestResources_List(t *testing.T) {
+	_, client := newMockBambooHR(t, nil)
+	s := New(client, newTestDateParser())
+
+	var resources struct {
+		Resources []mcp.Resource `json:"resources"`
+	}
+	if err := json.Unmarshal(sendMessage(t, s, "resources/list", map[string]string{}), &resources); err != nil {
+		t.Fatalf("Failed to unmarshal resources: %v", err)
+	}
+
+	expectedResources := map[string]bool{
+		"bamboohr://employees":         false,
+		"bamboohr://time-off/types":    false,
+		"bamboohr://time-off/policies": false,
+	}
+	for _, resource := range resources.Resources {
+		expectedResources[resource.URI] = true
+	}
+	for uri, found := range expectedResources {
+		if !found {
+			t.Errorf("Expected resource %s to be registered", uri)
+		}
+	}
+
+	var templates struct {
+		ResourceTemplates []struct {
+			URITemplate string `json:"uriTemplate"`
+		} `json:"resourceTemplates"`
+	}
+	if err := json.Unmarshal(sendMessage(t, s, "resources/templates/list", map[string]string{}), &templates); err != nil {
+		t.Fatalf("Failed to unmarshal resource templates: %v", err)
+	}
+
+	expectedTemplates := map[string]bool{
+		"bamboohr://employees/{id}":                   false,
+		"bamboohr://employees/{id}/time-off/balance":  false,
+		"bamboohr://employees/{id}/time-off/requests": false,
+		"bamboohr://holidays/{year}":                  false,
+	}
+	for _, template := range templates.ResourceTemplates {
+		expectedTemplates[template.URITemplate] = true
+	}
+	for uri, found := range expectedTemplates {
+		if !found {
+			t.Errorf("Expected resource template %s to be registered", uri)
+		}
+	}
+}
+
+func TestResources_EmployeeDirectory(t *testing.T) {
+	_, client := newMockBambooHR(t, map[string]string{
+		"/employees/directory": `{
+			"fields": [{"id": "displayName", "type": "text", "name": "Display name"}],
+			"employees": [{"id": "157", "displayName": "John Doe", "department": "Engineering"}]
+		}`,
+	})
+	s := New(client, newTestDateParser())
+
+	text := readResourceText(t, s, "bamboohr://employees")
+
+	var directory bamboohr.EmployeeDirectory
+	if err := json.Unmarshal([]byte(text), &directory); err != nil {
+		t.Fatalf("Failed to unmarshal directory: %v", err)
+	}
+
+	if len(directory.Employees) != 1 || directory.Employees[0].DisplayName != "John Doe" {
+		t.Errorf("Expected directory with John Doe, got %+v", directory.Employees)
+	}
+}
+
+func TestResources_TimeOffTypes(t *testing.T) {
+	_, client := newMockBambooHR(t, map[string]string{
+		"/meta/time_off/types": `{
+			"timeOffTypes": [{"id": "27", "name": "Home Office days", "units": "days", "color": null, "icon": "home"}],
+			"defaultHours": [{"name": "Monday", "amount": "8"}]
+		}`,
+	})
+	s := New(client, newTestDateParser())
+
+	var types bamboohr.TimeOffTypes
+	if err := json.Unmarshal([]byte(readResourceText(t, s, "bamboohr://time-off/types")), &types); err != nil {
+		t.Fatalf("Failed to unmarshal time-off types: %v", err)
+	}
+
+	if len(types.TimeOffTypes) != 1 || types.TimeOffTypes[0].Name != "Home Office days" {
+		t.Errorf("Expected Home Office days type, got %+v", types.TimeOffTypes)
+	}
+
+	if len(types.DefaultHours) != 1 || float64(types.DefaultHours[0].Amount) != 8 {
+		t.Errorf("Expected 8 default hours on Monday, got %+v", types.DefaultHours)
+	}
+}
+
+func TestResources_EmployeeTemplates(t *testing.T) {
+	_, client := newMockBambooHR(t, map[string]string{
+		"/employees/157": `{"id": "157", "firstName": "John", "lastName": "Doe", "location": "Sydney"}`,
+		"/employees/157/time_off/calculator": `[
+			{"timeOffType": "1", "name": "Vacation", "units": "days", "balance": "12.5", "end": "2025-12-31", "policyType": "accruing", "usedYearToDate": "3"}
+		]`,
+	})
+	s := New(client, newTestDateParser())
+
+	var employee bamboohr.Employee
+	if err := json.Unmarshal([]byte(readResourceText(t, s, "bamboohr://employees/157")), &employee); err != nil {
+		t.Fatalf("Failed to unmarshal employee: %v", err)
+	}
+
+	if employee.ID != "157" || employee.Location != "Sydney" {
+		t.Errorf("Expected employee 157 in Sydney, got %+v", employee)
+	}
+
+	var balances []bamboohr.TimeOffBalance
+	if err := json.Unmarshal([]byte(readResourceText(t, s, "bamboohr://employees/157/time-off/balance")), &balances); err != nil {
+		t.Fatalf("Failed to unmarshal balances: %v", err)
+	}
+
+	if len(balances) != 1 || float64(balances[0].Balance) != 12.5 {
+		t.Errorf("Expected a 12.5 day balance, got %+v", balances)
+	}
+}
+
+func TestResources_Holidays(t *testing.T) {
+	var query string
+	mock := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.RawQuery
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`[
+			{"id": 1, "type": "timeOff", "employeeId": 157, "name": "John Doe", "start": "2025-12-22", "end": "2025-12-24"},
+			{"id": 2, "type": "holiday", "name": "Christmas Day", "start": "2025-12-25", "end": "2025-12-25"}
+		]`))
+	}))
+	defer mock.Close()
+
+	client := bamboohr.NewClient("testcompany", "testkey")
+	client.BaseURL = mock.URL
+	s := New(client, newTestDateParser())
+
+	var holidays []bamboohr.WhosOutEntry
+	if err := json.Unmarshal([]byte(readResourceText(t, s, "bamboohr://holidays/2025")), &holidays); err != nil {
+		t.Fatalf("Failed to unmarshal holidays: %v", err)
+	}
+
+	if !strings.Contains(query, "start=2025-01-01") || !strings.Contains(query, "end=2025-12-31") {
+		t.Errorf("Expected query for the whole of 2025, got %s", query)
+	}
+
+	if len(holidays) != 1 || holidays[0].Name != "Christmas Day" {
+		t.Errorf("Expected only Christmas Day, got %+v", holidays)
+	}
+}
+
+func TestResourceIntArgument(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    interface{}
+		expected int
+		hasError bool
+	}{
+		{"String slice from URI template", []string{"157"}, 157, false},
+		{"Plain string", "42", 42, false},
+		{"Missing", nil, 0, true},
+		{"Not a number", []string{"abc"}, 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			request := mcp.ReadResourceRequest{}
+			request.Params.URI = "bamboohr://employees/x"
+			request.Params.Arguments = map[string]any{"id": tt.value}
+
+			n, err := resourceIntArgument(request, "id")
+			if tt.hasError {
+				if err == nil {
+					t.Error("Expected error, but got none")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Errorf("Unexpected error: %v", err)
+			}
+
+			if n != tt.expected {
+				t.Errorf("Expected %d, got %d", tt.expected, n)
+			}
+		})
+	}
+}
@@ -0,0 +1,863 @@
+// Package mcpserver exposes BambooHR time off to MCP clients as tools, resources and prompts.
+package mcpserver
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+
+	"bamboohr-mcp-server/bamboohr"
+)
+
+// Version is the version of the MCP server
+const Version = "1.0.0"
+
+// BambooHR is the BambooHR API used by the MCP server. It is implemented by *bamboohr.Client.
+type BambooHR interface {
+	Company() string
+	GetTimeOffRequests(employeeID int, start, end string) ([]bamboohr.TimeOffRequest, error)
+	SearchTimeOffRequests(query bamboohr.TimeOffRequestQuery) ([]bamboohr.TimeOffRequest, error)
+	GetTimeOffBalance(employeeID int) ([]bamboohr.TimeOffBalance, error)
+	CreateTimeOffRequest(employeeID int, request bamboohr.TimeOffRequestCreate) (*bamboohr.TimeOffRequest, error)
+	GetEmployeeDirectory() (*bamboohr.EmployeeDirectory, error)
+	GetEmployee(employeeID int) (*bamboohr.Employee, error)
+	GetChangedEmployees(since time.Time) (*bamboohr.EmployeeChanges, error)
+	GetTimeOffTypes() (*bamboohr.TimeOffTypes, error)
+	GetTimeOffPolicies() ([]bamboohr.TimeOffPolicy, error)
+	GetEmployeeTimeOffPolicies(employeeID int) ([]bamboohr.EmployeeTimeOffPolicy, error)
+	GetWhosOut(start, end string) ([]bamboohr.WhosOutEntry, error)
+}
+
+var _ BambooHR = (*bamboohr.Client)(nil)
+
+// Tool handlers
+
+func handleGetTimeOffRequests(client BambooHR, dates *DateParser, hoursPerDay float64) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		employeeIDStr, err := request.RequireString("employeeId")
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("employeeId is required: %s", err.Error())), nil
+		}
+
+		employeeID, err := strconv.Atoi(employeeIDStr)
+		if err != nil {
+			return mcp.NewToolResultError("employeeId must be a valid integer"), nil
+		}
+
+		locale, err := toolLocale(request, dates)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		format, err := toolFormat(request, FormatCompact)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		// Relative dates and the current year default are resolved in the employee's timezone
+		parser := dates.ForEmployee(employeeID)
+		period, err := parser.ResolveRange(request.GetString("start", ""), request.GetString("end", ""), parser.CurrentYear())
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		requests, err := client.GetTimeOffRequests(employeeID, period.StartYMD(), period.EndYMD())
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Failed to get time-off requests: %s", err.Error())), nil
+		}
+
+		workDay := employeeWorkDay(client, employeeID, hoursPerDay)
+
+		result := TimeOffRequestsResult{
+			EmployeeID: employeeIDStr,
+			Start:      period.StartYMD(),
+			End:        period.EndYMD(),
+			Requests:   requests,
+			WorkDay:    workDay,
+		}
+
+		return renderToolResult(result, timeOffRequestsView(result, locale), format), nil
+	}
+}
+
+func handleGetTimeOffBalance(client BambooHR, dates *DateParser, hoursPerDay float64) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		employeeIDStr, err := request.RequireString("employeeId")
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("employeeId is required: %s", err.Error())), nil
+		}
+
+		employeeID, err := strconv.Atoi(employeeIDStr)
+		if err != nil {
+			return mcp.NewToolResultError("employeeId must be a valid integer"), nil
+		}
+
+		locale, err := toolLocale(request, dates)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		format, err := toolFormat(request, FormatCompact)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		balances, err := client.GetTimeOffBalance(employeeID)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Failed to get time-off balance: %s", err.Error())), nil
+		}
+
+		workDay := employeeWorkDay(client, employeeID, hoursPerDay)
+
+		result := TimeOffBalanceResult{
+			EmployeeID: employeeIDStr,
+			Balances:   balances,
+			WorkDay:    workDay,
+		}
+
+		return renderToolResult(result, timeOffBalancesView(result, locale), format), nil
+	}
+}
+
+func handleListEmployees(client BambooHR) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		format, err := toolFormat(request, FormatCompact)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		directory, err := client.GetEmployeeDirectory()
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Failed to list employees: %s", err.Error())), nil
+		}
+
+		return renderToolResult(directory, employeeDirectoryView(directory), format), nil
+	}
+}
+
+func handleCreateTimeOffRequest(client BambooHR, dates *DateParser, hoursPerDay float64) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		employeeIDStr, err := request.RequireString("employeeId")
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("employeeId is required: %s", err.Error())), nil
+		}
+
+		employeeID, err := strconv.Atoi(employeeIDStr)
+		if err != nil {
+			return mcp.NewToolResultError("employeeId must be a valid integer"), nil
+		}
+
+		timeOffTypeIDStr, err := request.RequireString("timeOffTypeId")
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("timeOffTypeId is required: %s", err.Error())), nil
+		}
+
+		timeOffTypeID, err := strconv.Atoi(timeOffTypeIDStr)
+		if err != nil {
+			return mcp.NewToolResultError("timeOffTypeId must be a valid integer"), nil
+		}
+
+		start, err := request.RequireString("start")
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("start date is required: %s", err.Error())), nil
+		}
+
+		locale, err := toolLocale(request, dates)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		format, err := toolFormat(request, FormatFull)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		// The end date defaults to the end of the start expression, e.g. "Dec 23 - Jan 2".
+		// Relative dates such as "tomorrow" are resolved in the employee's timezone.
+		period, err := dates.ForEmployee(employeeID).ResolveRange(start, request.GetString("end", ""), DateRange{})
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		amount, err := strconv.ParseFloat(request.GetString("amount", "1"), 64)
+		if err != nil {
+			return mcp.NewToolResultError("amount must be a positive number, e.g. '1', '0.5' or '4'"), nil
+		}
+
+		result, err := createTimeOffRequest(client, timeOffRequestInput{
+			EmployeeID:    employeeID,
+			TimeOffTypeID: timeOffTypeID,
+			Period:        period,
+			Amount:        amount,
+			Unit:          request.GetString("unit", ""),
+			Note:          request.GetString("employeeNote", ""),
+		}, hoursPerDay)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		return renderToolResult(result, createdTimeOffRequestView(employeeID, result, locale), format), nil
+	}
+}
+
+// timeOffRequestInput is a time-off request to create, as given to a tool
+type timeOffRequestInput struct {
+	EmployeeID    int
+	TimeOffTypeID int
+	Period        DateRange
+	Amount        float64
+	// Unit is days or hours, empty for the units of the time off type
+	Unit string
+	Note string
+	// PreviousRequest is the ID of a request the new request supersedes
+	PreviousRequest int
+}
+
+// preparedTimeOffRequest is a validated time-off request ready to be created
+type preparedTimeOffRequest struct {
+	EmployeeID int
+	Payload    bamboohr.TimeOffRequestCreate
+	// Units are the units of the time off type, which the payload's amount is in
+	Units   string
+	WorkDay WorkDay
+}
+
+// prepareTimeOffRequest validates the input and builds the request to create, with its amount
+// converted to the units of the time off type with the employee's working day
+func prepareTimeOffRequest(client BambooHR, input timeOffRequestInput, hoursPerDay float64) (preparedTimeOffRequest, error) {
+	if input.Amount <= 0 {
+		return preparedTimeOffRequest{}, fmt.Errorf("amount must be a positive number, e.g. '1', '0.5' or '4'")
+	}
+
+	types, err := client.GetTimeOffTypes()
+	if err != nil {
+		return preparedTimeOffRequest{}, fmt.Errorf("failed to get time off types: %w", err)
+	}
+	typeUnits := "days"
+	for _, timeOffType := range types.TimeOffTypes {
+		if timeOffType.ID == strconv.Itoa(input.TimeOffTypeID) && timeOffType.Units != "" {
+			typeUnits = timeOffType.Units
+		}
+	}
+
+	unit := input.Unit
+	if unit == "" {
+		unit = typeUnits
+	}
+	if !isHours(unit) && !strings.HasPrefix(strings.ToLower(unit), "day") {
+		return preparedTimeOffRequest{}, fmt.Errorf("unit must be 'days' or 'hours', got %q", unit)
+	}
+
+	workDay := employeeWorkDay(client, input.EmployeeID, hoursPerDay)
+	amount := roundAmount(workDay.convert(input.Amount, unit, typeUnits))
+
+	var notes []bamboohr.Note
+	if input.Note != "" {
+		notes = append(notes, bamboohr.Note{From: "employee", Note: input.Note})
+	}
+
+	return preparedTimeOffRequest{
+		EmployeeID: input.EmployeeID,
+		Payload: bamboohr.TimeOffRequestCreate{
+			Status:          "requested",
+			Start:           input.Period.StartYMD(),
+			End:             input.Period.EndYMD(),
+			TimeOffTypeID:   input.TimeOffTypeID,
+			Amount:          amount,
+			Notes:           notes,
+			Dates:           workingDayAmounts(input.Period, amount),
+			PreviousRequest: input.PreviousRequest,
+		},
+		Units:   typeUnits,
+		WorkDay: workDay,
+	}, nil
+}
+
+// create sends the prepared request to BambooHR
+func (p preparedTimeOffRequest) create(client BambooHR) (CreatedTimeOffRequest, error) {
+	created, err := client.CreateTimeOffRequest(p.EmployeeID, p.Payload)
+	if err != nil {
+		return CreatedTimeOffRequest{}, fmt.Errorf("failed to create time-off request: %w", err)
+	}
+
+	result := CreatedTimeOffRequest{TimeOffRequest: *created, WorkDay: p.WorkDay}
+	if result.Amount.Unit == "" {
+		result.Amount.Unit = p.Units
+	}
+	result.Duration = p.WorkDay.amount(float64(result.Amount.Amount), result.Amount.Unit)
+	return result, nil
+}
+
+// createTimeOffRequest validates the input and creates the request
+func createTimeOffRequest(client BambooHR, input timeOffRequestInput, hoursPerDay float64) (CreatedTimeOffRequest, error) {
+	prepared, err := prepareTimeOffRequest(client, input, hoursPerDay)
+	if err != nil {
+		return CreatedTimeOffRequest{}, err
+	}
+	return prepared.create(client)
+}
+
+// toolLocale returns the locale requested by the optional locale argument, or the configured default
+func toolLocale(request mcp.CallToolRequest, dates *DateParser) (Locale, error) {
+	name := request.GetString("locale", "")
+	if name == "" {
+		return dates.Locale(), nil
+	}
+
+	return ParseLocale(name)
+}
+
+// toolFormat returns the format requested by the optional format argument, or the tool's default
+func toolFormat(request mcp.CallToolRequest, fallback Format) (Format, error) {
+	return ParseFormat(request.GetString("format", ""), fallback)
+}
+
+// renderToolResult returns the result as text content rendered in the format. The full format
+// also returns the result as structured content; the others leave it out, as they are asked for
+// to keep responses short.
+func renderToolResult(result interface{}, view textView, format Format) *mcp.CallToolResult {
+	text, err := renderText(result, view, format)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to render response: %s", err.Error()))
+	}
+
+	if format != FormatFull {
+		return mcp.NewToolResultText(text)
+	}
+	return mcp.NewToolResultStructured(result, text)
+}
+
+// localeArgumentDescription describes the optional locale argument of tools that return dates
+const localeArgumentDescription = "Locale to format dates in the response for, e.g. 'en-US', 'en-GB' or 'de-DE'. Optional, defaults to the configured locale (ISO YYYY-MM-DD)."
+
+// Option configures the MCP server
+type Option func(*options)
+
+type options struct {
+	currentEmployeeID int
+	hoursPerDay       float64
+	templates         *TemplateStore
+	offline           *offlineClient
+}
+
+// WithCurrentEmployee sets the employee the server acts for, so that "me" can be given in place
+// of an employee ID, e.g. to ask for "my reports"
+func WithCurrentEmployee(employeeID int) Option {
+	return func(o *options) {
+		o.currentEmployeeID = employeeID
+	}
+}
+
+// WithHoursPerDay sets the length of a working day used to convert time off between days and
+// hours for employees whose profile has no standard hours
+func WithHoursPerDay(hours float64) Option {
+	return func(o *options) {
+		o.hoursPerDay = hours
+	}
+}
+
+// WithTemplates adds the tools that save, list, apply and delete request templates kept in the
+// template store. Templates belong to the current employee set by WithCurrentEmployee, and the
+// tools refuse to run without one.
+func WithTemplates(templates *TemplateStore) Option {
+	return func(o *options) {
+		o.templates = templates
+	}
+}
+
+// New creates the MCP server and registers all tools, resources and prompts
+func New(client BambooHR, dates *DateParser, opts ...Option) *server.MCPServer {
+	var config options
+	for _, opt := range opts {
+		opt(&config)
+	}
+	me := config.currentEmployeeID
+	hoursPerDay := config.hoursPerDay
+
+	completions := newCompletionProvider(client, dates)
+
+	serverOpts := []server.ServerOption{
+		server.WithToolCapabilities(true),
+		server.WithResourceCapabilities(false, false),
+		server.WithPromptCapabilities(false),
+		server.WithCompletions(),
+		server.WithPromptCompletionProvider(completions),
+		server.WithResourceCompletionProvider(completions),
+	}
+	if config.offline != nil {
+		serverOpts = append(serverOpts, server.WithToolHandlerMiddleware(config.offline.notice))
+	}
+
+	s := server.NewMCPServer("BambooHR Time-Off MCP Server", Version, serverOpts...)
+
+	// Define tools
+	getTimeOffRequestsTool := mcp.NewTool(
+		"get_time_off_requests",
+		mcp.WithDescription("Get time-off requests for an employee"),
+		mcp.WithOutputSchema[TimeOffRequestsResult](),
+		mcp.WithTitleAnnotation("Get Time-Off Requests"),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithIdempotentHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(true),
+		mcp.WithString("employeeId",
+			mcp.Required(),
+			mcp.Description("The ID of the employee to get time-off requests for"),
+		),
+		mcp.WithString("start",
+			mcp.Description("Start date for filtering requests (YYYY-MM-DD or an expression like 'today', 'next friday' or 'Dec 23'). A period such as 'this quarter' or 'Dec 23 - Jan 2' may be given without an end. Optional, defaults to the current year."),
+		),
+		mcp.WithString("end",
+			mcp.Description("End date for filtering requests (YYYY-MM-DD or an expression like 'end of next month'). Optional."),
+		),
+		mcp.WithString("locale",
+			mcp.Description(localeArgumentDescription),
+		),
+		mcp.WithString("format",
+			mcp.Description("Response format: 'compact' (default) for a short line per item, 'full' for the complete JSON and structured content, 'markdown' for a table or 'csv'"),
+			mcp.Enum(formats...),
+		),
+	)
+
+	getTimeOffBalanceTool := mcp.NewTool(
+		"get_time_off_balance",
+		mcp.WithDescription("Get time-off balance for an employee"),
+		mcp.WithOutputSchema[TimeOffBalanceResult](),
+		mcp.WithTitleAnnotation("Get Time-Off Balance"),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithIdempotentHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(true),
+		mcp.WithString("employeeId",
+			mcp.Required(),
+			mcp.Description("The ID of the employee to get time-off balance for"),
+		),
+		mcp.WithString("locale",
+			mcp.Description(localeArgumentDescription),
+		),
+		mcp.WithString("format",
+			mcp.Description("Response format: 'compact' (default) for a short line per item, 'full' for the complete JSON and structured content, 'markdown' for a table or 'csv'"),
+			mcp.Enum(formats...),
+		),
+	)
+
+	listEmployeesTool := mcp.NewTool(
+		"list_employees",
+		mcp.WithDescription("List all employees in the company directory"),
+		mcp.WithOutputSchema[bamboohr.EmployeeDirectory](),
+		mcp.WithTitleAnnotation("List Employees"),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithIdempotentHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(true),
+		mcp.WithString("format",
+			mcp.Description("Response format: 'compact' (default) for a short line per item, 'full' for the complete JSON and structured content, 'markdown' for a table or 'csv'"),
+			mcp.Enum(formats...),
+		),
+	)
+
+	createTimeOffRequestTool := mcp.NewTool(
+		"create_time_off_request",
+		mcp.WithDescription("Create a new time-off request for an employee"),
+		mcp.WithOutputSchema[CreatedTimeOffRequest](),
+		mcp.WithTitleAnnotation("Create Time-Off Request"),
+		mcp.WithReadOnlyHintAnnotation(false),
+		mcp.WithDestructiveHintAnnotation(true),
+		mcp.WithIdempotentHintAnnotation(false),
+		mcp.WithOpenWorldHintAnnotation(true),
+		mcp.WithString("employeeId",
+			mcp.Required(),
+			mcp.Description("The ID of the employee to create the time-off request for"),
+		),
+		mcp.WithString("timeOffTypeId",
+			mcp.Required(),
+			mcp.Description("The ID of the time-off type (e.g., '1' for Vacation, '27' for Home Office days)"),
+		),
+		mcp.WithString("start",
+			mcp.Required(),
+			mcp.Description("Start date for the time-off request (YYYY-MM-DD or an expression like 'next friday'). A range such as 'Dec 23 - Jan 2' may be given without an end."),
+		),
+		mcp.WithString("end",
+			mcp.Description("End date for the time-off request (YYYY-MM-DD or an expression like 'friday'). Defaults to the end of start."),
+		),
+		mcp.WithString("amount",
+			mcp.Description("The amount of time off (e.g., '1', '0.5', '2.5'). Optional, defaults to 1."),
+		),
+		mcp.WithString("unit",
+			mcp.Description("Whether amount is in days or hours. Optional, defaults to the units of the time-off type. Amounts are converted with the employee's working day."),
+			mcp.Enum("days", "hours"),
+		),
+		mcp.WithString("employeeNote",
+			mcp.Description("Optional note from the employee about the request"),
+		),
+		mcp.WithString("locale",
+			mcp.Description(localeArgumentDescription),
+		),
+		mcp.WithString("format",
+			mcp.Description("Response format: 'full' (default) for the complete JSON, 'compact' for a short summary, 'markdown' for a table or 'csv'"),
+			mcp.Enum(formats...),
+		),
+	)
+
+	updateTimeOffRequestTool := mcp.NewTool(
+		"update_time_off_request",
+		mcp.WithDescription("Change the dates, amount or note of an existing time-off request. BambooHR replaces the request with a new one that supersedes it, so the updated request has a new ID and goes through approval again."),
+		mcp.WithOutputSchema[UpdatedTimeOffRequest](),
+		mcp.WithTitleAnnotation("Update Time-Off Request"),
+		mcp.WithReadOnlyHintAnnotation(false),
+		mcp.WithDestructiveHintAnnotation(true),
+		mcp.WithIdempotentHintAnnotation(false),
+		mcp.WithOpenWorldHintAnnotation(true),
+		mcp.WithString("employeeId",
+			mcp.Required(),
+			mcp.Description("The ID of the employee who made the request, or 'me' for the current employee"),
+		),
+		mcp.WithString("requestId",
+			mcp.Required(),
+			mcp.Description("The ID of the time-off request to change. Only requests from the start of last year to the end of next year can be found."),
+		),
+		mcp.WithString("start",
+			mcp.Description("New start date (YYYY-MM-DD or an expression like 'next friday'). A range such as 'Dec 23 - Jan 2' may be given without an end. Optional, defaults to the current dates."),
+		),
+		mcp.WithString("end",
+			mcp.Description("New end date. Optional, defaults to the current end date, or the end of start when a new start is given."),
+		),
+		mcp.WithString("amount",
+			mcp.Description("New amount of time off (e.g., '1', '0.5', '2.5'). Optional, defaults to the current amount, or to the working days of the new dates when they change."),
+		),
+		mcp.WithString("unit",
+			mcp.Description("Whether amount is in days or hours. Only used with amount. Optional, defaults to the units of the time-off type."),
+			mcp.Enum("days", "hours"),
+		),
+		mcp.WithString("employeeNote",
+			mcp.Description("New note from the employee. Optional, defaults to the current note; an empty string removes it."),
+		),
+		mcp.WithString("locale",
+			mcp.Description(localeArgumentDescription),
+		),
+		mcp.WithString("format",
+			mcp.Description("Response format: 'full' (default) for the complete JSON, 'compact' for a short summary, 'markdown' for a table or 'csv'"),
+			mcp.Enum(formats...),
+		),
+	)
+
+	bulkCreateTimeOffTool := mcp.NewTool(
+		"bulk_create_time_off",
+		mcp.WithDescription("Create many time-off requests for an employee at once, e.g. a home office day every Friday. Entries come from a list of dates, a recurrence rule or CSV. Every entry is validated first, entries overlapping existing time off or each other or exceeding the balance are skipped, and the result reports what happened to each entry."),
+		mcp.WithOutputSchema[BulkTimeOffResult](),
+		mcp.WithTitleAnnotation("Bulk Create Time Off"),
+		mcp.WithReadOnlyHintAnnotation(false),
+		mcp.WithDestructiveHintAnnotation(true),
+		mcp.WithIdempotentHintAnnotation(false),
+		mcp.WithOpenWorldHintAnnotation(true),
+		mcp.WithString("employeeId",
+			mcp.Required(),
+			mcp.Description("The ID of the employee to create the time off for, or 'me' for the current employee"),
+		),
+		mcp.WithString("timeOffType",
+			mcp.Required(),
+			mcp.Description("The time off type ID or name, e.g. '27' or 'Home Office'"),
+		),
+		mcp.WithString("dates",
+			mcp.Description("Dates or ranges separated by semicolons or new lines, e.g. '2025-10-03; Oct 10; Dec 22 - Dec 24'"),
+		),
+		mcp.WithString("recurrence",
+			mcp.Description("A weekly rule such as 'every friday until june', 'every other monday from oct 6 until dec 31' or 'every tuesday and thursday through 2025-12-19'. An end date is included, while a month such as 'june' ends the rule before it starts."),
+		),
+		mcp.WithString("csv",
+			mcp.Description("CSV rows of start, end, amount and note, or a header row naming any of start, end, amount, unit and note. Only start is required."),
+		),
+		mcp.WithString("amount",
+			mcp.Description("The amount of each entry (e.g., '1', '0.5'). Optional, defaults to the working days (Monday to Friday) in each entry."),
+		),
+		mcp.WithString("unit",
+			mcp.Description("Whether amount is in days or hours. Optional, defaults to the units of the time-off type."),
+			mcp.Enum("days", "hours"),
+		),
+		mcp.WithString("employeeNote",
+			mcp.Description("Optional note from the employee on every entry"),
+		),
+		mcp.WithBoolean("dryRun",
+			mcp.Description("Run the checks without creating anything. Optional, defaults to false."),
+		),
+		mcp.WithBoolean("ignoreBalance",
+			mcp.Description("Create entries even when they exceed the balance. Optional, defaults to false."),
+		),
+		mcp.WithString("locale",
+			mcp.Description(localeArgumentDescription),
+		),
+		mcp.WithString("format",
+			mcp.Description("Response format: 'compact' (default) for a line per entry, 'full' for the complete JSON and structured content, 'markdown' for a table or 'csv'"),
+			mcp.Enum(formats...),
+		),
+	)
+
+	exportTimeOffICalTool := mcp.NewTool(
+		"export_time_off_ical",
+		mcp.WithDescription("Export the time-off requests of an employee or team as an iCalendar (RFC 5545) file for import into calendar tools. Re-importing updates existing events."),
+		mcp.WithOutputSchema[TimeOffCalendarResult](),
+		mcp.WithTitleAnnotation("Export Time Off as iCalendar"),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithIdempotentHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(true),
+		mcp.WithString("employeeIds",
+			mcp.Description("Comma-separated IDs of the employees to export, e.g. '157' or '157,158'. One of employeeIds, department or managerId is required."),
+		),
+		mcp.WithString("department",
+			mcp.Description("Export everyone in this department instead of a list of IDs"),
+		),
+		mcp.WithString("managerId",
+			mcp.Description("Export everyone reporting to this manager, directly or indirectly, instead of a list of IDs. Use 'me' for your own reports."),
+		),
+		mcp.WithString("start",
+			mcp.Description("Start of the period to export (YYYY-MM-DD or an expression like 'this quarter'). Optional, defaults to the current year."),
+		),
+		mcp.WithString("end",
+			mcp.Description("End of the period to export (YYYY-MM-DD or an expression like 'end of next month'). Optional."),
+		),
+		mcp.WithString("format",
+			mcp.Description("Response format: 'compact' (default) for the calendar itself, 'full' for the complete JSON and structured content, 'markdown' for the calendar in a code block or 'csv'"),
+			mcp.Enum(formats...),
+		),
+	)
+
+	getTeamTimeOffTool := mcp.NewTool(
+		"get_team_time_off",
+		mcp.WithDescription("Get the time-off requests of a team: a list of employees, a department or a manager's reports"),
+		mcp.WithOutputSchema[TeamTimeOffResult](),
+		mcp.WithTitleAnnotation("Get Team Time Off"),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithIdempotentHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(true),
+		mcp.WithString("employeeIds",
+			mcp.Description("Comma-separated employee IDs, e.g. '157,158'. One of employeeIds, department or managerId is required."),
+		),
+		mcp.WithString("department",
+			mcp.Description("Get the time off of everyone in this department instead of a list of IDs"),
+		),
+		mcp.WithString("managerId",
+			mcp.Description("Get the time off of everyone reporting to this manager, directly or indirectly. Use 'me' for your own reports."),
+		),
+		mcp.WithString("start",
+			mcp.Description("Start of the period (YYYY-MM-DD or an expression like 'next month'). Optional, defaults to the current year."),
+		),
+		mcp.WithString("end",
+			mcp.Description("End of the period (YYYY-MM-DD or an expression). Optional."),
+		),
+		mcp.WithString("timeOffType",
+			mcp.Description("Comma-separated time off type IDs or names, e.g. 'Vacation' or '78,83'. Optional, defaults to every type."),
+		),
+		mcp.WithString("status",
+			mcp.Description("Comma-separated request statuses, e.g. 'approved'. Optional, defaults to 'approved,requested'."),
+		),
+		mcp.WithString("locale",
+			mcp.Description(localeArgumentDescription),
+		),
+		mcp.WithString("format",
+			mcp.Description("Response format: 'compact' (default) for a short line per request, 'full' for the complete JSON and structured content, 'markdown' for a table or 'csv'"),
+			mcp.Enum(formats...),
+		),
+	)
+
+	analyzeCoverageTool := mcp.NewTool(
+		"analyze_coverage",
+		mcp.WithDescription("Check whether enough of a team is available on each working day of a period, flagging days below a minimum headcount and the time off that causes the shortfall. The summary lists only the days below the minimum; tables list every working day."),
+		mcp.WithOutputSchema[CoverageResult](),
+		mcp.WithTitleAnnotation("Analyze Team Coverage"),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithIdempotentHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(true),
+		mcp.WithString("employeeIds",
+			mcp.Description("Comma-separated employee IDs of the team, e.g. the on-call rotation. One of employeeIds, department or managerId is required."),
+		),
+		mcp.WithString("department",
+			mcp.Description("Analyze everyone in this department instead of a list of IDs"),
+		),
+		mcp.WithString("managerId",
+			mcp.Description("Analyze everyone reporting to this manager, directly or indirectly. Use 'me' for your own reports."),
+		),
+		mcp.WithString("start",
+			mcp.Description("Start of the period (YYYY-MM-DD or an expression like 'next month'). Optional, defaults to the next four weeks."),
+		),
+		mcp.WithString("end",
+			mcp.Description("End of the period (YYYY-MM-DD or an expression). Optional."),
+		),
+		mcp.WithNumber("minimum",
+			mcp.Description("Minimum number of team members who must be available each working day. Optional, defaults to half the team, rounded up."),
+			mcp.Min(1),
+		),
+		mcp.WithBoolean("includeRequested",
+			mcp.Description("Also count time off waiting for approval as absent. Optional, defaults to false."),
+		),
+		mcp.WithBoolean("includeWeekends",
+			mcp.Description("Also check Saturdays and Sundays. Optional, defaults to false."),
+		),
+		mcp.WithString("locale",
+			mcp.Description(localeArgumentDescription),
+		),
+		mcp.WithString("format",
+			mcp.Description("Response format: 'compact' (default) for the days below the minimum, 'full' for the complete JSON and structured content, 'markdown' for a table or 'csv'"),
+			mcp.Enum(formats...),
+		),
+	)
+
+	reportTimeOffUsageTool := mcp.NewTool(
+		"report_time_off_usage",
+		mcp.WithDescription("Report the time off taken, grouped by any combination of employee, department, type, month and status, with summary statistics, e.g. days taken per type per department this year. Use the markdown or csv format for tables."),
+		mcp.WithOutputSchema[UsageReportResult](),
+		mcp.WithTitleAnnotation("Report Time-Off Usage"),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithIdempotentHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(true),
+		mcp.WithString("groupBy",
+			mcp.Description("Comma-separated dimensions to group by: employee, department, type, month and status, e.g. 'department,type'. Optional, defaults to 'type'."),
+		),
+		mcp.WithString("start",
+			mcp.Description("Start of the period (YYYY-MM-DD or an expression like 'last year' or 'Q1'). Optional, defaults to the current year."),
+		),
+		mcp.WithString("end",
+			mcp.Description("End of the period (YYYY-MM-DD or an expression). Optional."),
+		),
+		mcp.WithString("employeeIds",
+			mcp.Description("Comma-separated employee IDs to report on. Optional, defaults to the whole company."),
+		),
+		mcp.WithString("department",
+			mcp.Description("Only report on the employees in this department. Optional."),
+		),
+		mcp.WithString("managerId",
+			mcp.Description("Only report on the employees reporting to this manager, directly or indirectly. Use 'me' for your own reports. Optional."),
+		),
+		mcp.WithString("timeOffType",
+			mcp.Description("Comma-separated time off type IDs or names to include. Optional, defaults to every type."),
+		),
+		mcp.WithBoolean("excludeRejected",
+			mcp.Description("Leave out denied, cancelled and superseded requests. Optional, defaults to true."),
+		),
+		mcp.WithBoolean("normalizeToDays",
+			mcp.Description("Convert time off taken in hours to days. Optional, defaults to true."),
+		),
+		mcp.WithNumber("hoursPerDay",
+			mcp.Description("Hours in a working day when converting hours to days. Optional, defaults to the company's default hours for each weekday, or 8."),
+			mcp.Min(0),
+			mcp.Max(24),
+		),
+		mcp.WithString("locale",
+			mcp.Description(localeArgumentDescription),
+		),
+		mcp.WithString("format",
+			mcp.Description("Response format: 'compact' (default) for a short line per group, 'full' for the complete JSON and structured content, 'markdown' for a table or 'csv'"),
+			mcp.Enum(formats...),
+		),
+	)
+
+	reportUnusedLeaveTool := mcp.NewTool(
+		"report_unused_leave",
+		mcp.WithDescription("Find employees who may need a break: no approved vacation in the last months, a vacation balance that expires soon, or a balance approaching the accrual cap"),
+		mcp.WithOutputSchema[UnusedLeaveResult](),
+		mcp.WithTitleAnnotation("Report Unused Leave"),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithIdempotentHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(true),
+		mcp.WithString("employeeIds",
+			mcp.Description("Comma-separated employee IDs to check. Optional, defaults to the whole company. At most 50 employees are checked per call, so larger companies must give employeeIds, department or managerId."),
+		),
+		mcp.WithString("department",
+			mcp.Description("Only check the employees in this department. Optional."),
+		),
+		mcp.WithString("managerId",
+			mcp.Description("Only check the employees reporting to this manager, directly or indirectly. Use 'me' for your own reports. Optional."),
+		),
+		mcp.WithString("vacationType",
+			mcp.Description("Comma-separated time off type IDs or names that count as vacation. Optional, defaults to 'Vacation'."),
+		),
+		mcp.WithNumber("months",
+			mcp.Description("Flag employees without approved vacation in this many months. Optional, defaults to 6."),
+			mcp.Min(1),
+			mcp.Max(24),
+		),
+		mcp.WithNumber("expiringWithinDays",
+			mcp.Description("Flag balances that expire within this many days. Optional, defaults to 60."),
+			mcp.Min(0),
+			mcp.Max(366),
+		),
+		mcp.WithString("policyYearEnd",
+			mcp.Description("Month and day (MM-DD) unused balances expire, used when BambooHR doesn't give a future end date for the balance. Optional, defaults to '12-31'."),
+		),
+		mcp.WithNumber("accrualCap",
+			mcp.Description("Most time off a balance can accrue, in the balance's units. Balances within 10% of it are flagged. Optional."),
+			mcp.Min(0),
+		),
+		mcp.WithString("locale",
+			mcp.Description(localeArgumentDescription),
+		),
+		mcp.WithString("format",
+			mcp.Description("Response format: 'compact' (default) for a short line per employee, 'full' for the complete JSON and structured content, 'markdown' for a table or 'csv'"),
+			mcp.Enum(formats...),
+		),
+	)
+
+	listPendingApprovalsTool := mcp.NewTool(
+		"list_pending_approvals",
+		mcp.WithDescription("List the time-off requests waiting for your approval, with each employee's remaining balance and the time off of their team that overlaps the request"),
+		mcp.WithOutputSchema[PendingApprovalsResult](),
+		mcp.WithTitleAnnotation("List Pending Approvals"),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithIdempotentHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(true),
+		mcp.WithString("managerId",
+			mcp.Description("Only list requests of employees reporting to this manager, directly or indirectly. Use 'me' for your own reports (the default when the current employee is configured) or 'all' for every request you may approve."),
+		),
+		mcp.WithString("start",
+			mcp.Description("Start of the period to search (YYYY-MM-DD or an expression like 'next month'). Optional, defaults to today."),
+		),
+		mcp.WithString("end",
+			mcp.Description("End of the period to search (YYYY-MM-DD or an expression). Optional, defaults to a year from the start."),
+		),
+		mcp.WithString("locale",
+			mcp.Description(localeArgumentDescription),
+		),
+		mcp.WithString("format",
+			mcp.Description("Response format: 'compact' (default) for a short line per request, 'full' for the complete JSON and structured content, 'markdown' for a table or 'csv'"),
+			mcp.Enum(formats...),
+		),
+	)
+
+	// Add tools to server
+	s.AddTool(getTimeOffRequestsTool, handleGetTimeOffRequests(client, dates, hoursPerDay))
+	s.AddTool(getTimeOffBalanceTool, handleGetTimeOffBalance(client, dates, hoursPerDay))
+	s.AddTool(listEmployeesTool, handleListEmployees(client))
+	s.AddTool(createTimeOffRequestTool, handleCreateTimeOffRequest(client, dates, hoursPerDay))
+	s.AddTool(updateTimeOffRequestTool, handleUpdateTimeOffRequest(client, dates, me, hoursPerDay))
+	s.AddTool(bulkCreateTimeOffTool, handleBulkCreateTimeOff(client, dates, me, hoursPerDay))
+	s.AddTool(exportTimeOffICalTool, handleExportTimeOffICal(client, dates, me))
+	s.AddTool(getTeamTimeOffTool, handleGetTeamTimeOff(client, dates, me, hoursPerDay))
+	s.AddTool(analyzeCoverageTool, handleAnalyzeCoverage(client, dates, me))
+	s.AddTool(reportTimeOffUsageTool, handleReportTimeOffUsage(client, dates, me, hoursPerDay))
+	s.AddTool(reportUnusedLeaveTool, handleReportUnusedLeave(client, dates, me))
+	s.AddTool(listPendingApprovalsTool, handleListPendingApprovals(client, dates, me, hoursPerDay))
+
+	registerOrgChartTools(s, client, me)
+	registerPolicyTools(s, client, dates, me)
+	if config.templates != nil {
+		registerTemplateTools(s, config.templates, client, dates, me, hoursPerDay)
+	}
+
+	// Add resources to server
+	registerResources(s, client, dates)
+
+	// Add prompts to server
+	registerPrompts(s, dates)
+
+	return s
+}
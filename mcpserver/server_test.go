@@ -0,0 +1,247 @@
+package mcpserver
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+
+	"bamboohr-mcp-server/bamboohr"
+	"bamboohr-mcp-server/store"
+)
+
+func TestVersion(t *testing.T) {
+	if Version == "" {
+		t.Error("Version should not be empty")
+	}
+
+	// Version should be in format X.Y.Z
+	if len(Version) < 5 {
+		t.Errorf("Version '%s' seems too short", Version)
+	}
+}
+
+func TestTools_Annotations(t *testing.T) {
+	templates, err := OpenTemplateStore(filepath.Join(t.TempDir(), "templates.json"))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	st, err := store.Open(filepath.Join(t.TempDir(), "bamboohr.db"))
+	if err != nil {
+		t.Fatalf("Failed to open store: %v", err)
+	}
+	defer st.Close()
+
+	s := New(bamboohr.NewClient("testcompany", "testkey"), newTestDateParser(), WithTemplates(templates))
+	RegisterStoreTools(s, st, newTestDateParser())
+
+	type hints struct {
+		ReadOnly, Destructive, Idempotent, OpenWorld bool
+	}
+	read := hints{ReadOnly: true, Idempotent: true, OpenWorld: true}
+	local := hints{ReadOnly: true, Idempotent: true}
+	booking := hints{Destructive: true, OpenWorld: true}
+
+	expected := map[string]hints{
+		"analyze_coverage":               read,
+		"export_time_off_ical":           read,
+		"get_direct_reports":             read,
+		"get_employee_time_off_policies": read,
+		"get_manager_chain":              read,
+		"get_org_subtree":                read,
+		"get_team_time_off":              read,
+		"get_time_off_balance":           read,
+		"get_time_off_requests":          read,
+		"list_employees":                 read,
+		"list_pending_approvals":         read,
+		"list_time_off_policies":         read,
+		"report_time_off_usage":          read,
+		"report_unused_leave":            read,
+		"simulate_time_off_balance":      read,
+		"list_request_templates":         local,
+		"query_time_off_history":         local,
+		"create_time_off_request":        booking,
+		"update_time_off_request":        booking,
+		"bulk_create_time_off":           booking,
+		"apply_request_template":         booking,
+		"save_request_template":          {Destructive: true, Idempotent: true, OpenWorld: true},
+		"delete_request_template":        {Destructive: true},
+	}
+
+	var list struct {
+		Tools []struct {
+			Name        string `json:"name"`
+			Annotations struct {
+				Title           string `json:"title"`
+				ReadOnlyHint    *bool  `json:"readOnlyHint"`
+				DestructiveHint *bool  `json:"destructiveHint"`
+				IdempotentHint  *bool  `json:"idempotentHint"`
+				OpenWorldHint   *bool  `json:"openWorldHint"`
+			} `json:"annotations"`
+		} `json:"tools"`
+	}
+	if err := json.Unmarshal(sendMessage(t, s, "tools/list", map[string]string{}), &list); err != nil {
+		t.Fatalf("Failed to unmarshal tools: %v", err)
+	}
+
+	if len(list.Tools) != len(expected) {
+		t.Errorf("Expected %d tools, got %d", len(expected), len(list.Tools))
+	}
+
+	for _, tool := range list.Tools {
+		a := tool.Annotations
+		if a.Title == "" {
+			t.Errorf("Expected tool %s to declare a title", tool.Name)
+		}
+
+		want, ok := expected[tool.Name]
+		if !ok {
+			t.Errorf("Unexpected tool %s: add its expected hints", tool.Name)
+			continue
+		}
+		if a.ReadOnlyHint == nil || a.DestructiveHint == nil || a.IdempotentHint == nil || a.OpenWorldHint == nil {
+			t.Errorf("Expected tool %s to declare every hint, got %+v", tool.Name, a)
+			continue
+		}
+
+		got := hints{*a.ReadOnlyHint, *a.DestructiveHint, *a.IdempotentHint, *a.OpenWorldHint}
+		if got != want {
+			t.Errorf("Expected tool %s to have hints %+v, got %+v", tool.Name, want, got)
+		}
+	}
+}
+
+// callTool calls a tool through the MCP server and returns its text content and error flag
+func callTool(t *testing.T, s *server.MCPServer, name string, arguments map[string]string) (string, bool) {
+	t.Helper()
+
+	var result struct {
+		Content []mcp.TextContent `json:"content"`
+		IsError bool              `json:"isError"`
+	}
+	response := sendMessage(t, s, "tools/call", map[string]interface{}{
+		"name":      name,
+		"arguments": arguments,
+	})
+	if err := json.Unmarshal(response, &result); err != nil {
+		t.Fatalf("Failed to unmarshal result: %v", err)
+	}
+	if len(result.Content) == 0 {
+		t.Fatalf("Expected content from %s", name)
+	}
+
+	return result.Content[0].Text, result.IsError
+}
+
+func TestHandleCreateTimeOffRequest(t *testing.T) {
+	fake := newFakeBambooHR(bamboohr.Employee{ID: "4", DisplayName: "Charlotte Abbott"})
+	s := New(fake, newTestDateParser())
+
+	_, isError := callTool(t, s, "create_time_off_request", map[string]string{
+		"employeeId":    "4",
+		"timeOffTypeId": "78",
+		"start":         "2025-12-22",
+		"end":           "2025-12-23",
+		"amount":        "2",
+		"employeeNote":  "Family visit",
+	})
+	if isError {
+		t.Fatal("Expected the request to be created")
+	}
+
+	if len(fake.created) != 1 {
+		t.Fatalf("Expected 1 created request, got %d", len(fake.created))
+	}
+
+	created := fake.created[0]
+	if created.Status != "requested" || created.Start != "2025-12-22" || created.End != "2025-12-23" || created.TimeOffTypeID != 78 || created.Amount != 2 {
+		t.Errorf("Unexpected request payload: %+v", created)
+	}
+	if len(created.Notes) != 1 || created.Notes[0].From != "employee" || created.Notes[0].Note != "Family visit" {
+		t.Errorf("Expected the employee note, got %+v", created.Notes)
+	}
+}
+
+func TestHandleCreateTimeOffRequest_Units(t *testing.T) {
+	fake := newFakeBambooHR(bamboohr.Employee{ID: "4", DisplayName: "Charlotte Abbott", StandardHoursPerWeek: 30})
+	fake.types.TimeOffTypes = []bamboohr.TimeOffType{
+		{ID: "78", Name: "Vacation", Units: "days"},
+		{ID: "83", Name: "Sick", Units: "hours"},
+	}
+	s := New(fake, newTestDateParser())
+
+	tests := []struct {
+		name           string
+		arguments      map[string]string
+		expectedAmount float64
+		expectedText   string
+	}{
+		{
+			name:           "hours of a type in days",
+			arguments:      map[string]string{"timeOffTypeId": "78", "amount": "3", "unit": "hours"},
+			expectedAmount: 0.5,
+			expectedText:   "0.5 days / 3 hours",
+		},
+		{
+			name:           "days of a type in hours",
+			arguments:      map[string]string{"timeOffTypeId": "83", "amount": "1.5", "unit": "days"},
+			expectedAmount: 9,
+			expectedText:   "9 hours / 1.5 days",
+		},
+		{
+			name:           "the type's units by default",
+			arguments:      map[string]string{"timeOffTypeId": "83", "amount": "4"},
+			expectedAmount: 4,
+			expectedText:   "4 hours / 0.67 days",
+		},
+	}
+
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.arguments["employeeId"] = "4"
+			tt.arguments["start"] = "2025-12-22"
+			tt.arguments["format"] = "compact"
+
+			text, isError := callTool(t, s, "create_time_off_request", tt.arguments)
+			if isError {
+				t.Fatalf("Unexpected tool error: %s", text)
+			}
+			if created := fake.created[i]; created.Amount != tt.expectedAmount || created.Dates[0].Amount != tt.expectedAmount {
+				t.Errorf("Expected amount %v, got %+v", tt.expectedAmount, created)
+			}
+			if !strings.Contains(text, tt.expectedText) {
+				t.Errorf("Expected %q in %q", tt.expectedText, text)
+			}
+		})
+	}
+
+	for _, arguments := range []map[string]string{
+		{"amount": "0"},
+		{"amount": "two"},
+		{"amount": "2", "unit": "weeks"},
+	} {
+		arguments["employeeId"] = "4"
+		arguments["timeOffTypeId"] = "78"
+		arguments["start"] = "2025-12-22"
+		if text, isError := callTool(t, s, "create_time_off_request", arguments); !isError {
+			t.Errorf("Expected tool error for %v, got %q", arguments, text)
+		}
+	}
+}
+
+func TestHandleCreateTimeOffRequest_PolicyDenied(t *testing.T) {
+	fake := newFakeBambooHR(bamboohr.Employee{ID: "4"})
+	s := New(NewPolicyEnforcer(fake, Policy{ReadOnly: true}), newTestDateParser())
+
+	text, isError := callTool(t, s, "create_time_off_request", map[string]string{
+		"employeeId":    "4",
+		"timeOffTypeId": "78",
+		"start":         "2025-12-22",
+	})
+	if !isError || !strings.Contains(text, "denied by policy") {
+		t.Errorf("Expected a policy error, got %q", text)
+	}
+}
@@ -0,0 +1,274 @@
+package mcpserver
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"bamboohr-mcp-server/bamboohr"
+)
+
+// EmployeeSync is a BambooHR decorator that keeps a local copy of the employee directory.
+// The directory is downloaded once and then kept up to date by polling BambooHR for the
+// employees changed since the last sync, so the directory, employee lookups and name search
+// are served locally. Other calls are passed through to the client.
+type EmployeeSync struct {
+	BambooHR
+
+	now func() time.Time
+
+	// bootstrapMu is held while the directory is first downloaded, so concurrent callers wait
+	// for one download
+	bootstrapMu sync.Mutex
+
+	mu           sync.Mutex
+	bootstrapped bool
+	fields       []bamboohr.DirectoryField
+	employees    []bamboohr.Employee
+	since        time.Time
+	// detailed holds the employees whose full record has been fetched, as the directory lacks
+	// fields such as standardHoursPerWeek and supervisorEId
+	detailed map[string]bool
+	// applied holds the lastChanged of the change last applied to each employee, so changes
+	// returned again by an overlapping sync are not reapplied
+	applied map[string]string
+}
+
+// bootstrapSkew is subtracted from the local clock when the directory is downloaded, so changes
+// made while it downloads are picked up by the next sync even if the clock is ahead of BambooHR's
+const bootstrapSkew = 5 * time.Minute
+
+// SyncResult counts the changes applied by a sync
+type SyncResult struct {
+	Inserted int
+	Updated  int
+	Deleted  int
+}
+
+// NewEmployeeSync creates an employee sync backed by the client. The directory is downloaded
+// on the first call or sync.
+func NewEmployeeSync(client BambooHR) *EmployeeSync {
+	return &EmployeeSync{BambooHR: client, now: time.Now, detailed: make(map[string]bool), applied: make(map[string]string)}
+}
+
+// Bootstrap downloads the full employee directory, replacing the local copy
+func (s *EmployeeSync) Bootstrap() error {
+	// Changes made while the directory is downloading are picked up by the next sync
+	started := s.now().Add(-bootstrapSkew)
+
+	directory, err := s.BambooHR.GetEmployeeDirectory()
+	if err != nil {
+		return fmt.Errorf("failed to download employee directory: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.fields = directory.Fields
+	s.employees = append([]bamboohr.Employee(nil), directory.Employees...)
+	s.since = started
+	s.bootstrapped = true
+	clear(s.detailed)
+
+	return nil
+}
+
+// Sync applies the employees inserted, updated and deleted since the last sync to the local
+// directory, bootstrapping it first if needed
+func (s *EmployeeSync) Sync() (SyncResult, error) {
+	var result SyncResult
+
+	if err := s.ensureBootstrapped(); err != nil {
+		return result, err
+	}
+
+	s.mu.Lock()
+	since := s.since
+	s.mu.Unlock()
+
+	changes, err := s.BambooHR.GetChangedEmployees(since)
+	if err != nil {
+		return result, fmt.Errorf("failed to get changed employees: %w", err)
+	}
+
+	for id, change := range changes.Employees {
+		employeeID, err := strconv.Atoi(id)
+		if err != nil {
+			continue
+		}
+
+		s.mu.Lock()
+		seen := change.LastChanged != "" && s.applied[id] == change.LastChanged
+		s.mu.Unlock()
+		if seen {
+			continue
+		}
+
+		if change.Action == bamboohr.EmployeeDeleted {
+			if s.remove(id) {
+				result.Deleted++
+			}
+			s.markApplied(id, change.LastChanged)
+			continue
+		}
+
+		employee, err := s.BambooHR.GetEmployee(employeeID)
+		if err != nil {
+			// Keep the previous since, so the remaining changes are retried by the next sync
+			return result, fmt.Errorf("failed to get changed employee %d: %w", employeeID, err)
+		}
+		if employee.ID == "" {
+			employee.ID = id
+		}
+
+		if s.upsert(*employee) {
+			result.Inserted++
+		} else {
+			result.Updated++
+		}
+		s.markApplied(id, change.LastChanged)
+	}
+
+	// The next sync starts from BambooHR's time of the latest change rather than the local
+	// clock, which may be ahead of BambooHR's
+	if latest, err := time.Parse(time.RFC3339, changes.Latest); err == nil && latest.After(since) {
+		s.mu.Lock()
+		s.since = latest
+		s.mu.Unlock()
+	}
+
+	return result, nil
+}
+
+// markApplied records the change applied to the employee
+func (s *EmployeeSync) markApplied(id, lastChanged string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.applied[id] = lastChanged
+}
+
+// Run syncs the directory every interval until the context is cancelled. Failed syncs are
+// reported to onError, and the local directory keeps serving the last synced employees.
+func (s *EmployeeSync) Run(ctx context.Context, interval time.Duration, onError func(error)) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := s.Sync(); err != nil && onError != nil {
+			onError(err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// ensureBootstrapped downloads the directory if it has not been downloaded yet. A failed
+// download is retried by the next call.
+func (s *EmployeeSync) ensureBootstrapped() error {
+	s.bootstrapMu.Lock()
+	defer s.bootstrapMu.Unlock()
+
+	s.mu.Lock()
+	bootstrapped := s.bootstrapped
+	s.mu.Unlock()
+
+	if bootstrapped {
+		return nil
+	}
+	return s.Bootstrap()
+}
+
+// upsert inserts or replaces the employee with their full record, and reports whether it was
+// inserted
+func (s *EmployeeSync) upsert(employee bamboohr.Employee) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.detailed[employee.ID] = true
+
+	for i := range s.employees {
+		if s.employees[i].ID == employee.ID {
+			s.employees[i] = employee
+			return false
+		}
+	}
+
+	s.employees = append(s.employees, employee)
+	return true
+}
+
+// remove deletes the employee, and reports whether it was present
+func (s *EmployeeSync) remove(id string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i := range s.employees {
+		if s.employees[i].ID == id {
+			s.employees = append(s.employees[:i], s.employees[i+1:]...)
+			delete(s.detailed, id)
+			return true
+		}
+	}
+
+	return false
+}
+
+// GetEmployeeDirectory returns the local copy of the employee directory
+func (s *EmployeeSync) GetEmployeeDirectory() (*bamboohr.EmployeeDirectory, error) {
+	if err := s.ensureBootstrapped(); err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return &bamboohr.EmployeeDirectory{
+		Fields:    s.fields,
+		Employees: append([]bamboohr.Employee(nil), s.employees...),
+	}, nil
+}
+
+// GetEmployee returns the employee from the local directory once their full record has been
+// fetched. Employees only known from the directory, or not in it, are fetched from the client,
+// and the full record is kept until the next bootstrap.
+func (s *EmployeeSync) GetEmployee(employeeID int) (*bamboohr.Employee, error) {
+	id := strconv.Itoa(employeeID)
+	bootstrapped := s.ensureBootstrapped() == nil
+
+	if bootstrapped {
+		s.mu.Lock()
+		for _, employee := range s.employees {
+			if employee.ID == id && s.detailed[id] {
+				s.mu.Unlock()
+				return &employee, nil
+			}
+		}
+		s.mu.Unlock()
+	}
+
+	employee, err := s.BambooHR.GetEmployee(employeeID)
+	if err != nil || !bootstrapped {
+		return employee, err
+	}
+
+	record := *employee
+	if record.ID == "" {
+		record.ID = id
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i := range s.employees {
+		if s.employees[i].ID == id {
+			s.employees[i] = record
+			s.detailed[id] = true
+		}
+	}
+	return employee, nil
+}
@@ -0,0 +1,183 @@
+package mcpserver
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"bamboohr-mcp-server/bamboohr"
+)
+
+func TestEmployeeSync(t *testing.T) {
+	fake := newFakeBambooHR(
+		bamboohr.Employee{ID: "4", DisplayName: "Charlotte Abbott", Department: "Engineering"},
+		bamboohr.Employee{ID: "5", DisplayName: "Ashley Adams", Department: "Sales"},
+	)
+
+	now := time.Date(2025, 12, 1, 9, 0, 0, 0, time.UTC)
+	employeeSync := NewEmployeeSync(fake)
+	employeeSync.now = func() time.Time { return now }
+
+	directory, err := employeeSync.GetEmployeeDirectory()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(directory.Employees) != 2 {
+		t.Fatalf("Expected 2 employees after bootstrap, got %d", len(directory.Employees))
+	}
+
+	// Employee 4 moves department, 5 leaves and 6 joins
+	fake.employees = []bamboohr.Employee{
+		{ID: "4", DisplayName: "Charlotte Abbott", Department: "Product"},
+		{ID: "6", DisplayName: "Christina Agluinda", Department: "Sales"},
+	}
+	fake.changes = []bamboohr.EmployeeChange{
+		{ID: "4", Action: bamboohr.EmployeeUpdated, LastChanged: "2025-12-01T09:10:00Z"},
+		{ID: "5", Action: bamboohr.EmployeeDeleted, LastChanged: "2025-12-01T09:20:00Z"},
+		{ID: "6", Action: bamboohr.EmployeeInserted, LastChanged: "2025-12-01T09:30:00Z"},
+	}
+
+	now = now.Add(time.Hour)
+	result, err := employeeSync.Sync()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if result != (SyncResult{Inserted: 1, Updated: 1, Deleted: 1}) {
+		t.Errorf("Expected 1 insert, 1 update and 1 delete, got %+v", result)
+	}
+
+	fake.calls = nil
+	directory, err = employeeSync.GetEmployeeDirectory()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	departments := map[string]string{}
+	for _, employee := range directory.Employees {
+		departments[employee.ID] = employee.Department
+	}
+	expected := map[string]string{"4": "Product", "6": "Sales"}
+	if len(departments) != len(expected) || departments["4"] != "Product" || departments["6"] != "Sales" {
+		t.Errorf("Expected %v, got %v", expected, departments)
+	}
+
+	if employee, err := employeeSync.GetEmployee(6); err != nil || employee.DisplayName != "Christina Agluinda" {
+		t.Errorf("Expected the inserted employee, got %+v (%v)", employee, err)
+	}
+
+	if len(fake.calls) != 0 {
+		t.Errorf("Expected the directory and employee to be served locally, got calls %v", fake.calls)
+	}
+
+	// The next sync only asks for changes since the previous one
+	now = now.Add(time.Hour)
+	result, err = employeeSync.Sync()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if result != (SyncResult{}) {
+		t.Errorf("Expected no changes, got %+v", result)
+	}
+}
+
+func TestEmployeeSync_FailedSyncKeepsDirectory(t *testing.T) {
+	fake := newFakeBambooHR(bamboohr.Employee{ID: "4", DisplayName: "Charlotte Abbott"})
+	employeeSync := NewEmployeeSync(fake)
+
+	if err := employeeSync.Bootstrap(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	fake.err = errNotReachable
+	if _, err := employeeSync.Sync(); err == nil {
+		t.Error("Expected the sync to fail")
+	}
+
+	directory, err := employeeSync.GetEmployeeDirectory()
+	if err != nil {
+		t.Fatalf("Expected the local directory to be served, got %v", err)
+	}
+	if len(directory.Employees) != 1 {
+		t.Errorf("Expected 1 employee, got %d", len(directory.Employees))
+	}
+}
+
+func TestEmployeeSync_SinceLatestChange(t *testing.T) {
+	fake := newFakeBambooHR(bamboohr.Employee{ID: "4", DisplayName: "Charlotte Abbott", Department: "Engineering"})
+
+	// The local clock is a day ahead of BambooHR's
+	employeeSync := NewEmployeeSync(fake)
+	employeeSync.now = func() time.Time { return time.Date(2025, 12, 2, 9, 0, 0, 0, time.UTC) }
+	if err := employeeSync.Bootstrap(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	employeeSync.since = time.Date(2025, 12, 1, 8, 0, 0, 0, time.UTC)
+
+	fake.changes = []bamboohr.EmployeeChange{{ID: "4", Action: bamboohr.EmployeeUpdated, LastChanged: "2025-12-01T09:00:00Z"}}
+	if _, err := employeeSync.Sync(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if expected := time.Date(2025, 12, 1, 9, 0, 0, 0, time.UTC); !employeeSync.since.Equal(expected) {
+		t.Errorf("Expected the next sync from the latest change at %s, got %s", expected, employeeSync.since)
+	}
+
+	// A change made after the previous sync by BambooHR's clock is applied, and the change
+	// returned again by the overlapping sync is not
+	fake.employees[0].Department = "Product"
+	fake.changes = append(fake.changes, bamboohr.EmployeeChange{ID: "5", Action: bamboohr.EmployeeInserted, LastChanged: "2025-12-01T10:00:00Z"})
+	fake.employees = append(fake.employees, bamboohr.Employee{ID: "5", DisplayName: "Ashley Adams"})
+	result, err := employeeSync.Sync()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if result != (SyncResult{Inserted: 1}) {
+		t.Errorf("Expected only the new change to be applied, got %+v", result)
+	}
+}
+
+func TestEmployeeSync_ConcurrentBootstrap(t *testing.T) {
+	fake := newFakeBambooHR(bamboohr.Employee{ID: "4", DisplayName: "Charlotte Abbott"})
+	employeeSync := NewEmployeeSync(fake)
+
+	var wg sync.WaitGroup
+	for range 10 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := employeeSync.GetEmployeeDirectory(); err != nil {
+				t.Errorf("Unexpected error: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if len(fake.calls) != 1 {
+		t.Errorf("Expected the directory to be downloaded once, got calls %v", fake.calls)
+	}
+}
+
+func TestEmployeeSync_GetEmployeeFetchesFullRecord(t *testing.T) {
+	fake := newFakeBambooHR(bamboohr.Employee{ID: "4", DisplayName: "Charlotte Abbott"})
+	employeeSync := NewEmployeeSync(fake)
+	if err := employeeSync.Bootstrap(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	// The full record has fields the directory lacks
+	fake.employees[0].StandardHoursPerWeek = 30
+	fake.employees[0].SupervisorEID = "2"
+	fake.calls = nil
+
+	for range 2 {
+		employee, err := employeeSync.GetEmployee(4)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if employee.StandardHoursPerWeek != 30 || employee.SupervisorEID != "2" {
+			t.Errorf("Expected the full record, got %+v", employee)
+		}
+	}
+	if len(fake.calls) != 1 {
+		t.Errorf("Expected the full record to be fetched once, got calls %v", fake.calls)
+	}
+}
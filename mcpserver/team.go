@@ -0,0 +1,229 @@
+package mcpserver
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+
+	"bamboohr-mcp-server/bamboohr"
+)
+
+// teamQueryConcurrency caps the concurrent BambooHR calls made for a team
+const teamQueryConcurrency = 4
+
+// teamFanOutLimit is the largest team whose requests are fetched one employee at a time. The
+// requests of larger teams are fetched for the whole company in one call and filtered.
+const teamFanOutLimit = 10
+
+// TeamTimeOffResult is the structured output of get_team_time_off
+type TeamTimeOffResult struct {
+	EmployeeIDs []string                  `json:"employeeIds" jsonschema:"description=The employees whose time off was searched"`
+	Start       string                    `json:"start" jsonschema:"description=Start of the period searched (YYYY-MM-DD)"`
+	End         string                    `json:"end" jsonschema:"description=End of the period searched (YYYY-MM-DD)"`
+	Statuses    []string                  `json:"statuses,omitempty" jsonschema:"description=The request statuses included, empty for every status"`
+	TypeIDs     []string                  `json:"typeIds,omitempty" jsonschema:"description=The time off types included, empty for every type"`
+	Requests    []bamboohr.TimeOffRequest `json:"requests"`
+	WorkDay     WorkDay                   `json:"workDay" jsonschema:"description=The working day used to show amounts in both days and hours"`
+}
+
+// teamTimeOffRequests returns the requests matching the query for each of the employees, ordered
+// by start date. Small teams are queried per employee with bounded concurrency, larger teams with
+// a single query across the company.
+func teamTimeOffRequests(client BambooHR, employeeIDs []int, query bamboohr.TimeOffRequestQuery) ([]bamboohr.TimeOffRequest, error) {
+	var requests []bamboohr.TimeOffRequest
+
+	if len(employeeIDs) > teamFanOutLimit {
+		team := make(map[string]bool, len(employeeIDs))
+		for _, id := range employeeIDs {
+			team[strconv.Itoa(id)] = true
+		}
+
+		query.EmployeeID = 0
+		all, err := client.SearchTimeOffRequests(query)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get time-off requests: %w", err)
+		}
+		for _, request := range all {
+			if team[request.EmployeeID] {
+				requests = append(requests, request)
+			}
+		}
+	} else {
+		results := make([][]bamboohr.TimeOffRequest, len(employeeIDs))
+		err := forEachEmployee(employeeIDs, func(i, employeeID int) error {
+			employeeQuery := query
+			employeeQuery.EmployeeID = employeeID
+
+			var err error
+			results[i], err = client.SearchTimeOffRequests(employeeQuery)
+			if err != nil {
+				return fmt.Errorf("failed to get time-off requests for employee %d: %w", employeeID, err)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		for _, employeeRequests := range results {
+			requests = append(requests, employeeRequests...)
+		}
+	}
+
+	sort.SliceStable(requests, func(i, j int) bool {
+		return requests[i].Start < requests[j].Start
+	})
+
+	return requests, nil
+}
+
+// forEachEmployee calls fn for each employee with its index, at most teamQueryConcurrency at a
+// time, and returns the error of the first employee that failed
+func forEachEmployee(employeeIDs []int, fn func(i, employeeID int) error) error {
+	errs := make([]error, len(employeeIDs))
+
+	var wg sync.WaitGroup
+	limit := make(chan struct{}, teamQueryConcurrency)
+	for i, employeeID := range employeeIDs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			limit <- struct{}{}
+			defer func() { <-limit }()
+
+			errs[i] = fn(i, employeeID)
+		}()
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// resolveTimeOffTypeIDs resolves a comma-separated list of time off type IDs or names to IDs
+func resolveTimeOffTypeIDs(client BambooHR, value string) ([]string, error) {
+	values := splitList(value)
+	if len(values) == 0 {
+		return nil, nil
+	}
+
+	var types *bamboohr.TimeOffTypes
+	var ids []string
+	for _, value := range values {
+		if _, err := strconv.Atoi(value); err == nil {
+			ids = append(ids, value)
+			continue
+		}
+
+		if types == nil {
+			var err error
+			if types, err = client.GetTimeOffTypes(); err != nil {
+				return nil, fmt.Errorf("failed to get time off types: %w", err)
+			}
+		}
+
+		id := ""
+		for _, timeOffType := range types.TimeOffTypes {
+			if strings.EqualFold(timeOffType.Name, value) {
+				id = timeOffType.ID
+				break
+			}
+		}
+		if id == "" {
+			return nil, fmt.Errorf("unknown time off type %q", value)
+		}
+		ids = append(ids, id)
+	}
+
+	return ids, nil
+}
+
+func handleGetTeamTimeOff(client BambooHR, dates *DateParser, me int, hoursPerDay float64) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		format, err := toolFormat(request, FormatCompact)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		locale, err := toolLocale(request, dates)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		managerID, err := toolManagerID(request, me)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		employeeIDs, err := teamEmployeeIDs(client, request.GetString("employeeIds", ""), request.GetString("department", ""), managerID)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		parser := dates.ForEmployees(employeeIDs)
+		period, err := parser.ResolveRange(request.GetString("start", ""), request.GetString("end", ""), parser.CurrentYear())
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		typeIDs, err := resolveTimeOffTypeIDs(client, request.GetString("timeOffType", ""))
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		workDay := companyWorkDay(client, hoursPerDay)
+
+		result := TeamTimeOffResult{
+			Start:    period.StartYMD(),
+			End:      period.EndYMD(),
+			Statuses: splitList(request.GetString("status", "approved,requested")),
+			TypeIDs:  typeIDs,
+			WorkDay:  workDay,
+		}
+		for _, id := range employeeIDs {
+			result.EmployeeIDs = append(result.EmployeeIDs, strconv.Itoa(id))
+		}
+
+		result.Requests, err = teamTimeOffRequests(client, employeeIDs, bamboohr.TimeOffRequestQuery{
+			Start:    result.Start,
+			End:      result.End,
+			Statuses: result.Statuses,
+			TypeIDs:  result.TypeIDs,
+		})
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Failed to get team time off: %s", err.Error())), nil
+		}
+		if result.Requests == nil {
+			result.Requests = []bamboohr.TimeOffRequest{}
+		}
+
+		return renderToolResult(result, teamTimeOffView(result, locale), format), nil
+	}
+}
+
+// teamTimeOffView shows a team's time-off requests as text, naming the employee of each request
+func teamTimeOffView(result TeamTimeOffResult, locale Locale) textView {
+	view := timeOffRequestTable(result.Requests, locale, result.WorkDay)
+	view.Title = fmt.Sprintf("%s for %s between %s and %s",
+		pluralize(len(result.Requests), "time-off request", "time-off requests"),
+		pluralize(len(result.EmployeeIDs), "employee", "employees"),
+		locale.FormatDate(result.Start), locale.FormatDate(result.End))
+
+	view.Columns = append([]string{"Employee ID", "Name"}, view.Columns...)
+	for i, request := range result.Requests {
+		view.Items[i] = fmt.Sprintf("%s (ID %s): %s", request.Name, request.EmployeeID, view.Items[i])
+		view.Rows[i] = append([]string{request.EmployeeID, request.Name}, view.Rows[i]...)
+	}
+
+	return view
+}
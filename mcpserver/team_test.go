@@ -0,0 +1,117 @@
+package mcpserver
+
+import (
+	"testing"
+
+	"bamboohr-mcp-server/bamboohr"
+)
+
+func TestTeamTimeOffRequests(t *testing.T) {
+	fake := newApprovalsTestFake()
+
+	countCalls := func() int {
+		count := 0
+		for _, call := range fake.calls {
+			if call == "SearchTimeOffRequests" {
+				count++
+			}
+		}
+		return count
+	}
+
+	// A small team is queried one employee at a time
+	requests, err := teamTimeOffRequests(fake, []int{5, 4}, bamboohr.TimeOffRequestQuery{Statuses: []string{"requested"}})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	var ids []string
+	for _, request := range requests {
+		ids = append(ids, request.ID)
+	}
+	if fmtIDs(ids) != `["10","11","14"]` {
+		t.Errorf("Expected requests 10, 11 and 14 by start date, got %v", ids)
+	}
+	if calls := countCalls(); calls != 2 {
+		t.Errorf("Expected 2 calls, got %d", calls)
+	}
+
+	// A large team is queried across the company and filtered
+	fake.calls = nil
+	team := []int{3, 5}
+	for id := 100; len(team) <= teamFanOutLimit; id++ {
+		team = append(team, id)
+	}
+	requests, err = teamTimeOffRequests(fake, team, bamboohr.TimeOffRequestQuery{TypeIDs: []string{"78"}})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(requests) != 3 {
+		t.Errorf("Expected the 3 requests of employees 3 and 5, got %+v", requests)
+	}
+	if calls := countCalls(); calls != 1 {
+		t.Errorf("Expected 1 call, got %d", calls)
+	}
+
+	fake.err = errNotReachable
+	if _, err := teamTimeOffRequests(fake, []int{4, 5}, bamboohr.TimeOffRequestQuery{}); err == nil {
+		t.Error("Expected error when BambooHR is unreachable")
+	}
+}
+
+func TestResolveTimeOffTypeIDs(t *testing.T) {
+	fake := newFakeBambooHR()
+	fake.types.TimeOffTypes = []bamboohr.TimeOffType{{ID: "78", Name: "Vacation"}, {ID: "83", Name: "Sick"}}
+
+	tests := []struct {
+		input    string
+		expected string
+		hasError bool
+	}{
+		{"", "null", false},
+		{"78", `["78"]`, false},
+		{"vacation, 83", `["78","83"]`, false},
+		{"Bereavement", "", true},
+	}
+
+	for _, tt := range tests {
+		ids, err := resolveTimeOffTypeIDs(fake, tt.input)
+		if (err != nil) != tt.hasError {
+			t.Errorf("%q: expected error %v, got %v", tt.input, tt.hasError, err)
+			continue
+		}
+		if !tt.hasError && fmtIDs(ids) != tt.expected {
+			t.Errorf("%q: expected %s, got %v", tt.input, tt.expected, ids)
+		}
+	}
+}
+
+func TestGetTeamTimeOff(t *testing.T) {
+	fake := newApprovalsTestFake()
+	fake.types.TimeOffTypes = []bamboohr.TimeOffType{{ID: "78", Name: "Vacation"}}
+	s := New(fake, newTestDateParser(), WithCurrentEmployee(2))
+
+	text, isError := callTool(t, s, "get_team_time_off", map[string]string{"managerId": "me", "start": "October"})
+	expected := "2 time-off requests for 2 employees between 2025-10-01 and 2025-10-31:\n" +
+		"- Dan Diaz (ID 4): 2025-10-06 to 2025-10-08: Vacation, 3 days / 24 hours (requested) [request 10]\n" +
+		"- Eve Evans (ID 5): 2025-10-07: Vacation, 1 day / 8 hours (requested) [request 11]"
+	if isError || text != expected {
+		t.Errorf("Expected %q, got %q", expected, text)
+	}
+
+	text, isError = callTool(t, s, "get_team_time_off", map[string]string{"department": "Engineering", "status": "approved", "timeOffType": "Vacation", "format": "csv"})
+	expected = "Employee ID,Name,ID,Start,End,Type,Amount,Unit,Days,Hours,Status\n4,Dan Diaz,13,2025-11-03,2025-11-03,Vacation,1,days,1,8,approved\n"
+	if isError || text != expected {
+		t.Errorf("Expected %q, got %q", expected, text)
+	}
+
+	errorCases := []map[string]string{
+		{},
+		{"employeeIds": "4", "timeOffType": "Bereavement"},
+		{"employeeIds": "4", "start": "not a date"},
+	}
+	for _, arguments := range errorCases {
+		if text, isError := callTool(t, s, "get_team_time_off", arguments); !isError {
+			t.Errorf("Expected tool error for %v, got %q", arguments, text)
+		}
+	}
+}
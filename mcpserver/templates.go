@@ -0,0 +1,546 @@
+package mcpserver
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// ErrTemplateNotFound is returned for a request template that isn't in the store
+var ErrTemplateNotFound = errors.New("request template not found")
+
+// RequestTemplate is a named time-off request that can be applied to any dates
+type RequestTemplate struct {
+	Name            string  `json:"name"`
+	Owner           int     `json:"owner" jsonschema:"description=The employee ID of the employee who saved the template"`
+	TimeOffTypeID   string  `json:"timeOffTypeId"`
+	TimeOffTypeName string  `json:"timeOffTypeName,omitempty"`
+	Amount          float64 `json:"amount,omitempty" jsonschema:"description=The amount of each request, absent for the working days in it"`
+	Unit            string  `json:"unit,omitempty" jsonschema:"description=days or hours, absent for the units of the time off type"`
+	Days            string  `json:"days,omitempty" jsonschema:"description=The weekdays the template books, e.g. 'friday' or 'monday and wednesday', absent to book the whole period as one request"`
+	Note            string  `json:"note,omitempty"`
+	Updated         string  `json:"updated,omitempty"`
+}
+
+// RequestTemplatesResult is the structured output of list_request_templates
+type RequestTemplatesResult struct {
+	Templates []RequestTemplate `json:"templates"`
+}
+
+// templateFile is the JSON document the templates are kept in
+type templateFile struct {
+	Templates []RequestTemplate `json:"templates"`
+}
+
+// TemplateStore keeps named request templates in a JSON file. Each template belongs to the
+// employee who saved it, and is only listed, applied or deleted for them. The file is read on
+// every call, so edits made while the server runs are picked up.
+type TemplateStore struct {
+	mu   sync.Mutex
+	path string
+}
+
+// OpenTemplateStore opens the template file at path, which is created by the first save
+func OpenTemplateStore(path string) (*TemplateStore, error) {
+	store := &TemplateStore{path: path}
+	if _, err := store.load(); err != nil {
+		return nil, err
+	}
+	return store, nil
+}
+
+// List returns the owner's templates sorted by name
+func (s *TemplateStore) List(owner int) ([]RequestTemplate, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	templates, err := s.load()
+	if err != nil {
+		return nil, err
+	}
+
+	var owned []RequestTemplate
+	for _, template := range templates {
+		if template.Owner == owner {
+			owned = append(owned, template)
+		}
+	}
+	return owned, nil
+}
+
+// Get returns the owner's template with the name, ignoring case
+func (s *TemplateStore) Get(owner int, name string) (RequestTemplate, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	templates, err := s.load()
+	if err != nil {
+		return RequestTemplate{}, err
+	}
+	if i := templateIndex(templates, owner, name); i >= 0 {
+		return templates[i], nil
+	}
+	return RequestTemplate{}, fmt.Errorf("%q: %w", name, ErrTemplateNotFound)
+}
+
+// Save adds the template, replacing any of its owner's with the same name, and reports whether
+// one was replaced
+func (s *TemplateStore) Save(template RequestTemplate) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	templates, err := s.load()
+	if err != nil {
+		return false, err
+	}
+
+	i := templateIndex(templates, template.Owner, template.Name)
+	if i >= 0 {
+		templates[i] = template
+	} else {
+		templates = append(templates, template)
+	}
+	return i >= 0, s.write(templates)
+}
+
+// Delete removes the owner's template with the name
+func (s *TemplateStore) Delete(owner int, name string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	templates, err := s.load()
+	if err != nil {
+		return err
+	}
+
+	i := templateIndex(templates, owner, name)
+	if i < 0 {
+		return fmt.Errorf("%q: %w", name, ErrTemplateNotFound)
+	}
+	return s.write(slices.Delete(templates, i, i+1))
+}
+
+// load reads the templates, of which there are none before the file is created
+func (s *TemplateStore) load() ([]RequestTemplate, error) {
+	data, err := os.ReadFile(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading templates: %w", err)
+	}
+
+	var file templateFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("reading templates from %s: %w", s.path, err)
+	}
+	slices.SortFunc(file.Templates, func(a, b RequestTemplate) int {
+		return strings.Compare(strings.ToLower(a.Name), strings.ToLower(b.Name))
+	})
+	return file.Templates, nil
+}
+
+// write replaces the file through a temporary file, so a failed write leaves it unchanged
+func (s *TemplateStore) write(templates []RequestTemplate) error {
+	data, err := json.MarshalIndent(templateFile{Templates: templates}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encoding templates: %w", err)
+	}
+
+	dir := filepath.Dir(s.path)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("writing templates: %w", err)
+	}
+	tmp, err := os.CreateTemp(dir, ".templates-*.json")
+	if err != nil {
+		return fmt.Errorf("writing templates: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(append(data, '\n')); err != nil {
+		tmp.Close()
+		return fmt.Errorf("writing templates: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("writing templates: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), s.path); err != nil {
+		return fmt.Errorf("writing templates: %w", err)
+	}
+	return nil
+}
+
+// templateIndex returns the index of the owner's template with the name, ignoring case, or -1
+func templateIndex(templates []RequestTemplate, owner int, name string) int {
+	return slices.IndexFunc(templates, func(template RequestTemplate) bool {
+		return template.Owner == owner && strings.EqualFold(template.Name, strings.TrimSpace(name))
+	})
+}
+
+// errNoTemplateOwner is returned by the template tools when there is no current employee to
+// keep templates for
+var errNoTemplateOwner = errors.New("request templates are kept per employee: set the current employee with BAMBOOHR_EMPLOYEE_ID")
+
+// templateDates returns the days of the period the template books, separated by semicolons as
+// bulk_create_time_off takes them, or the whole period if the template has no day pattern
+func templateDates(template RequestTemplate, period DateRange) (string, error) {
+	if template.Days == "" {
+		return period.StartYMD() + " - " + period.EndYMD(), nil
+	}
+
+	days, err := parseWeekdays(template.Days)
+	if err != nil {
+		return "", err
+	}
+
+	var dates []string
+	for date := period.Start; !date.After(period.End); date = date.AddDate(0, 0, 1) {
+		if days[date.Weekday()] {
+			dates = append(dates, date.Format(dateLayout))
+		}
+	}
+	if len(dates) == 0 {
+		return "", fmt.Errorf("no day from %s to %s is on the template's days (%s)", period.StartYMD(), period.EndYMD(), template.Days)
+	}
+	return strings.Join(dates, "; "), nil
+}
+
+// registerTemplateTools adds the request template tools to the server
+func registerTemplateTools(s *server.MCPServer, templates *TemplateStore, client BambooHR, dates *DateParser, me int, hoursPerDay float64) {
+	saveRequestTemplateTool := mcp.NewTool(
+		"save_request_template",
+		mcp.WithDescription("Save a named time-off request template, e.g. 'Home office Friday', with its time off type, amount, days and note. A template with the same name is replaced."),
+		mcp.WithOutputSchema[RequestTemplate](),
+		mcp.WithTitleAnnotation("Save Request Template"),
+		mcp.WithReadOnlyHintAnnotation(false),
+		mcp.WithDestructiveHintAnnotation(true),
+		mcp.WithIdempotentHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(true),
+		mcp.WithString("name",
+			mcp.Required(),
+			mcp.Description("The name of the template"),
+		),
+		mcp.WithString("timeOffType",
+			mcp.Required(),
+			mcp.Description("The time off type ID or name, e.g. '27' or 'Home Office'"),
+		),
+		mcp.WithString("amount",
+			mcp.Description("The amount of each request (e.g., '1', '0.5'). Optional, defaults to the working days in each request."),
+		),
+		mcp.WithString("unit",
+			mcp.Description("Whether amount is in days or hours. Optional, defaults to the units of the time-off type."),
+			mcp.Enum("days", "hours"),
+		),
+		mcp.WithString("days",
+			mcp.Description("The weekdays the template books, e.g. 'friday', 'monday and wednesday' or 'weekday', with a request for each such day of the period it is applied to. Optional, by default the whole period is one request."),
+		),
+		mcp.WithString("note",
+			mcp.Description("Optional employee note on the requests"),
+		),
+	)
+
+	listRequestTemplatesTool := mcp.NewTool(
+		"list_request_templates",
+		mcp.WithDescription("List the saved time-off request templates"),
+		mcp.WithOutputSchema[RequestTemplatesResult](),
+		mcp.WithTitleAnnotation("List Request Templates"),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithIdempotentHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(false),
+		mcp.WithString("format",
+			mcp.Description("Response format: 'compact' (default) for a short line per template, 'full' for the complete JSON and structured content, 'markdown' for a table or 'csv'"),
+			mcp.Enum(formats...),
+		),
+	)
+
+	applyRequestTemplateTool := mcp.NewTool(
+		"apply_request_template",
+		mcp.WithDescription("Create time-off requests from a saved template for a period, e.g. apply 'Home office Friday' to next month. Requests are validated like create_time_off_request, and those overlapping existing time off or exceeding the balance are skipped."),
+		mcp.WithOutputSchema[BulkTimeOffResult](),
+		mcp.WithTitleAnnotation("Apply Request Template"),
+		mcp.WithReadOnlyHintAnnotation(false),
+		mcp.WithDestructiveHintAnnotation(true),
+		mcp.WithIdempotentHintAnnotation(false),
+		mcp.WithOpenWorldHintAnnotation(true),
+		mcp.WithString("name",
+			mcp.Required(),
+			mcp.Description("The name of the template"),
+		),
+		mcp.WithString("employeeId",
+			mcp.Required(),
+			mcp.Description("The ID of the employee to create the time off for, or 'me' for the current employee"),
+		),
+		mcp.WithString("start",
+			mcp.Required(),
+			mcp.Description("Start of the period (YYYY-MM-DD or an expression like 'next friday'), or a whole period such as 'next month'"),
+		),
+		mcp.WithString("end",
+			mcp.Description("End of the period. Defaults to the end of start."),
+		),
+		mcp.WithBoolean("dryRun",
+			mcp.Description("Run the checks without creating anything. Optional, defaults to false."),
+		),
+		mcp.WithString("locale",
+			mcp.Description(localeArgumentDescription),
+		),
+		mcp.WithString("format",
+			mcp.Description("Response format: 'compact' (default) for a line per request, 'full' for the complete JSON and structured content, 'markdown' for a table or 'csv'"),
+			mcp.Enum(formats...),
+		),
+	)
+
+	deleteRequestTemplateTool := mcp.NewTool(
+		"delete_request_template",
+		mcp.WithDescription("Delete a saved time-off request template"),
+		mcp.WithTitleAnnotation("Delete Request Template"),
+		mcp.WithReadOnlyHintAnnotation(false),
+		mcp.WithDestructiveHintAnnotation(true),
+		mcp.WithIdempotentHintAnnotation(false),
+		mcp.WithOpenWorldHintAnnotation(false),
+		mcp.WithString("name",
+			mcp.Required(),
+			mcp.Description("The name of the template"),
+		),
+	)
+
+	s.AddTool(saveRequestTemplateTool, handleSaveRequestTemplate(templates, client, me))
+	s.AddTool(listRequestTemplatesTool, handleListRequestTemplates(templates, me))
+	s.AddTool(applyRequestTemplateTool, handleApplyRequestTemplate(templates, client, dates, me, hoursPerDay))
+	s.AddTool(deleteRequestTemplateTool, handleDeleteRequestTemplate(templates, me))
+}
+
+func handleSaveRequestTemplate(templates *TemplateStore, client BambooHR, me int) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		if me == 0 {
+			return mcp.NewToolResultError(errNoTemplateOwner.Error()), nil
+		}
+
+		name, err := request.RequireString("name")
+		if err != nil || strings.TrimSpace(name) == "" {
+			return mcp.NewToolResultError("name is required"), nil
+		}
+
+		timeOffType, err := request.RequireString("timeOffType")
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("timeOffType is required: %s", err.Error())), nil
+		}
+
+		template := RequestTemplate{
+			Name:    strings.TrimSpace(name),
+			Owner:   me,
+			Unit:    request.GetString("unit", ""),
+			Days:    request.GetString("days", ""),
+			Note:    request.GetString("note", ""),
+			Updated: time.Now().UTC().Format(time.RFC3339),
+		}
+
+		if amount := request.GetString("amount", ""); amount != "" {
+			if template.Amount, err = strconv.ParseFloat(amount, 64); err != nil || template.Amount <= 0 {
+				return mcp.NewToolResultError("amount must be a positive number, e.g. '1', '0.5' or '4'"), nil
+			}
+		}
+		if template.Unit != "" && !isHours(template.Unit) && !strings.HasPrefix(strings.ToLower(template.Unit), "day") {
+			return mcp.NewToolResultError(fmt.Sprintf("unit must be 'days' or 'hours', got %q", template.Unit)), nil
+		}
+		if template.Days != "" {
+			if _, err := parseWeekdays(template.Days); err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+		}
+
+		typeIDs, err := resolveTimeOffTypeIDs(client, timeOffType)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		if len(typeIDs) != 1 {
+			return mcp.NewToolResultError("give exactly one time off type"), nil
+		}
+		template.TimeOffTypeID = typeIDs[0]
+		if types, err := client.GetTimeOffTypes(); err == nil {
+			for _, timeOffType := range types.TimeOffTypes {
+				if timeOffType.ID == template.TimeOffTypeID {
+					template.TimeOffTypeName = timeOffType.Name
+				}
+			}
+		}
+
+		replaced, err := templates.Save(template)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Failed to save request template: %s", err.Error())), nil
+		}
+
+		action := "Saved"
+		if replaced {
+			action = "Replaced"
+		}
+		return mcp.NewToolResultStructured(template, fmt.Sprintf("%s request template %s", action, templateItem(template))), nil
+	}
+}
+
+func handleListRequestTemplates(templates *TemplateStore, me int) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		if me == 0 {
+			return mcp.NewToolResultError(errNoTemplateOwner.Error()), nil
+		}
+
+		format, err := toolFormat(request, FormatCompact)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		list, err := templates.List(me)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Failed to list request templates: %s", err.Error())), nil
+		}
+
+		result := RequestTemplatesResult{Templates: list}
+		if result.Templates == nil {
+			result.Templates = []RequestTemplate{}
+		}
+		return renderToolResult(result, requestTemplatesView(result), format), nil
+	}
+}
+
+func handleApplyRequestTemplate(templates *TemplateStore, client BambooHR, dates *DateParser, me int, hoursPerDay float64) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		if me == 0 {
+			return mcp.NewToolResultError(errNoTemplateOwner.Error()), nil
+		}
+
+		name, err := request.RequireString("name")
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("name is required: %s", err.Error())), nil
+		}
+
+		employeeIDStr, err := request.RequireString("employeeId")
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("employeeId is required: %s", err.Error())), nil
+		}
+
+		employeeID, err := resolveEmployeeID(employeeIDStr, me)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		start, err := request.RequireString("start")
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("start date is required: %s", err.Error())), nil
+		}
+
+		locale, err := toolLocale(request, dates)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		format, err := toolFormat(request, FormatCompact)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		template, err := templates.Get(me, name)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		period, err := dates.ForEmployee(employeeID).ResolveRange(start, request.GetString("end", ""), DateRange{})
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		requestDates, err := templateDates(template, period)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		// The template's requests go through the same validation and checks as bulk creation
+		result, err := BulkCreateTimeOff(client, dates, BulkTimeOffRequest{
+			EmployeeID:  employeeID,
+			TimeOffType: template.TimeOffTypeID,
+			Dates:       requestDates,
+			Amount:      template.Amount,
+			Unit:        template.Unit,
+			Note:        template.Note,
+			DryRun:      request.GetBool("dryRun", false),
+		}, hoursPerDay)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		view := bulkTimeOffView(*result, locale)
+		view.Title = fmt.Sprintf("%s from template %q", view.Title, template.Name)
+		return renderToolResult(result, view, format), nil
+	}
+}
+
+func handleDeleteRequestTemplate(templates *TemplateStore, me int) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		if me == 0 {
+			return mcp.NewToolResultError(errNoTemplateOwner.Error()), nil
+		}
+
+		name, err := request.RequireString("name")
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("name is required: %s", err.Error())), nil
+		}
+
+		if err := templates.Delete(me, name); err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		return mcp.NewToolResultText(fmt.Sprintf("Deleted request template %q", strings.TrimSpace(name))), nil
+	}
+}
+
+// requestTemplatesView lists the templates
+func requestTemplatesView(result RequestTemplatesResult) textView {
+	view := textView{
+		Title:   pluralize(len(result.Templates), "request template", "request templates"),
+		Columns: []string{"Name", "Type ID", "Type", "Amount", "Unit", "Days", "Note"},
+	}
+	for _, template := range result.Templates {
+		view.Items = append(view.Items, templateItem(template))
+		view.Rows = append(view.Rows, []string{
+			template.Name,
+			template.TimeOffTypeID,
+			template.TimeOffTypeName,
+			strconv.FormatFloat(template.Amount, 'f', -1, 64),
+			template.Unit,
+			template.Days,
+			template.Note,
+		})
+	}
+	return view
+}
+
+// templateItem summarizes a template, e.g. "Home office Friday: 1 day of Home Office on friday"
+func templateItem(template RequestTemplate) string {
+	typeName := template.TimeOffTypeName
+	if typeName == "" {
+		typeName = "time off type " + template.TimeOffTypeID
+	}
+
+	amount := "the working days"
+	if template.Amount > 0 {
+		amount = formatAmount(template.Amount, template.Unit)
+	}
+
+	item := fmt.Sprintf("%s: %s of %s", template.Name, amount, typeName)
+	if template.Days != "" {
+		item += " on " + template.Days
+	}
+	if template.Note != "" {
+		item += fmt.Sprintf(" (%q)", template.Note)
+	}
+	return item
+}
@@ -0,0 +1,195 @@
+package mcpserver
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestTemplateStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config", "templates.json")
+	templates, err := OpenTemplateStore(path)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if list, err := templates.List(4); err != nil || len(list) != 0 {
+		t.Fatalf("Expected no templates before the first save, got %v, %v", list, err)
+	}
+
+	for _, template := range []RequestTemplate{
+		{Name: "Home office Friday", Owner: 4, TimeOffTypeID: "27", Days: "friday"},
+		{Name: "dentist", Owner: 4, TimeOffTypeID: "83", Amount: 2, Unit: "hours"},
+		{Name: "Dentist", Owner: 5, TimeOffTypeID: "83", Amount: 1, Unit: "hours"},
+	} {
+		if replaced, err := templates.Save(template); err != nil || replaced {
+			t.Fatalf("Expected %s to be added, got %v, %v", template.Name, replaced, err)
+		}
+	}
+
+	replaced, err := templates.Save(RequestTemplate{Name: "Dentist", Owner: 4, TimeOffTypeID: "83", Amount: 3, Unit: "hours"})
+	if err != nil || !replaced {
+		t.Fatalf("Expected Dentist to replace dentist, got %v, %v", replaced, err)
+	}
+
+	// A new store reads what was saved
+	reopened, err := OpenTemplateStore(path)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	list, err := reopened.List(4)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(list) != 2 || list[0].Name != "Dentist" || list[0].Amount != 3 || list[1].Name != "Home office Friday" {
+		t.Errorf("Expected Dentist and Home office Friday sorted by name, got %+v", list)
+	}
+
+	if template, err := reopened.Get(4, "HOME OFFICE FRIDAY"); err != nil || template.Days != "friday" {
+		t.Errorf("Expected the template ignoring case, got %+v, %v", template, err)
+	}
+
+	// Other employees' templates can't be read or deleted
+	if _, err := reopened.Get(5, "Home office Friday"); !errors.Is(err, ErrTemplateNotFound) {
+		t.Errorf("Expected ErrTemplateNotFound for another employee's template, got %v", err)
+	}
+	if err := reopened.Delete(6, "Dentist"); !errors.Is(err, ErrTemplateNotFound) {
+		t.Errorf("Expected ErrTemplateNotFound deleting another employee's template, got %v", err)
+	}
+
+	if err := reopened.Delete(4, "dentist"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if _, err := reopened.Get(4, "Dentist"); !errors.Is(err, ErrTemplateNotFound) {
+		t.Errorf("Expected ErrTemplateNotFound after delete, got %v", err)
+	}
+	if err := reopened.Delete(4, "dentist"); !errors.Is(err, ErrTemplateNotFound) {
+		t.Errorf("Expected ErrTemplateNotFound deleting twice, got %v", err)
+	}
+	if template, err := reopened.Get(5, "dentist"); err != nil || template.Amount != 1 {
+		t.Errorf("Expected employee 5's template to be kept, got %+v, %v", template, err)
+	}
+
+	if err := os.WriteFile(path, []byte("not json"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := OpenTemplateStore(path); err == nil {
+		t.Error("Expected error for a corrupt template file")
+	}
+}
+
+func TestRequestTemplateTools(t *testing.T) {
+	fake := newBulkTestFake()
+	templates, err := OpenTemplateStore(filepath.Join(t.TempDir(), "templates.json"))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	s := New(fake, newTestDateParser(), WithCurrentEmployee(4), WithTemplates(templates))
+
+	text, isError := callTool(t, s, "save_request_template", map[string]string{
+		"name":        "Home office Friday",
+		"timeOffType": "home office",
+		"days":        "fridays",
+		"note":        "Working from home",
+	})
+	if isError {
+		t.Fatalf("Unexpected tool error: %s", text)
+	}
+	if text != `Saved request template Home office Friday: the working days of Home Office on fridays ("Working from home")` {
+		t.Errorf("Unexpected save response %q", text)
+	}
+
+	text, isError = callTool(t, s, "save_request_template", map[string]string{
+		"name":        "Half day",
+		"timeOffType": "78",
+		"amount":      "4",
+		"unit":        "hours",
+	})
+	if isError {
+		t.Fatalf("Unexpected tool error: %s", text)
+	}
+
+	text, isError = callTool(t, s, "list_request_templates", map[string]string{"format": "csv"})
+	if isError {
+		t.Fatalf("Unexpected tool error: %s", text)
+	}
+	expected := "Name,Type ID,Type,Amount,Unit,Days,Note\n" +
+		"Half day,78,Vacation,4,hours,,\n" +
+		"Home office Friday,27,Home Office,0,,fridays,Working from home\n"
+	if text != expected {
+		t.Errorf("Expected %q, got %q", expected, text)
+	}
+
+	// Applying the template books each Friday of the period, skipping the one already booked
+	text, isError = callTool(t, s, "apply_request_template", map[string]string{
+		"name":       "home office friday",
+		"employeeId": "me",
+		"start":      "2025-09-01",
+		"end":        "2025-09-21",
+	})
+	if isError {
+		t.Fatalf("Unexpected tool error: %s", text)
+	}
+	if !strings.HasPrefix(text, `Created 2 of 3 entries of Home Office for employee 4 (2 days / 16 hours), 1 skipped from template "Home office Friday":`) {
+		t.Errorf("Unexpected apply response %q", text)
+	}
+	if len(fake.created) != 2 || fake.created[0].Start != "2025-09-05" || fake.created[1].Start != "2025-09-19" || fake.created[0].Notes[0].Note != "Working from home" {
+		t.Errorf("Expected requests on the free Fridays with the template's note, got %+v", fake.created)
+	}
+
+	// A template without days books the whole period as one request
+	text, isError = callTool(t, s, "apply_request_template", map[string]string{
+		"name":       "Half day",
+		"employeeId": "4",
+		"start":      "2025-10-10",
+	})
+	if isError {
+		t.Fatalf("Unexpected tool error: %s", text)
+	}
+	if created := fake.created[2]; created.Start != "2025-10-10" || created.End != "2025-10-10" || created.Amount != 0.5 || created.TimeOffTypeID != 78 {
+		t.Errorf("Expected half a day of vacation, got %+v", created)
+	}
+
+	if text, isError = callTool(t, s, "delete_request_template", map[string]string{"name": "Half day"}); isError {
+		t.Fatalf("Unexpected tool error: %s", text)
+	}
+
+	// Another employee sharing the template file sees none of these templates
+	other := New(fake, newTestDateParser(), WithCurrentEmployee(5), WithTemplates(templates))
+	if text, _ := callTool(t, other, "list_request_templates", map[string]string{}); text != "0 request templates." {
+		t.Errorf("Expected no templates for another employee, got %q", text)
+	}
+	if text, isError := callTool(t, other, "apply_request_template", map[string]string{"name": "Home office Friday", "employeeId": "5", "start": "2025-10-10"}); !isError || !strings.Contains(text, "request template not found") {
+		t.Errorf("Expected another employee's template not to be found, got %q", text)
+	}
+
+	// Without a current employee there is nobody to keep templates for
+	anonymous := New(fake, newTestDateParser(), WithTemplates(templates))
+	if text, isError := callTool(t, anonymous, "list_request_templates", map[string]string{}); !isError || !strings.Contains(text, "BAMBOOHR_EMPLOYEE_ID") {
+		t.Errorf("Expected an error without a current employee, got %q", text)
+	}
+
+	errorTests := []struct {
+		name          string
+		tool          string
+		arguments     map[string]string
+		expectedError string
+	}{
+		{"unknown type", "save_request_template", map[string]string{"name": "Sabbatical", "timeOffType": "Sabbatical"}, "unknown time off type"},
+		{"invalid days", "save_request_template", map[string]string{"name": "Funday", "timeOffType": "27", "days": "funday"}, "unknown day"},
+		{"invalid unit", "save_request_template", map[string]string{"name": "Weekly", "timeOffType": "27", "amount": "1", "unit": "weeks"}, "unit must be"},
+		{"deleted template", "apply_request_template", map[string]string{"name": "Half day", "employeeId": "4", "start": "2025-10-10"}, "request template not found"},
+		{"no matching days", "apply_request_template", map[string]string{"name": "Home office Friday", "employeeId": "4", "start": "2025-10-06", "end": "2025-10-09"}, "is on the template's days"},
+		{"delete unknown template", "delete_request_template", map[string]string{"name": "Half day"}, "request template not found"},
+	}
+	for _, tt := range errorTests {
+		t.Run(tt.name, func(t *testing.T) {
+			text, isError := callTool(t, s, tt.tool, tt.arguments)
+			if !isError || !strings.Contains(text, tt.expectedError) {
+				t.Errorf("Expected error containing %q, got %q", tt.expectedError, text)
+			}
+		})
+	}
+}
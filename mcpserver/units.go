@@ -0,0 +1,108 @@
+package mcpserver
+
+import (
+	"fmt"
+	"strings"
+)
+
+// defaultHoursPerDay is the length of a working day when neither the employee's profile, the
+// configuration nor the company's default hours give one
+const defaultHoursPerDay = 8
+
+// Where the length of a working day comes from
+const (
+	workDayFromProfile = "profile"
+	workDayFromConfig  = "config"
+	workDayFromCompany = "company"
+	workDayFromDefault = "default"
+)
+
+// WorkDay is the length of a working day, used to show time off in both days and hours
+type WorkDay struct {
+	Hours  float64 `json:"hours"`
+	Source string  `json:"source" jsonschema:"description=Where the length comes from: profile (the employee's standard hours per week), config (BAMBOOHR_HOURS_PER_DAY), company (the company's default hours) or default (8 hours)"`
+}
+
+// TimeOffAmount is an amount of time off in both days and hours
+type TimeOffAmount struct {
+	Days  float64 `json:"days"`
+	Hours float64 `json:"hours"`
+}
+
+// isHours reports whether a time-off unit is hours rather than days
+func isHours(unit string) bool {
+	return strings.HasPrefix(strings.ToLower(strings.TrimSpace(unit)), "hour")
+}
+
+// convert converts an amount of time off from one unit to the other
+func (w WorkDay) convert(amount float64, from, to string) float64 {
+	switch {
+	case isHours(from) == isHours(to), w.Hours <= 0:
+		return amount
+	case isHours(from):
+		return amount / w.Hours
+	default:
+		return amount * w.Hours
+	}
+}
+
+// amount returns an amount of time off in the unit in both days and hours
+func (w WorkDay) amount(amount float64, unit string) TimeOffAmount {
+	return TimeOffAmount{
+		Days:  roundAmount(w.convert(amount, unit, "days")),
+		Hours: roundAmount(w.convert(amount, unit, "hours")),
+	}
+}
+
+// format formats an amount of time off in its unit followed by the other unit, such as
+// "1.5 days / 12 hours". Amounts without a unit are days.
+func (w WorkDay) format(amount float64, unit string) string {
+	if unit == "" {
+		unit = "days"
+	}
+	if w.Hours <= 0 {
+		return formatAmount(amount, unit)
+	}
+
+	other := "hours"
+	if isHours(unit) {
+		other = "days"
+	}
+	return fmt.Sprintf("%s / %s", formatAmount(amount, unit), formatAmount(roundAmount(w.convert(amount, unit, other)), other))
+}
+
+// employeeWorkDay returns the length of the employee's working day: their standard hours per
+// week from their profile spread over five days, or else the company's working day
+func employeeWorkDay(client BambooHR, employeeID int, hoursPerDay float64) WorkDay {
+	// The profile only refines the conversion, so an employee whose profile can't be read gets
+	// the company's working day
+	if employee, err := client.GetEmployee(employeeID); err == nil && employee.StandardHoursPerWeek > 0 {
+		return WorkDay{Hours: roundAmount(float64(employee.StandardHoursPerWeek) / 5), Source: workDayFromProfile}
+	}
+
+	return companyWorkDay(client, hoursPerDay)
+}
+
+// companyWorkDay returns the configured hours per day, or else the average of the company's
+// default hours on the days that have them, or else 8 hours
+func companyWorkDay(client BambooHR, hoursPerDay float64) WorkDay {
+	if hoursPerDay > 0 {
+		return WorkDay{Hours: hoursPerDay, Source: workDayFromConfig}
+	}
+
+	if types, err := client.GetTimeOffTypes(); err == nil {
+		var total float64
+		var days int
+		for _, hours := range types.DefaultHours {
+			if hours.Amount > 0 {
+				total += float64(hours.Amount)
+				days++
+			}
+		}
+		if days > 0 {
+			return WorkDay{Hours: roundAmount(total / float64(days)), Source: workDayFromCompany}
+		}
+	}
+
+	return WorkDay{Hours: defaultHoursPerDay, Source: workDayFromDefault}
+}
@@ -0,0 +1,68 @@
+package mcpserver
+
+import (
+	"testing"
+
+	"bamboohr-mcp-server/bamboohr"
+)
+
+func TestWorkDay(t *testing.T) {
+	workDay := WorkDay{Hours: 7.5}
+
+	tests := []struct {
+		amount   float64
+		unit     string
+		expected string
+		days     float64
+		hours    float64
+	}{
+		{2, "days", "2 days / 15 hours", 2, 15},
+		{1, "day", "1 day / 7.5 hours", 1, 7.5},
+		{15, "hours", "15 hours / 2 days", 2, 15},
+		{1, "hours", "1 hour / 0.13 days", 0.13, 1},
+		{0.5, "", "0.5 days / 3.75 hours", 0.5, 3.75},
+	}
+
+	for _, tt := range tests {
+		if formatted := workDay.format(tt.amount, tt.unit); formatted != tt.expected {
+			t.Errorf("format(%v, %q): expected %q, got %q", tt.amount, tt.unit, tt.expected, formatted)
+		}
+		if amount := workDay.amount(tt.amount, tt.unit); amount.Days != tt.days || amount.Hours != tt.hours {
+			t.Errorf("amount(%v, %q): expected %v days and %v hours, got %+v", tt.amount, tt.unit, tt.days, tt.hours, amount)
+		}
+	}
+
+	// Without a working day amounts are only shown in their own unit
+	if formatted := (WorkDay{}).format(3, "hours"); formatted != "3 hours" {
+		t.Errorf("Expected \"3 hours\", got %q", formatted)
+	}
+}
+
+func TestEmployeeWorkDay(t *testing.T) {
+	fake := newFakeBambooHR(
+		bamboohr.Employee{ID: "4", StandardHoursPerWeek: 32},
+		bamboohr.Employee{ID: "5"},
+	)
+
+	tests := []struct {
+		name         string
+		employeeID   int
+		hoursPerDay  float64
+		defaultHours []bamboohr.DefaultHours
+		expected     WorkDay
+	}{
+		{"profile", 4, 7, nil, WorkDay{Hours: 6.4, Source: "profile"}},
+		{"config", 5, 7, nil, WorkDay{Hours: 7, Source: "config"}},
+		{"company", 5, 0, []bamboohr.DefaultHours{{Name: "Monday", Amount: 8}, {Name: "Friday", Amount: 6}, {Name: "Saturday"}}, WorkDay{Hours: 7, Source: "company"}},
+		{"unknown employee", 9, 0, nil, WorkDay{Hours: 8, Source: "default"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake.types.DefaultHours = tt.defaultHours
+			if workDay := employeeWorkDay(fake, tt.employeeID, tt.hoursPerDay); workDay != tt.expected {
+				t.Errorf("Expected %+v, got %+v", tt.expected, workDay)
+			}
+		})
+	}
+}
@@ -0,0 +1,163 @@
+package mcpserver
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+
+	"bamboohr-mcp-server/bamboohr"
+)
+
+// UpdatedTimeOffRequest is the structured output of update_time_off_request
+type UpdatedTimeOffRequest struct {
+	CreatedTimeOffRequest
+	Supersedes string `json:"supersedes" jsonschema:"description=The ID of the request replaced by this one"`
+}
+
+// findTimeOffRequest finds one of the employee's requests by ID, looking from the start of last
+// year to the end of next year
+func findTimeOffRequest(client BambooHR, dates *DateParser, employeeID int, requestID string) (*bamboohr.TimeOffRequest, error) {
+	year := dates.Today().Year()
+	start, end := yearRange(year-1, dates.Location()).StartYMD(), yearRange(year+1, dates.Location()).EndYMD()
+	requests, err := client.SearchTimeOffRequests(bamboohr.TimeOffRequestQuery{
+		Start:      start,
+		End:        end,
+		EmployeeID: employeeID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get time-off requests: %w", err)
+	}
+
+	for _, request := range requests {
+		if request.ID == requestID {
+			return &request, nil
+		}
+	}
+	return nil, fmt.Errorf("time-off request %s not found for employee %d between %s and %s; requests outside these dates can't be changed", requestID, employeeID, start, end)
+}
+
+// employeeNote returns the employee's note on a request
+func employeeNote(request bamboohr.TimeOffRequest) string {
+	for _, note := range request.Notes.Notes {
+		if note.From == "employee" || note.From == "" {
+			return note.Note
+		}
+	}
+	return ""
+}
+
+func handleUpdateTimeOffRequest(client BambooHR, dates *DateParser, me int, hoursPerDay float64) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		employeeIDStr, err := request.RequireString("employeeId")
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("employeeId is required: %s", err.Error())), nil
+		}
+
+		employeeID, err := resolveEmployeeID(employeeIDStr, me)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		requestID, err := request.RequireString("requestId")
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("requestId is required: %s", err.Error())), nil
+		}
+
+		previousRequest, err := strconv.Atoi(requestID)
+		if err != nil {
+			return mcp.NewToolResultError("requestId must be a valid integer"), nil
+		}
+
+		locale, err := toolLocale(request, dates)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		format, err := toolFormat(request, FormatFull)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		start := request.GetString("start", "")
+		end := request.GetString("end", "")
+		amountStr := request.GetString("amount", "")
+		note, hasNote := request.GetArguments()["employeeNote"].(string)
+		if amountStr == "" && request.GetString("unit", "") != "" {
+			return mcp.NewToolResultError("unit only applies to a new amount: give the amount in that unit"), nil
+		}
+		if start == "" && end == "" && amountStr == "" && !hasNote {
+			return mcp.NewToolResultError("nothing to update: give a new start, end, amount or employeeNote"), nil
+		}
+
+		existing, err := findTimeOffRequest(client, dates.ForEmployee(employeeID), employeeID, requestID)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		if isRejected(existing.Status.Status) {
+			return mcp.NewToolResultError(fmt.Sprintf("time-off request %s is %s and can't be changed", requestID, existing.Status.Status)), nil
+		}
+		if !existing.Actions.Edit {
+			return mcp.NewToolResultError(fmt.Sprintf("time-off request %s can't be edited with this API key", requestID)), nil
+		}
+
+		// Unchanged dates are kept, and a new start without an end takes the end of the start
+		// expression as create_time_off_request does
+		if start == "" {
+			start = existing.Start
+			if end == "" {
+				end = existing.End
+			}
+		}
+		period, err := dates.ForEmployee(employeeID).ResolveRange(start, end, DateRange{})
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		input := timeOffRequestInput{
+			EmployeeID:      employeeID,
+			Period:          period,
+			Amount:          float64(existing.Amount.Amount),
+			Unit:            existing.Amount.Unit,
+			Note:            employeeNote(*existing),
+			PreviousRequest: previousRequest,
+		}
+		if input.TimeOffTypeID, err = strconv.Atoi(existing.Type.ID); err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("time-off request %s has no valid time off type", requestID)), nil
+		}
+		switch {
+		case amountStr != "":
+			if input.Amount, err = strconv.ParseFloat(amountStr, 64); err != nil {
+				return mcp.NewToolResultError("amount must be a positive number, e.g. '1', '0.5' or '4'"), nil
+			}
+			input.Unit = request.GetString("unit", "")
+		case period.StartYMD() != existing.Start || period.EndYMD() != existing.End:
+			// New dates without an amount book their working days, as bulk_create_time_off does
+			days := workingDays(period)
+			if days == 0 {
+				return mcp.NewToolResultError(fmt.Sprintf("%s to %s has no working days: give an amount", period.StartYMD(), period.EndYMD())), nil
+			}
+			input.Amount, input.Unit = float64(days), "days"
+		}
+		if hasNote {
+			input.Note = note
+		}
+
+		created, err := createTimeOffRequest(client, input, hoursPerDay)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		result := UpdatedTimeOffRequest{CreatedTimeOffRequest: created, Supersedes: requestID}
+		return renderToolResult(result, updatedTimeOffRequestView(employeeID, result, locale), format), nil
+	}
+}
+
+// updatedTimeOffRequestView shows the request that replaced the updated one
+func updatedTimeOffRequestView(employeeID int, result UpdatedTimeOffRequest, locale Locale) textView {
+	view := timeOffRequestTable([]bamboohr.TimeOffRequest{result.TimeOffRequest}, locale, result.WorkDay)
+	view.Title = fmt.Sprintf("Updated time-off request %s for employee %d", result.Supersedes, employeeID)
+	return view
+}
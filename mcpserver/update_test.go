@@ -0,0 +1,133 @@
+package mcpserver
+
+import (
+	"strings"
+	"testing"
+
+	"bamboohr-mcp-server/bamboohr"
+)
+
+// newUpdateTestFake returns a fake where Dan has an editable request 20 for Oct 6-8 with a note,
+// an approved request 21 the API key can't edit, a canceled request 22 and a superseded request 23
+func newUpdateTestFake() *fakeBambooHR {
+	fake := newFakeBambooHR(orgChartTestEmployees()...)
+	fake.types.TimeOffTypes = []bamboohr.TimeOffType{{ID: "78", Name: "Vacation", Units: "days"}}
+
+	editable := approvalsTestRequest("20", "4", "Dan Diaz", "2025-10-06", "2025-10-08", "requested", 3, false)
+	editable.Actions.Edit = true
+	editable.Notes.Notes = []bamboohr.Note{{From: "employee", Note: "Family visit"}}
+	locked := approvalsTestRequest("21", "4", "Dan Diaz", "2025-11-03", "2025-11-03", "approved", 1, false)
+	canceled := approvalsTestRequest("22", "4", "Dan Diaz", "2025-12-01", "2025-12-01", "canceled", 1, false)
+	canceled.Actions.Edit = true
+	superseded := approvalsTestRequest("23", "4", "Dan Diaz", "2025-12-08", "2025-12-08", "superceded", 1, false)
+	superseded.Actions.Edit = true
+
+	fake.requests[4] = []bamboohr.TimeOffRequest{editable, locked, canceled, superseded}
+	return fake
+}
+
+func TestHandleUpdateTimeOffRequest(t *testing.T) {
+	tests := []struct {
+		name          string
+		arguments     map[string]string
+		expectedStart string
+		expectedEnd   string
+		expectedAmt   float64
+		expectedNote  string
+	}{
+		{
+			name:          "new dates book their working days and keep the note",
+			arguments:     map[string]string{"start": "Oct 10 - Oct 13"},
+			expectedStart: "2025-10-10",
+			expectedEnd:   "2025-10-13",
+			expectedAmt:   2,
+			expectedNote:  "Family visit",
+		},
+		{
+			name:          "new end keeps the start",
+			arguments:     map[string]string{"end": "2025-10-07", "amount": "2"},
+			expectedStart: "2025-10-06",
+			expectedEnd:   "2025-10-07",
+			expectedAmt:   2,
+			expectedNote:  "Family visit",
+		},
+		{
+			name:          "amount in hours and a new note",
+			arguments:     map[string]string{"amount": "12", "unit": "hours", "employeeNote": "Shorter trip"},
+			expectedStart: "2025-10-06",
+			expectedEnd:   "2025-10-08",
+			expectedAmt:   1.5,
+			expectedNote:  "Shorter trip",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := newUpdateTestFake()
+			s := New(fake, newTestDateParser(), WithCurrentEmployee(4))
+
+			tt.arguments["employeeId"] = "me"
+			tt.arguments["requestId"] = "20"
+			tt.arguments["format"] = "compact"
+			text, isError := callTool(t, s, "update_time_off_request", tt.arguments)
+			if isError {
+				t.Fatalf("Unexpected tool error: %s", text)
+			}
+			if !strings.Contains(text, "Updated time-off request 20 for employee 4") {
+				t.Errorf("Expected the updated request in the title, got %q", text)
+			}
+
+			if len(fake.created) != 1 {
+				t.Fatalf("Expected 1 created request, got %d", len(fake.created))
+			}
+			created := fake.created[0]
+			if created.PreviousRequest != 20 || created.TimeOffTypeID != 78 || created.Status != "requested" {
+				t.Errorf("Expected a request superseding 20, got %+v", created)
+			}
+			if created.Start != tt.expectedStart || created.End != tt.expectedEnd || created.Amount != tt.expectedAmt {
+				t.Errorf("Expected %s to %s for %v, got %+v", tt.expectedStart, tt.expectedEnd, tt.expectedAmt, created)
+			}
+			if len(created.Notes) != 1 || created.Notes[0].Note != tt.expectedNote {
+				t.Errorf("Expected note %q, got %+v", tt.expectedNote, created.Notes)
+			}
+			if status := fake.requests[4][0].Status.Status; status != "superceded" {
+				t.Errorf("Expected the previous request to be superseded, got %s", status)
+			}
+		})
+	}
+}
+
+func TestHandleUpdateTimeOffRequest_Errors(t *testing.T) {
+	tests := []struct {
+		name          string
+		arguments     map[string]string
+		expectedError string
+	}{
+		{"nothing to change", map[string]string{"requestId": "20"}, "nothing to update"},
+		{"unknown request", map[string]string{"requestId": "99", "amount": "1"}, "not found for employee 4 between 2024-01-01 and 2026-12-31"},
+		{"unit without amount", map[string]string{"requestId": "20", "unit": "hours"}, "unit only applies to a new amount"},
+		{"not editable", map[string]string{"requestId": "21", "amount": "2"}, "can't be edited"},
+		{"closed", map[string]string{"requestId": "22", "amount": "2"}, "is canceled"},
+		{"superseded", map[string]string{"requestId": "23", "amount": "2"}, "is superceded"},
+		{"weekend without amount", map[string]string{"requestId": "20", "start": "2025-10-11 - 2025-10-12"}, "has no working days"},
+		{"invalid amount", map[string]string{"requestId": "20", "amount": "-1"}, "amount must be a positive number"},
+		{"invalid request ID", map[string]string{"requestId": "abc", "amount": "1"}, "requestId must be a valid integer"},
+		{"end before start", map[string]string{"requestId": "20", "end": "2025-10-01"}, "is before start"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := newUpdateTestFake()
+			s := New(fake, newTestDateParser())
+
+			tt.arguments["employeeId"] = "4"
+			text, isError := callTool(t, s, "update_time_off_request", tt.arguments)
+			if !isError || !strings.Contains(text, tt.expectedError) {
+				t.Errorf("Expected error containing %q, got %q", tt.expectedError, text)
+			}
+			if len(fake.created) != 0 {
+				t.Errorf("Expected no request to be created, got %+v", fake.created)
+			}
+		})
+	}
+}
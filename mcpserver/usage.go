@@ -0,0 +1,376 @@
+package mcpserver
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+
+	"bamboohr-mcp-server/bamboohr"
+)
+
+// usageDimensions lists the groupBy dimensions of report_time_off_usage
+var usageDimensions = []string{"employee", "department", "type", "month", "status"}
+
+// rejectedStatuses are the statuses of requests that were never taken and can no longer change
+var rejectedStatuses = []string{"denied", "canceled", "cancelled", "superceded"}
+
+// isRejected reports whether a request status is one of rejectedStatuses
+func isRejected(status string) bool {
+	return slices.Contains(rejectedStatuses, strings.ToLower(status))
+}
+
+// UsageReportGroup is the time off of one combination of the grouped dimensions
+type UsageReportGroup struct {
+	Keys      map[string]string `json:"keys" jsonschema:"description=The value of each groupBy dimension"`
+	Requests  int               `json:"requests"`
+	Employees int               `json:"employees"`
+	Days      float64           `json:"days"`
+	Hours     float64           `json:"hours"`
+}
+
+// UsageReportSummary totals the time off across all groups
+type UsageReportSummary struct {
+	Requests           int     `json:"requests"`
+	Employees          int     `json:"employees"`
+	Days               float64 `json:"days"`
+	Hours              float64 `json:"hours"`
+	AverageDays        float64 `json:"averageDays" jsonschema:"description=Days per employee with time off"`
+	MedianDays         float64 `json:"medianDays" jsonschema:"description=Median days per employee with time off"`
+	MaxEmployeeDays    float64 `json:"maxEmployeeDays" jsonschema:"description=Most days taken by one employee"`
+	MaxEmployeeName    string  `json:"maxEmployeeName,omitempty"`
+	NormalizedToDays   bool    `json:"normalizedToDays" jsonschema:"description=Whether amounts were converted so that days and hours both hold the total, rather than the time off booked in each unit"`
+	ExcludedRejections bool    `json:"excludedRejections" jsonschema:"description=Whether denied, cancelled and superseded requests were left out"`
+}
+
+// UsageReportResult is the structured output of report_time_off_usage
+type UsageReportResult struct {
+	Start   string             `json:"start"`
+	End     string             `json:"end"`
+	GroupBy []string           `json:"groupBy"`
+	Summary UsageReportSummary `json:"summary"`
+	Groups  []UsageReportGroup `json:"groups"`
+}
+
+// usageReportOptions are the options of a time-off usage report
+type usageReportOptions struct {
+	groupBy         []string
+	excludeRejected bool
+	normalize       bool
+	// hoursPerDay converts hours to days. Zero uses the company's default hours for each weekday.
+	hoursPerDay  float64
+	defaultHours map[string]float64
+	departments  map[string]string
+}
+
+func handleReportTimeOffUsage(client BambooHR, dates *DateParser, me int, hoursPerDay float64) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		format, err := toolFormat(request, FormatCompact)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		locale, err := toolLocale(request, dates)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		opts := usageReportOptions{
+			groupBy:         splitList(strings.ToLower(request.GetString("groupBy", "type"))),
+			excludeRejected: request.GetBool("excludeRejected", true),
+			normalize:       request.GetBool("normalizeToDays", true),
+			hoursPerDay:     request.GetFloat("hoursPerDay", hoursPerDay),
+		}
+		if len(opts.groupBy) == 0 {
+			return mcp.NewToolResultError("groupBy must list at least one dimension"), nil
+		}
+		for _, dimension := range opts.groupBy {
+			if !slices.Contains(usageDimensions, dimension) {
+				return mcp.NewToolResultError(fmt.Sprintf("unsupported groupBy %q: expected a comma-separated list of %s", dimension, strings.Join(usageDimensions, ", "))), nil
+			}
+		}
+		if opts.hoursPerDay < 0 || opts.hoursPerDay > 24 {
+			return mcp.NewToolResultError("hoursPerDay must be between 0 and 24"), nil
+		}
+
+		// Report on the whole company unless a team is given
+		var ids []int
+		employeeIDs, department := request.GetString("employeeIds", ""), request.GetString("department", "")
+		managerID, err := toolManagerID(request, me)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		team := employeeIDs != "" || department != "" || managerID != 0
+		if team {
+			if ids, err = teamEmployeeIDs(client, employeeIDs, department, managerID); err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+		}
+
+		parser := dates.ForEmployees(ids)
+		period, err := parser.ResolveRange(request.GetString("start", ""), request.GetString("end", ""), parser.CurrentYear())
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		typeIDs, err := resolveTimeOffTypeIDs(client, request.GetString("timeOffType", ""))
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		query := bamboohr.TimeOffRequestQuery{Start: period.StartYMD(), End: period.EndYMD(), TypeIDs: typeIDs}
+
+		var requests []bamboohr.TimeOffRequest
+		if team {
+			requests, err = teamTimeOffRequests(client, ids, query)
+			if err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("Failed to get time-off requests: %s", err.Error())), nil
+			}
+		} else {
+			requests, err = client.SearchTimeOffRequests(query)
+			if err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("Failed to get time-off requests: %s", err.Error())), nil
+			}
+		}
+
+		if slices.Contains(opts.groupBy, "department") {
+			directory, err := client.GetEmployeeDirectory()
+			if err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("Failed to get employee directory: %s", err.Error())), nil
+			}
+			opts.departments = make(map[string]string, len(directory.Employees))
+			for _, employee := range directory.Employees {
+				opts.departments[employee.ID] = employee.Department
+			}
+		}
+
+		if opts.normalize && opts.hoursPerDay == 0 {
+			types, err := client.GetTimeOffTypes()
+			if err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("Failed to get time off types: %s", err.Error())), nil
+			}
+			opts.defaultHours = make(map[string]float64)
+			for _, hours := range types.DefaultHours {
+				opts.defaultHours[strings.ToLower(hours.Name)] = float64(hours.Amount)
+			}
+		}
+
+		result := reportTimeOffUsage(requests, period, opts)
+		return renderToolResult(result, usageReportView(result, locale), format), nil
+	}
+}
+
+// reportTimeOffUsage totals the amount of each request that falls within the period by the
+// combination of the groupBy dimensions, and summarizes the totals per employee
+func reportTimeOffUsage(requests []bamboohr.TimeOffRequest, period DateRange, opts usageReportOptions) UsageReportResult {
+	result := UsageReportResult{
+		Start:   period.StartYMD(),
+		End:     period.EndYMD(),
+		GroupBy: opts.groupBy,
+		Summary: UsageReportSummary{NormalizedToDays: opts.normalize, ExcludedRejections: opts.excludeRejected},
+		Groups:  []UsageReportGroup{},
+	}
+
+	if opts.excludeRejected {
+		var taken []bamboohr.TimeOffRequest
+		for _, request := range requests {
+			if !isRejected(request.Status.Status) {
+				taken = append(taken, request)
+			}
+		}
+		requests = taken
+	}
+
+	split := bookedAmount
+	if opts.normalize {
+		split = func(request bamboohr.TimeOffRequest, day string, amount float64) (float64, float64) {
+			workDay := WorkDay{Hours: opts.dayLength(day)}
+			return workDay.convert(amount, request.Amount.Unit, "days"), workDay.convert(amount, request.Amount.Unit, "hours")
+		}
+	}
+
+	groupKey := func(request bamboohr.TimeOffRequest, day string) string {
+		var values []string
+		for _, dimension := range opts.groupBy {
+			values = append(values, usageDimensionValue(dimension, request, day, opts.departments))
+		}
+		return strings.Join(values, "\x00")
+	}
+	for _, total := range tallyTimeOff(requests, period, groupKey, split) {
+		keys := make(map[string]string, len(opts.groupBy))
+		for i, value := range strings.Split(total.key, "\x00") {
+			keys[opts.groupBy[i]] = value
+		}
+		result.Groups = append(result.Groups, UsageReportGroup{
+			Keys:      keys,
+			Requests:  len(total.requests),
+			Employees: len(total.employees),
+			Days:      roundAmount(total.days),
+			Hours:     roundAmount(total.hours),
+		})
+	}
+
+	// Each request is one employee's, so the employees' totals add up to the summary
+	employeeDays := make(map[string]float64)
+	employeeNames := make(map[string]string)
+	for _, request := range requests {
+		employeeNames[request.EmployeeID] = request.Name
+	}
+	employeeKey := func(request bamboohr.TimeOffRequest, _ string) string {
+		return request.EmployeeID
+	}
+	for _, total := range tallyTimeOff(requests, period, employeeKey, split) {
+		employeeDays[total.key] = total.days
+		result.Summary.Requests += len(total.requests)
+		result.Summary.Days += total.days
+		result.Summary.Hours += total.hours
+	}
+
+	// Months are listed in order, other groupings by the most time off first
+	sort.SliceStable(result.Groups, func(i, j int) bool {
+		for _, dimension := range opts.groupBy {
+			a, b := result.Groups[i].Keys[dimension], result.Groups[j].Keys[dimension]
+			if dimension == "month" && a != b {
+				return a < b
+			}
+		}
+		a, b := result.Groups[i].Days+result.Groups[i].Hours, result.Groups[j].Days+result.Groups[j].Hours
+		if a != b {
+			return a > b
+		}
+		return strings.Join(usageKeys(result.Groups[i], opts.groupBy), ",") < strings.Join(usageKeys(result.Groups[j], opts.groupBy), ",")
+	})
+
+	summary := &result.Summary
+	summary.Employees = len(employeeDays)
+	summary.Days, summary.Hours = roundAmount(summary.Days), roundAmount(summary.Hours)
+	if summary.Employees > 0 {
+		var perEmployee []float64
+		for id, days := range employeeDays {
+			perEmployee = append(perEmployee, days)
+			if days > summary.MaxEmployeeDays || (days == summary.MaxEmployeeDays && employeeNames[id] < summary.MaxEmployeeName) {
+				summary.MaxEmployeeDays = days
+				summary.MaxEmployeeName = employeeNames[id]
+			}
+		}
+		sort.Float64s(perEmployee)
+
+		middle := len(perEmployee) / 2
+		summary.MedianDays = perEmployee[middle]
+		if len(perEmployee)%2 == 0 {
+			summary.MedianDays = (perEmployee[middle-1] + perEmployee[middle]) / 2
+		}
+		summary.AverageDays = roundAmount(summary.Days / float64(summary.Employees))
+		summary.MedianDays = roundAmount(summary.MedianDays)
+		summary.MaxEmployeeDays = roundAmount(summary.MaxEmployeeDays)
+	}
+
+	return result
+}
+
+// dayLength returns the hours in a working day, used to convert hours to days
+func (o usageReportOptions) dayLength(day string) float64 {
+	if o.hoursPerDay > 0 {
+		return o.hoursPerDay
+	}
+
+	if date, err := time.Parse(dateLayout, day); err == nil {
+		if hours := o.defaultHours[strings.ToLower(date.Weekday().String())]; hours > 0 {
+			return hours
+		}
+		if hours := o.defaultHours[strings.ToLower(date.Weekday().String()[:3])]; hours > 0 {
+			return hours
+		}
+	}
+
+	return defaultHoursPerDay
+}
+
+// usageDimensionValue returns the value of the dimension for a day of the request
+func usageDimensionValue(dimension string, request bamboohr.TimeOffRequest, day string, departments map[string]string) string {
+	var value string
+	switch dimension {
+	case "employee":
+		value = fmt.Sprintf("%s (ID %s)", request.Name, request.EmployeeID)
+	case "department":
+		value = departments[request.EmployeeID]
+	case "type":
+		value = request.Type.Name
+	case "month":
+		value = day[:7]
+	case "status":
+		value = request.Status.Status
+	}
+
+	if value == "" {
+		return "Unknown"
+	}
+	return value
+}
+
+// usageKeys returns the group's value of each dimension in order
+func usageKeys(group UsageReportGroup, groupBy []string) []string {
+	keys := make([]string, 0, len(groupBy))
+	for _, dimension := range groupBy {
+		keys = append(keys, group.Keys[dimension])
+	}
+	return keys
+}
+
+// usageReportView shows the usage report as text, with the summary statistics in the title
+func usageReportView(result UsageReportResult, locale Locale) textView {
+	summary := result.Summary
+
+	title := fmt.Sprintf("Time off from %s to %s by %s: %s by %s, %s", locale.FormatDate(result.Start), locale.FormatDate(result.End),
+		strings.Join(result.GroupBy, " and "), pluralize(summary.Requests, "request", "requests"),
+		pluralize(summary.Employees, "employee", "employees"), usageAmount(summary.Days, summary.Hours, summary.NormalizedToDays))
+	if summary.Employees > 0 {
+		title += fmt.Sprintf(" (%s per employee on average, median %s, most %s by %s)",
+			formatAmount(summary.AverageDays, "days"), formatAmount(summary.MedianDays, "days"),
+			formatAmount(summary.MaxEmployeeDays, "days"), summary.MaxEmployeeName)
+	}
+
+	view := textView{Title: title}
+	for _, dimension := range result.GroupBy {
+		view.Columns = append(view.Columns, strings.ToUpper(dimension[:1])+dimension[1:])
+	}
+	view.Columns = append(view.Columns, "Requests", "Employees", "Days", "Hours")
+
+	for _, group := range result.Groups {
+		keys := usageKeys(group, result.GroupBy)
+
+		view.Items = append(view.Items, fmt.Sprintf("%s: %s in %s by %s", strings.Join(keys, ", "),
+			usageAmount(group.Days, group.Hours, summary.NormalizedToDays), pluralize(group.Requests, "request", "requests"),
+			pluralize(group.Employees, "employee", "employees")))
+		view.Rows = append(view.Rows, append(keys,
+			strconv.Itoa(group.Requests),
+			strconv.Itoa(group.Employees),
+			strconv.FormatFloat(group.Days, 'f', -1, 64),
+			strconv.FormatFloat(group.Hours, 'f', -1, 64),
+		))
+	}
+
+	return view
+}
+
+// usageAmount describes the time off of a group or the summary. Normalized days and hours are
+// the same total in each unit, otherwise they are the time off booked in days and in hours.
+func usageAmount(days, hours float64, normalized bool) string {
+	if normalized {
+		return fmt.Sprintf("%s / %s", formatAmount(days, "days"), formatAmount(hours, "hours"))
+	}
+
+	var amounts []string
+	if days != 0 || hours == 0 {
+		amounts = append(amounts, formatAmount(days, "days"))
+	}
+	if hours != 0 {
+		amounts = append(amounts, formatAmount(hours, "hours"))
+	}
+	return strings.Join(amounts, " and ")
+}
@@ -0,0 +1,91 @@
+package mcpserver
+
+import (
+	"testing"
+
+	"bamboohr-mcp-server/bamboohr"
+)
+
+// newUsageTestFake returns a company with approved, denied and hour-based time off
+func newUsageTestFake() *fakeBambooHR {
+	fake := newFakeBambooHR(orgChartTestEmployees()...)
+
+	sick := approvalsTestRequest("21", "4", "Dan Diaz", "2025-10-20", "2025-10-20", "approved", 4, false)
+	sick.Type.ID, sick.Type.Name = "83", "Sick"
+	sick.Amount.Unit = "hours"
+	sick.Dates = map[string]string{"2025-10-20": "4"}
+
+	fake.requests[3] = []bamboohr.TimeOffRequest{
+		approvalsTestRequest("23", "3", "Cleo Cruz", "2025-11-03", "2025-11-04", "approved", 2, false),
+	}
+	fake.requests[4] = []bamboohr.TimeOffRequest{
+		approvalsTestRequest("20", "4", "Dan Diaz", "2025-10-06", "2025-10-08", "approved", 3, false),
+		sick,
+	}
+	fake.requests[5] = []bamboohr.TimeOffRequest{
+		approvalsTestRequest("22", "5", "Eve Evans", "2025-10-07", "2025-10-07", "denied", 1, false),
+	}
+	fake.types.TimeOffTypes = []bamboohr.TimeOffType{{ID: "78", Name: "Vacation"}, {ID: "83", Name: "Sick"}}
+	return fake
+}
+
+func TestReportTimeOffUsage(t *testing.T) {
+	fake := newUsageTestFake()
+	s := New(fake, newTestDateParser())
+
+	text, isError := callTool(t, s, "report_time_off_usage", map[string]string{"groupBy": "department,type"})
+	expected := "Time off from 2025-01-01 to 2025-12-31 by department and type: 3 requests by 2 employees, 5.5 days / 44 hours " +
+		"(2.75 days per employee on average, median 2.75 days, most 3.5 days by Dan Diaz):\n" +
+		"- Engineering, Vacation: 3 days / 24 hours in 1 request by 1 employee\n" +
+		"- Finance, Vacation: 2 days / 16 hours in 1 request by 1 employee\n" +
+		"- Engineering, Sick: 0.5 days / 4 hours in 1 request by 1 employee"
+	if isError || text != expected {
+		t.Errorf("Expected %q, got %q", expected, text)
+	}
+
+	// The company's default hours for Monday are used to convert hours to days, and both units
+	// hold the total
+	fake.types.DefaultHours = []bamboohr.DefaultHours{{Name: "Monday", Amount: 4}}
+	text, _ = callTool(t, s, "report_time_off_usage", map[string]string{"groupBy": "type", "timeOffType": "Sick", "format": "csv"})
+	if expected := "Type,Requests,Employees,Days,Hours\nSick,1,1,1,4\n"; text != expected {
+		t.Errorf("Expected %q, got %q", expected, text)
+	}
+
+	text, _ = callTool(t, s, "report_time_off_usage", map[string]string{
+		"groupBy": "month,status", "excludeRejected": "false", "normalizeToDays": "false", "employeeIds": "4,5", "format": "markdown",
+	})
+	expected = "Time off from 2025-01-01 to 2025-12-31 by month and status: 3 requests by 2 employees, 4 days and 4 hours " +
+		"(2 days per employee on average, median 2 days, most 3 days by Dan Diaz):\n\n" +
+		"| Month | Status | Requests | Employees | Days | Hours |\n" +
+		"| --- | --- | --- | --- | --- | --- |\n" +
+		"| 2025-10 | approved | 2 | 1 | 3 | 4 |\n" +
+		"| 2025-10 | denied | 1 | 1 | 1 | 0 |"
+	if text != expected {
+		t.Errorf("Expected %q, got %q", expected, text)
+	}
+
+	errorCases := []map[string]string{
+		{"groupBy": "location"},
+		{"groupBy": ","},
+		{"hoursPerDay": "25"},
+		{"timeOffType": "Bereavement"},
+	}
+	for _, arguments := range errorCases {
+		if text, isError := callTool(t, s, "report_time_off_usage", arguments); !isError {
+			t.Errorf("Expected tool error for %v, got %q", arguments, text)
+		}
+	}
+}
+
+func TestReportTimeOffUsage_Empty(t *testing.T) {
+	result := reportTimeOffUsage(nil, newTestDateParser().CurrentYear(), usageReportOptions{groupBy: []string{"type"}})
+
+	if result.Summary.Requests != 0 || len(result.Groups) != 0 {
+		t.Errorf("Expected an empty report, got %+v", result)
+	}
+
+	view := usageReportView(result, Locale{})
+	if view.Title != "Time off from 2025-01-01 to 2025-12-31 by type: 0 requests by 0 employees, 0 days" {
+		t.Errorf("Unexpected title: %q", view.Title)
+	}
+}
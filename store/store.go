@@ -0,0 +1,398 @@
+// Package store keeps a local SQLite copy of BambooHR employees, time-off requests and balances,
+// for querying history without calling the API and for serving reads while BambooHR is unreachable.
+package store
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	_ "modernc.org/sqlite"
+
+	"bamboohr-mcp-server/bamboohr"
+)
+
+// ErrNotFound is returned when a record is not in the store
+var ErrNotFound = errors.New("not found in local store")
+
+// schema creates the store tables. Records are kept as JSON, with the columns used for filtering
+// copied alongside.
+const schema = `
+CREATE TABLE IF NOT EXISTS employees (
+	id INTEGER PRIMARY KEY,
+	department TEXT NOT NULL,
+	data TEXT NOT NULL
+);
+CREATE TABLE IF NOT EXISTS time_off_requests (
+	id TEXT PRIMARY KEY,
+	employee_id INTEGER NOT NULL,
+	status TEXT NOT NULL,
+	type_id TEXT NOT NULL,
+	type_name TEXT NOT NULL,
+	start_date TEXT NOT NULL,
+	end_date TEXT NOT NULL,
+	data TEXT NOT NULL
+);
+CREATE INDEX IF NOT EXISTS time_off_requests_employee ON time_off_requests (employee_id, start_date);
+CREATE TABLE IF NOT EXISTS time_off_balances (
+	employee_id INTEGER NOT NULL,
+	time_off_type TEXT NOT NULL,
+	data TEXT NOT NULL,
+	PRIMARY KEY (employee_id, time_off_type)
+);
+CREATE TABLE IF NOT EXISTS sync_state (
+	key TEXT PRIMARY KEY,
+	value TEXT NOT NULL
+);
+`
+
+// Store is a local SQLite copy of BambooHR data
+type Store struct {
+	db *sql.DB
+}
+
+// Open opens the SQLite store at path, creating it if needed
+func Open(path string) (*Store, error) {
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		return nil, fmt.Errorf("opening store: %w", err)
+	}
+
+	// SQLite allows a single writer, so serialize access through one connection
+	db.SetMaxOpenConns(1)
+
+	if _, err := db.Exec(schema); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("creating store schema: %w", err)
+	}
+
+	return &Store{db: db}, nil
+}
+
+// Close closes the store
+func (s *Store) Close() error {
+	return s.db.Close()
+}
+
+// Source is the BambooHR API the store is populated from
+type Source interface {
+	GetEmployeeDirectory() (*bamboohr.EmployeeDirectory, error)
+	GetTimeOffRequests(employeeID int, start, end string) ([]bamboohr.TimeOffRequest, error)
+	GetTimeOffBalance(employeeID int) ([]bamboohr.TimeOffBalance, error)
+	GetTimeOffTypes() (*bamboohr.TimeOffTypes, error)
+}
+
+// SyncResult counts the records written by a sync
+type SyncResult struct {
+	Employees int
+	Requests  int
+	Balances  int
+}
+
+// Sync downloads the directory, time off types, and every employee's balances and time-off
+// requests between start and end (YYYY-MM-DD), and replaces the stored copies. Requests outside
+// the period are kept, so history builds up over successive syncs. Nothing is written unless
+// every download succeeds.
+func (s *Store) Sync(source Source, start, end string, now time.Time) (SyncResult, error) {
+	var result SyncResult
+
+	directory, err := source.GetEmployeeDirectory()
+	if err != nil {
+		return result, fmt.Errorf("failed to get employee directory: %w", err)
+	}
+
+	types, err := source.GetTimeOffTypes()
+	if err != nil {
+		return result, fmt.Errorf("failed to get time off types: %w", err)
+	}
+
+	requests := make(map[int][]bamboohr.TimeOffRequest)
+	balances := make(map[int][]bamboohr.TimeOffBalance)
+	for _, employee := range directory.Employees {
+		id, err := strconv.Atoi(employee.ID)
+		if err != nil {
+			continue
+		}
+
+		if requests[id], err = source.GetTimeOffRequests(id, start, end); err != nil {
+			return result, fmt.Errorf("failed to get time-off requests for employee %d: %w", id, err)
+		}
+		if balances[id], err = source.GetTimeOffBalance(id); err != nil {
+			return result, fmt.Errorf("failed to get time-off balance for employee %d: %w", id, err)
+		}
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return result, fmt.Errorf("starting transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// Employees no longer in the directory are removed; their request history is kept
+	if _, err := tx.Exec(`DELETE FROM employees`); err != nil {
+		return result, fmt.Errorf("clearing employees: %w", err)
+	}
+	if _, err := tx.Exec(`DELETE FROM time_off_balances`); err != nil {
+		return result, fmt.Errorf("clearing balances: %w", err)
+	}
+
+	for _, employee := range directory.Employees {
+		id, err := strconv.Atoi(employee.ID)
+		if err != nil {
+			continue
+		}
+
+		if err := insertJSON(tx, `INSERT INTO employees (id, department, data) VALUES (?, ?, ?)`, employee, id, employee.Department); err != nil {
+			return result, fmt.Errorf("storing employee %d: %w", id, err)
+		}
+		result.Employees++
+
+		// Requests deleted in BambooHR disappear from the period, so replace rather than merge
+		if _, err := tx.Exec(`DELETE FROM time_off_requests WHERE employee_id = ? AND end_date >= ? AND start_date <= ?`, id, start, end); err != nil {
+			return result, fmt.Errorf("clearing time-off requests for employee %d: %w", id, err)
+		}
+		for _, request := range requests[id] {
+			if err := insertJSON(tx, `INSERT OR REPLACE INTO time_off_requests (id, employee_id, status, type_id, type_name, start_date, end_date, data) VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+				request, request.ID, id, request.Status.Status, request.Type.ID, request.Type.Name, request.Start, request.End); err != nil {
+				return result, fmt.Errorf("storing time-off request %s: %w", request.ID, err)
+			}
+			result.Requests++
+		}
+
+		for _, balance := range balances[id] {
+			if err := insertJSON(tx, `INSERT OR REPLACE INTO time_off_balances (employee_id, time_off_type, data) VALUES (?, ?, ?)`, balance, id, balance.TimeOffType); err != nil {
+				return result, fmt.Errorf("storing time-off balance for employee %d: %w", id, err)
+			}
+			result.Balances++
+		}
+	}
+
+	state := map[string]interface{}{
+		"fields":         directory.Fields,
+		"time_off_types": types,
+		"synced_at":      now.UTC().Format(time.RFC3339),
+	}
+	for key, value := range state {
+		if err := insertJSON(tx, `INSERT OR REPLACE INTO sync_state (key, value) VALUES (?, ?)`, value, key); err != nil {
+			return result, fmt.Errorf("storing sync state: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return result, fmt.Errorf("committing sync: %w", err)
+	}
+
+	return result, nil
+}
+
+// insertJSON executes the statement with the arguments followed by v encoded as JSON
+func insertJSON(tx *sql.Tx, statement string, v interface{}, args ...interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(statement, append(args, string(data))...)
+	return err
+}
+
+// SyncedAt returns the time of the last successful sync, or ErrNotFound if the store has never
+// been synced
+func (s *Store) SyncedAt() (time.Time, error) {
+	var value string
+	if err := s.state("synced_at", &value); err != nil {
+		return time.Time{}, err
+	}
+
+	return time.Parse(time.RFC3339, value)
+}
+
+// state decodes the sync state value stored under key into v
+func (s *Store) state(key string, v interface{}) error {
+	var data string
+	err := s.db.QueryRow(`SELECT value FROM sync_state WHERE key = ?`, key).Scan(&data)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("reading sync state: %w", err)
+	}
+
+	return json.Unmarshal([]byte(data), v)
+}
+
+// EmployeeDirectory returns the stored employee directory
+func (s *Store) EmployeeDirectory() (*bamboohr.EmployeeDirectory, error) {
+	var directory bamboohr.EmployeeDirectory
+	if err := s.state("fields", &directory.Fields); err != nil {
+		return nil, err
+	}
+
+	if err := s.query(`SELECT data FROM employees ORDER BY id`, nil, func(data []byte) error {
+		var employee bamboohr.Employee
+		if err := json.Unmarshal(data, &employee); err != nil {
+			return err
+		}
+		directory.Employees = append(directory.Employees, employee)
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	return &directory, nil
+}
+
+// Employee returns the stored employee
+func (s *Store) Employee(employeeID int) (*bamboohr.Employee, error) {
+	var data string
+	err := s.db.QueryRow(`SELECT data FROM employees WHERE id = ?`, employeeID).Scan(&data)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("employee %d: %w", employeeID, ErrNotFound)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading employee: %w", err)
+	}
+
+	var employee bamboohr.Employee
+	if err := json.Unmarshal([]byte(data), &employee); err != nil {
+		return nil, fmt.Errorf("decoding employee: %w", err)
+	}
+
+	return &employee, nil
+}
+
+// TimeOffTypes returns the stored time off types
+func (s *Store) TimeOffTypes() (*bamboohr.TimeOffTypes, error) {
+	var types bamboohr.TimeOffTypes
+	if err := s.state("time_off_types", &types); err != nil {
+		return nil, err
+	}
+
+	return &types, nil
+}
+
+// TimeOffBalance returns the stored balances of the employee
+func (s *Store) TimeOffBalance(employeeID int) ([]bamboohr.TimeOffBalance, error) {
+	var balances []bamboohr.TimeOffBalance
+	if err := s.query(`SELECT data FROM time_off_balances WHERE employee_id = ? ORDER BY time_off_type`, []interface{}{employeeID}, func(data []byte) error {
+		var balance bamboohr.TimeOffBalance
+		if err := json.Unmarshal(data, &balance); err != nil {
+			return err
+		}
+		balances = append(balances, balance)
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	if balances == nil {
+		return nil, fmt.Errorf("balances of employee %d: %w", employeeID, ErrNotFound)
+	}
+
+	return balances, nil
+}
+
+// RequestFilter selects stored time-off requests. Empty fields match every request.
+type RequestFilter struct {
+	// Start and End select requests overlapping the period (YYYY-MM-DD)
+	Start string
+	End   string
+
+	EmployeeIDs []int
+	// Department matches the employee's current department, case-insensitively
+	Department string
+	// Statuses matches request statuses such as "approved"
+	Statuses []string
+	// TimeOffType matches the type ID or, case-insensitively, the type name
+	TimeOffType string
+}
+
+// TimeOffRequests returns the stored requests of the employee overlapping the period
+func (s *Store) TimeOffRequests(employeeID int, start, end string) ([]bamboohr.TimeOffRequest, error) {
+	return s.SearchTimeOffRequests(RequestFilter{Start: start, End: end, EmployeeIDs: []int{employeeID}})
+}
+
+// SearchTimeOffRequests returns the stored requests matching the filter, ordered by start date
+func (s *Store) SearchTimeOffRequests(filter RequestFilter) ([]bamboohr.TimeOffRequest, error) {
+	var conditions []string
+	var args []interface{}
+
+	if filter.Start != "" {
+		conditions = append(conditions, "r.end_date >= ?")
+		args = append(args, filter.Start)
+	}
+	if filter.End != "" {
+		conditions = append(conditions, "r.start_date <= ?")
+		args = append(args, filter.End)
+	}
+	if len(filter.EmployeeIDs) > 0 {
+		conditions = append(conditions, "r.employee_id IN ("+placeholders(len(filter.EmployeeIDs))+")")
+		for _, id := range filter.EmployeeIDs {
+			args = append(args, id)
+		}
+	}
+	if filter.Department != "" {
+		conditions = append(conditions, "r.employee_id IN (SELECT id FROM employees WHERE department = ? COLLATE NOCASE)")
+		args = append(args, filter.Department)
+	}
+	if len(filter.Statuses) > 0 {
+		conditions = append(conditions, "r.status IN ("+placeholders(len(filter.Statuses))+")")
+		for _, status := range filter.Statuses {
+			args = append(args, status)
+		}
+	}
+	if filter.TimeOffType != "" {
+		conditions = append(conditions, "(r.type_id = ? OR r.type_name = ? COLLATE NOCASE)")
+		args = append(args, filter.TimeOffType, filter.TimeOffType)
+	}
+
+	query := `SELECT r.data FROM time_off_requests r`
+	if len(conditions) > 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
+	}
+	query += " ORDER BY r.start_date, r.employee_id, r.id"
+
+	var requests []bamboohr.TimeOffRequest
+	if err := s.query(query, args, func(data []byte) error {
+		var request bamboohr.TimeOffRequest
+		if err := json.Unmarshal(data, &request); err != nil {
+			return err
+		}
+		requests = append(requests, request)
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	return requests, nil
+}
+
+// query runs the query and calls scan with the data column of each row
+func (s *Store) query(query string, args []interface{}, scan func(data []byte) error) error {
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		return fmt.Errorf("querying store: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var data []byte
+		if err := rows.Scan(&data); err != nil {
+			return fmt.Errorf("reading store: %w", err)
+		}
+		if err := scan(data); err != nil {
+			return fmt.Errorf("decoding store record: %w", err)
+		}
+	}
+
+	return rows.Err()
+}
+
+// placeholders returns n comma-separated SQL placeholders
+func placeholders(n int) string {
+	return strings.TrimSuffix(strings.Repeat("?,", n), ",")
+}
@@ -0,0 +1,216 @@
+package store
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"bamboohr-mcp-server/bamboohr"
+)
+
+// fakeSource is an in-memory BambooHR API to sync the store from
+type fakeSource struct {
+	employees []bamboohr.Employee
+	requests  map[int][]bamboohr.TimeOffRequest
+	balances  map[int][]bamboohr.TimeOffBalance
+	err       error
+}
+
+func (f *fakeSource) GetEmployeeDirectory() (*bamboohr.EmployeeDirectory, error) {
+	return &bamboohr.EmployeeDirectory{
+		Fields:    []bamboohr.DirectoryField{{ID: "displayName", Type: "text", Name: "Display name"}},
+		Employees: f.employees,
+	}, nil
+}
+
+func (f *fakeSource) GetTimeOffRequests(employeeID int, start, end string) ([]bamboohr.TimeOffRequest, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	return f.requests[employeeID], nil
+}
+
+func (f *fakeSource) GetTimeOffBalance(employeeID int) ([]bamboohr.TimeOffBalance, error) {
+	return f.balances[employeeID], nil
+}
+
+func (f *fakeSource) GetTimeOffTypes() (*bamboohr.TimeOffTypes, error) {
+	return &bamboohr.TimeOffTypes{TimeOffTypes: []bamboohr.TimeOffType{{ID: "78", Name: "Vacation", Units: "days"}}}, nil
+}
+
+// testRequest creates a time-off request for the store tests
+func testRequest(id string, employeeID int, typeName, status, start, end string, amount float64) bamboohr.TimeOffRequest {
+	request := bamboohr.TimeOffRequest{ID: id, EmployeeID: fmt.Sprint(employeeID), Start: start, End: end}
+	request.Type.ID = map[string]string{"Vacation": "78", "Sick": "79"}[typeName]
+	request.Type.Name = typeName
+	request.Status.Status = status
+	request.Amount.Unit = "days"
+	request.Amount.Amount = bamboohr.FlexibleFloat(amount)
+	return request
+}
+
+func newTestSource() *fakeSource {
+	return &fakeSource{
+		employees: []bamboohr.Employee{
+			{ID: "4", DisplayName: "Charlotte Abbott", Department: "Engineering"},
+			{ID: "5", DisplayName: "Ashley Adams", Department: "Sales"},
+		},
+		requests: map[int][]bamboohr.TimeOffRequest{
+			4: {
+				testRequest("1", 4, "Sick", "approved", "2024-03-04", "2024-03-05", 2),
+				testRequest("2", 4, "Vacation", "approved", "2024-08-12", "2024-08-16", 5),
+				testRequest("3", 4, "Sick", "denied", "2024-10-01", "2024-10-01", 1),
+			},
+			5: {
+				testRequest("4", 5, "Sick", "approved", "2024-11-20", "2024-11-20", 1),
+			},
+		},
+		balances: map[int][]bamboohr.TimeOffBalance{
+			4: {{TimeOffType: "78", Name: "Vacation", Units: "days", Balance: 12.5}},
+		},
+	}
+}
+
+func openTestStore(t *testing.T) *Store {
+	t.Helper()
+
+	s, err := Open(filepath.Join(t.TempDir(), "bamboohr.db"))
+	if err != nil {
+		t.Fatalf("Failed to open store: %v", err)
+	}
+	t.Cleanup(func() { s.Close() })
+
+	return s
+}
+
+func TestStore_Sync(t *testing.T) {
+	s := openTestStore(t)
+
+	if _, err := s.SyncedAt(); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound before the first sync, got %v", err)
+	}
+
+	now := time.Date(2025, 1, 2, 3, 0, 0, 0, time.UTC)
+	result, err := s.Sync(newTestSource(), "2024-01-01", "2024-12-31", now)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if result != (SyncResult{Employees: 2, Requests: 4, Balances: 1}) {
+		t.Errorf("Unexpected sync result: %+v", result)
+	}
+
+	syncedAt, err := s.SyncedAt()
+	if err != nil || !syncedAt.Equal(now) {
+		t.Errorf("Expected synced at %s, got %s (%v)", now, syncedAt, err)
+	}
+
+	directory, err := s.EmployeeDirectory()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(directory.Employees) != 2 || len(directory.Fields) != 1 {
+		t.Errorf("Expected 2 employees and 1 field, got %+v", directory)
+	}
+
+	employee, err := s.Employee(5)
+	if err != nil || employee.DisplayName != "Ashley Adams" {
+		t.Errorf("Expected Ashley Adams, got %+v (%v)", employee, err)
+	}
+	if _, err := s.Employee(99); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound for an unknown employee, got %v", err)
+	}
+
+	balances, err := s.TimeOffBalance(4)
+	if err != nil || len(balances) != 1 || balances[0].Balance != 12.5 {
+		t.Errorf("Expected the Vacation balance, got %+v (%v)", balances, err)
+	}
+
+	types, err := s.TimeOffTypes()
+	if err != nil || len(types.TimeOffTypes) != 1 {
+		t.Errorf("Expected 1 time off type, got %+v (%v)", types, err)
+	}
+
+	requests, err := s.TimeOffRequests(4, "2024-08-01", "2024-08-31")
+	if err != nil || len(requests) != 1 || requests[0].ID != "2" {
+		t.Errorf("Expected request 2, got %+v (%v)", requests, err)
+	}
+}
+
+func TestStore_Resync(t *testing.T) {
+	s := openTestStore(t)
+	source := newTestSource()
+
+	if _, err := s.Sync(source, "2024-01-01", "2024-12-31", time.Now()); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	// Request 2 is deleted in BambooHR and employee 5 leaves
+	source.requests[4] = source.requests[4][:1]
+	source.employees = source.employees[:1]
+
+	// A failed sync leaves the store untouched
+	source.err = errors.New("API error 503: Service Unavailable")
+	if _, err := s.Sync(source, "2024-01-01", "2024-12-31", time.Now()); err == nil {
+		t.Fatal("Expected the sync to fail")
+	}
+	if requests, _ := s.TimeOffRequests(4, "", ""); len(requests) != 3 {
+		t.Errorf("Expected a failed sync to keep 3 requests, got %d", len(requests))
+	}
+
+	source.err = nil
+	if _, err := s.Sync(source, "2024-01-01", "2024-12-31", time.Now()); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if requests, _ := s.TimeOffRequests(4, "", ""); len(requests) != 1 {
+		t.Errorf("Expected the deleted requests to be removed, got %d", len(requests))
+	}
+
+	if _, err := s.Employee(5); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected the departed employee to be removed, got %v", err)
+	}
+
+	// The departed employee's history is kept
+	if requests, _ := s.TimeOffRequests(5, "", ""); len(requests) != 1 {
+		t.Errorf("Expected the departed employee's history to be kept, got %d", len(requests))
+	}
+}
+
+func TestStore_SearchTimeOffRequests(t *testing.T) {
+	s := openTestStore(t)
+	if _, err := s.Sync(newTestSource(), "2024-01-01", "2024-12-31", time.Now()); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		filter   RequestFilter
+		expected []string
+	}{
+		{"all", RequestFilter{}, []string{"1", "2", "3", "4"}},
+		{"period", RequestFilter{Start: "2024-08-01", End: "2024-10-31"}, []string{"2", "3"}},
+		{"department", RequestFilter{Department: "engineering"}, []string{"1", "2", "3"}},
+		{"type name", RequestFilter{TimeOffType: "sick", Statuses: []string{"approved"}}, []string{"1", "4"}},
+		{"type ID", RequestFilter{TimeOffType: "78"}, []string{"2"}},
+		{"employees", RequestFilter{EmployeeIDs: []int{5}}, []string{"4"}},
+	}
+
+	for _, tt := range tests {
+		requests, err := s.SearchTimeOffRequests(tt.filter)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", tt.name, err)
+			continue
+		}
+
+		var ids []string
+		for _, request := range requests {
+			ids = append(ids, request.ID)
+		}
+		if fmt.Sprint(ids) != fmt.Sprint(tt.expected) {
+			t.Errorf("%s: expected %v, got %v", tt.name, tt.expected, ids)
+		}
+	}
+}